@@ -0,0 +1,113 @@
+package paiboonizer
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// AcronymMode selects how known abbreviations are read
+type AcronymMode int
+
+const (
+	AcronymSpellOut AcronymMode = iota // Letter by letter: กทม. → gɔɔ-tɔɔ-mɔɔ
+	AcronymExpand                      // Full form: กทม. → grung-têep-má~hǎa-ná~kɔɔn
+)
+
+// Acronym describes a Thai abbreviation and the word it stands for
+type Acronym struct {
+	Expansion string // Thai full form
+	Roman     string // Paiboon romanization of the full form
+}
+
+// acronyms holds common abbreviations, keyed by their dotted spelling
+var acronyms = map[string]Acronym{
+	// Places and institutions
+	"กทม.": {"กรุงเทพมหานคร", "grung-têep-má~hǎa-ná~kɔɔn"},
+	"รพ.":  {"โรงพยาบาล", "roong-pá~yaa-baan"},
+	"ร.ร.": {"โรงเรียน", "roong-riian"},
+	"ม.":   {"มหาวิทยาลัย", "má~hǎa-wít-tá~yaa-lai"},
+	"จ.":   {"จังหวัด", "jang-wàt"},
+	"ต.":   {"ตำบล", "dtam-bon"},
+	"ถ.":   {"ถนน", "tà~nǒn"},
+	"ซ.":   {"ซอย", "sɔɔi"},
+	"ตร.":  {"ตำรวจ", "dtam-rùuat"},
+
+	// Titles
+	"ดร.": {"ด็อกเตอร์", "dɔ́k-dtə̂ə"},
+	"ผอ.": {"ผู้อำนวยการ", "pûu-am-nuuai-gaan"},
+	"สส.": {"สมาชิกสภาผู้แทนราษฎร", "sà~maa-chík-sà~paa-pûu-tɛɛn-râat-sà~dɔɔn"},
+
+	// Time and units
+	"น.":   {"นาฬิกา", "naa-lí~gaa"},
+	"ชม.":  {"ชั่วโมง", "chûua-moong"},
+	"พ.ศ.": {"พุทธศักราช", "pút-tá~sàk-gà~ràat"},
+	"ค.ศ.": {"คริสต์ศักราช", "krít-sàk-gà~ràat"},
+	"กม.":  {"กิโลเมตร", "gì-loo-mét"},
+	"กก.":  {"กิโลกรัม", "gì-loo-gram"},
+
+	// Months
+	"ม.ค.":  {"มกราคม", "mók-gà~raa-kom"},
+	"ก.พ.":  {"กุมภาพันธ์", "gum-paa-pan"},
+	"มี.ค.": {"มีนาคม", "mii-naa-kom"},
+	"เม.ย.": {"เมษายน", "mee-sǎa-yon"},
+	"พ.ค.":  {"พฤษภาคม", "prʉ́t-sà~paa-kom"},
+	"มิ.ย.": {"มิถุนายน", "mí-tù-naa-yon"},
+	"ก.ค.":  {"กรกฎาคม", "gà~rá-gà~daa-kom"},
+	"ส.ค.":  {"สิงหาคม", "sǐng-hǎa-kom"},
+	"ก.ย.":  {"กันยายน", "gan-yaa-yon"},
+	"ต.ค.":  {"ตุลาคม", "dtù-laa-kom"},
+	"พ.ย.":  {"พฤศจิกายน", "prʉ́t-sà~jì-gaa-yon"},
+	"ธ.ค.":  {"ธันวาคม", "tan-waa-kom"},
+}
+
+// maxAcronymLen is the rune length of the longest acronym key
+const maxAcronymLen = 5
+
+// dottedAcronymRegex matches the generic dotted abbreviation shape at the
+// start of a string: groups of 1-3 consonants each followed by a dot
+var dottedAcronymRegex = regexp.MustCompile(`^(?:[ก-ฮ]{1,3}\.)+`)
+
+// LookupAcronym returns the table entry for a dotted abbreviation. The
+// dots are required: undotted forms collide with real words (ชม, กก).
+func LookupAcronym(abbr string) (Acronym, bool) {
+	a, ok := acronyms[abbr]
+	return a, ok
+}
+
+// readAcronym romanizes an abbreviation according to mode. Unknown
+// abbreviations are always spelled out since there is nothing to expand.
+func readAcronym(abbr string, mode AcronymMode) string {
+	if a, ok := LookupAcronym(abbr); ok && mode == AcronymExpand {
+		return norm.NFC.String(a.Roman)
+	}
+	return SpellOut(abbr)
+}
+
+// matchAcronym returns the abbreviation starting at runes[0], if any: the
+// longest table entry first, then the generic dotted shape. A single dotted
+// group (รถ.) is only accepted when it isn't a known word, so that a
+// sentence-final dot doesn't turn words into acronyms.
+func matchAcronym(runes []rune) string {
+	maxLen := maxAcronymLen
+	if maxLen > len(runes) {
+		maxLen = len(runes)
+	}
+	for length := maxLen; length > 1; length-- {
+		candidate := string(runes[:length])
+		if _, ok := acronyms[candidate]; ok {
+			return candidate
+		}
+	}
+	m := dottedAcronymRegex.FindString(string(runes))
+	if m == "" {
+		return ""
+	}
+	if strings.Count(m, ".") == 1 {
+		if _, ok := LookupDictionary(strings.TrimSuffix(m, ".")); ok {
+			return ""
+		}
+	}
+	return m
+}
@@ -0,0 +1,147 @@
+package paiboonizer
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// CoverageIssue is a generated syllable the rules can't romanize cleanly
+type CoverageIssue struct {
+	Thai    string
+	Roman   string
+	Problem string
+}
+
+// syllableParts are the building blocks of AdversarialSyllables: every
+// initial (single consonants, true clusters and silent ห leads), every
+// vowel form of the pattern table and every final
+type syllableParts struct {
+	consonants []string // Single consonant initials
+	initials   []string // All initials
+	forms      []VowelPattern
+	finals     []string
+}
+
+// adversarialParts collects the syllable parts from the engine tables
+func adversarialParts() syllableParts {
+	var parts syllableParts
+	for r := 'ก'; r <= 'ฮ'; r++ {
+		if _, ok := initialConsonants[string(r)]; ok {
+			parts.consonants = append(parts.consonants, string(r))
+		}
+		if f := finalConsonants[string(r)]; f != "" {
+			parts.finals = append(parts.finals, string(r))
+		}
+	}
+	parts.initials = append(parts.initials, parts.consonants...)
+	// The cluster table also holds silent leads and pseudo clusters (สว)
+	for c, roman := range clusters {
+		if !strings.HasPrefix(c, "ห") && !strings.HasPrefix(c, "อ") && isPaiboonInitial(roman) {
+			parts.initials = append(parts.initials, c)
+		}
+	}
+	for s := range sonorants {
+		parts.initials = append(parts.initials, "ห"+s)
+	}
+	sort.Strings(parts.initials)
+
+	seen := make(map[string]bool)
+	for _, p := range thaiVowelPatterns {
+		// K (cluster) and T (tone mark) variants repeat the C forms
+		if strings.ContainsAny(p.pattern, "KT") || seen[p.pattern] {
+			continue
+		}
+		seen[p.pattern] = true
+		parts.forms = append(parts.forms, p)
+	}
+	return parts
+}
+
+// buildSyllable spells a syllable from a vowel form, an initial, a final
+// (closed forms only) and a tone mark ("" for none). The tone mark goes
+// after the initial and any vowel written above or below it. Returns ""
+// for combinations Thai spelling excludes (tone marks with ็).
+func buildSyllable(form, initial, final, tone string) string {
+	if tone != "" && strings.Contains(form, "็") {
+		return ""
+	}
+	var sb strings.Builder
+	runes := []rune(form)
+	placed := false
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != 'C' {
+			sb.WriteRune(runes[i])
+			continue
+		}
+		if placed {
+			sb.WriteString(final)
+			continue
+		}
+		placed = true
+		sb.WriteString(initial)
+		for i+1 < len(runes) && isAboveOrBelowVowel(runes[i+1]) {
+			i++
+			sb.WriteRune(runes[i])
+		}
+		sb.WriteString(tone)
+	}
+	return sb.String()
+}
+
+// AdversarialSyllables returns n well-formed but mostly rare Thai
+// syllables drawn at random from all initials × vowel forms × finals ×
+// tone marks, the combinations real text rarely exercises. The same seed
+// gives the same syllables.
+func AdversarialSyllables(n int, seed int64) []string {
+	ensureDictionaryLoaded()
+	parts := adversarialParts()
+	tones := append([]string{""}, thaiToneMarks...)
+	rng := rand.New(rand.NewSource(seed))
+
+	syllables := make([]string, 0, n)
+	seen := make(map[string]bool)
+	for attempts := 0; len(syllables) < n && attempts < n*10; attempts++ {
+		form := parts.forms[rng.Intn(len(parts.forms))]
+		// Forms spelled with ร after the initial (Cรา, Cรร) take a
+		// single consonant
+		initials := parts.initials
+		if strings.HasPrefix(form.pattern, "Cร") {
+			initials = parts.consonants
+		}
+		final := ""
+		if form.hasFinal {
+			final = parts.finals[rng.Intn(len(parts.finals))]
+		}
+		syl := buildSyllable(form.pattern, initials[rng.Intn(len(initials))], final, tones[rng.Intn(len(tones))])
+		if syl == "" || seen[syl] {
+			continue
+		}
+		seen[syl] = true
+		syllables = append(syllables, syl)
+	}
+	return syllables
+}
+
+// CheckSyllableCoverage romanizes each syllable with the rules alone and
+// reports the ones coming out empty, with Thai letters left over, or
+// breaking Thai phonotactics (see CheckPhonotactics): coverage gaps that
+// a silent "" would otherwise hide.
+func CheckSyllableCoverage(syllables []string) []CoverageIssue {
+	ensureDictionaryLoaded()
+	var issues []CoverageIssue
+	for _, syl := range syllables {
+		roman := ComprehensiveTransliterate(syl)
+		switch {
+		case roman == "":
+			issues = append(issues, CoverageIssue{syl, roman, "empty output"})
+		case containsThai(roman):
+			issues = append(issues, CoverageIssue{syl, roman, "Thai letters in output"})
+		default:
+			for _, p := range CheckPhonotactics(roman) {
+				issues = append(issues, CoverageIssue{syl, roman, p.String()})
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,30 @@
+package paiboonizer
+
+import "unicode/utf8"
+
+// AlignedSpan maps a part of the input text to the part of the
+// romanization written for it, for reading UIs that highlight both sides
+// and karaoke timing. Offsets are in runes, ends excluded; a particle
+// marked by Options.Particles spans its parentheses or tag.
+type AlignedSpan struct {
+	Text       string // The input text of the span: a Thai word or a non-Thai run
+	Start, End int    // Position in the input text
+	// RomanStart and RomanEnd are the position of its romanization in
+	// TextResult.Roman
+	RomanStart, RomanEnd int
+}
+
+// romanOffsetsToRunes converts the Roman offsets of spans from bytes, as
+// recorded while writing roman, to runes. Spans are in increasing order.
+func romanOffsetsToRunes(roman string, spans []AlignedSpan) {
+	bytePos, runePos := 0, 0
+	toRunes := func(offset int) int {
+		runePos += utf8.RuneCountInString(roman[bytePos:offset])
+		bytePos = offset
+		return runePos
+	}
+	for i := range spans {
+		spans[i].RomanStart = toRunes(spans[i].RomanStart)
+		spans[i].RomanEnd = toRunes(spans[i].RomanEnd)
+	}
+}
@@ -0,0 +1,93 @@
+package paiboonizer
+
+import "sort"
+
+// AnnotationCandidate is an unknown word proposed for human romanization
+type AnnotationCandidate struct {
+	Word  string
+	Count int // Occurrences in the material the word was collected from
+	// Uncertainty estimates how likely the rules are to get the word
+	// wrong, from 0.1 to 1 (see wordUncertainty)
+	Uncertainty float64
+	Score       float64 // Count × Uncertainty
+	Guess       string  // Current rule output, a starting point for the annotator
+}
+
+// SelectForAnnotation ranks words by frequency × uncertainty so that
+// dictionary work goes where it moves accuracy most: a frequent word the
+// rules handle confidently and a rare word they can't read both rank below
+// a frequent word they can't read. counts maps words (typically collected
+// from corpus failures) to their number of occurrences; words already in
+// the official dictionary are ignored. Returns the top n candidates, all
+// of them if n <= 0.
+func SelectForAnnotation(counts map[string]int, n int) []AnnotationCandidate {
+	ensureDictionaryLoaded()
+	candidates := []AnnotationCandidate{}
+	for word, count := range counts {
+		if _, ok := dictionary[word]; ok || count <= 0 {
+			continue
+		}
+		u := wordUncertainty(word)
+		candidates = append(candidates, AnnotationCandidate{
+			Word:        word,
+			Count:       count,
+			Uncertainty: u,
+			Score:       float64(count) * u,
+			Guess:       ComprehensiveTransliterate(word),
+		})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Word < candidates[j].Word
+	})
+	if n > 0 && len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	return candidates
+}
+
+// wordUncertainty scores how unsure the rules are about word:
+//   - the two rule engines (maximal match and syllable parser) disagree:
+//     fully, or only on tones for half the weight
+//   - share of syllables whose rule output breaks Thai phonotactics
+//   - share of syllables missing from the syllable dictionary
+//
+// An Opus dictionary entry halves the result since a proposal exists. The
+// result is kept at 0.1 or above so that frequency always counts.
+func wordUncertainty(word string) float64 {
+	u := 0.0
+	// Syllable separators differ between the engines and don't count
+	comprehensive := romanSyllableSep.ReplaceAllString(ComprehensiveTransliterate(word), "")
+	parser := romanSyllableSep.ReplaceAllString(TransliterateWord(word), "")
+	switch {
+	case stripRomanTones(comprehensive) != stripRomanTones(parser):
+		u += 0.4
+	case comprehensive != parser:
+		u += 0.2
+	}
+
+	syllables := ExtractSyllables(word)
+	if len(syllables) > 0 {
+		invalid, unknown := 0, 0
+		for _, syl := range syllables {
+			if CheckPhonotactics(ruleTransliterateSyllable(syl)) != nil {
+				invalid++
+			}
+			if _, ok := syllableDict[syl]; !ok {
+				unknown++
+			}
+		}
+		u += 0.3 * float64(invalid) / float64(len(syllables))
+		u += 0.3 * float64(unknown) / float64(len(syllables))
+	}
+
+	if _, ok := opusDictionary[word]; ok {
+		u /= 2
+	}
+	if u < 0.1 {
+		u = 0.1
+	}
+	return u
+}
@@ -0,0 +1,17 @@
+package paiboonizer
+
+// ToASCII renders a romanization without the glyphs systems limited to
+// ASCII can't show: ʉ, ɛ, ɔ and ə become ue, ae, aw and oe, their long
+// forms ʉʉ, ɛɛ, ɔɔ and əə uee, aee, aaw and oee, and each tone mark
+// becomes a digit after its syllable (1 low, 2 falling, 3 high, 4 rising:
+// sà~wàt-dii → sa1~wat1-dii, kʉ̂n → kuen2, mʉʉang → mueeang). Other marks
+// are dropped; text in other scripts is left as is. It is the "ascii"
+// Scheme.
+func ToASCII(roman string) string {
+	return asciiScheme.Render(roman)
+}
+
+// isASCIILetter reports whether r is an ASCII letter
+func isASCIILetter(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
@@ -0,0 +1,34 @@
+package paiboonizer
+
+import "testing"
+
+func TestASCIISchemes(t *testing.T) {
+	tests := []struct {
+		scheme, roman, want string
+	}{
+		{"ascii", "sà~wàt-dii", "sa1~wat1-dii"},
+		{"ascii", "mʉʉang", "mueeang"},
+		{"ascii", "kʉ̂n", "kuen2"},
+		{"ascii", "pʉ̂ʉan", "pueean2"},
+		{"ascii", "Mʉʉang", "Mueeang"},
+		{"ascii", "kɛ̂ɛ tɔ̌ɔng dəən", "kaee2 taawng4 doeen"},
+		{"ime", "mʉʉang", "meuuang0"},
+		{"ime", "kɛ̂ɛ tɔ̌ɔng dəən", "kaee2 toorng4 deern0"},
+	}
+	for _, tt := range tests {
+		s, _ := LookupScheme(tt.scheme)
+		if got := s.Render(tt.roman); got != tt.want {
+			t.Errorf("%s Render(%q) = %q, want %q", tt.scheme, tt.roman, got, tt.want)
+		}
+	}
+
+	opts := DefaultOptions()
+	opts.ASCII = true
+	if got := TransliterateText("เมือง", opts); got != "mueeang" {
+		t.Errorf("TransliterateText(เมือง) in ASCII = %q, want mueeang", got)
+	}
+	opts.Scheme = "ime"
+	if got := TransliterateText("เมือง", opts); got != "meuuang0" {
+		t.Errorf("TransliterateText(เมือง) in ime = %q, want meuuang0", got)
+	}
+}
@@ -0,0 +1,154 @@
+package paiboonizer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// BreakerState is the state of the circuit breaker around pythainlp
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // Calls go to pythainlp
+	BreakerOpen                         // Calls are served by rules until the cool-down ends
+	BreakerHalfOpen                     // A single probe call is let through
+)
+
+// String returns the state name
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// errCircuitOpen is returned internally when the breaker rejects a call
+var errCircuitOpen = errors.New("pythainlp circuit breaker is open")
+
+// breaker opens after threshold consecutive failures, stays open for
+// cooldown, then lets one probe through: a successful probe closes it, a
+// failed one opens it again. A nil *breaker allows everything.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	state     BreakerState
+	openedAt  time.Time
+	probing   bool
+	metrics   Metrics
+}
+
+// WithCircuitBreaker stops calling pythainlp after threshold consecutive
+// failures and serves rule-based results for cooldown before probing the
+// service again. The state is exported as the MetricBreakerState gauge.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) ManagerOption {
+	return func(m *Manager) {
+		if threshold <= 0 {
+			m.breaker = nil
+			return
+		}
+		m.breaker = &breaker{threshold: threshold, cooldown: cooldown}
+	}
+}
+
+// BreakerState returns the current state of the Manager's circuit breaker.
+// Always BreakerClosed when no breaker is configured.
+func (m *Manager) BreakerState() BreakerState {
+	if m.breaker == nil {
+		return BreakerClosed
+	}
+	m.breaker.mu.Lock()
+	defer m.breaker.mu.Unlock()
+	return m.breaker.state
+}
+
+// allow reports whether a call may go to pythainlp
+func (b *breaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.setState(BreakerHalfOpen)
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		// Only one probe at a time
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// success records a successful call
+func (b *breaker) success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.probing = false
+	if b.state != BreakerClosed {
+		b.setState(BreakerClosed)
+	}
+}
+
+// failure records a failed call and opens the circuit when needed
+func (b *breaker) failure() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.openedAt = time.Now()
+		if b.state != BreakerOpen {
+			b.metrics.IncCounter(MetricBreakerOpened)
+		}
+		b.setState(BreakerOpen)
+	}
+}
+
+// record updates the breaker with the outcome of a call made under parent.
+// Failures caused by the caller's own context don't count against
+// pythainlp, but they release a pending probe.
+func (b *breaker) record(parent context.Context, err error) {
+	if b == nil {
+		return
+	}
+	switch {
+	case err == nil:
+		b.success()
+	case parent.Err() != nil:
+		b.mu.Lock()
+		b.probing = false
+		b.mu.Unlock()
+	default:
+		b.failure()
+	}
+}
+
+// setState updates the state and the exported gauge; b.mu must be held
+func (b *breaker) setState(state BreakerState) {
+	b.state = state
+	b.metrics.SetGauge(MetricBreakerState, float64(state))
+}
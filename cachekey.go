@@ -0,0 +1,141 @@
+package paiboonizer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// rulesVersion must be bumped whenever a change to the rule engine alters
+// output, so that keys from CacheKey stop matching stale entries. Dictionary
+// changes are picked up by the checksum automatically.
+const rulesVersion = 17
+
+var (
+	dictionaryChecksum     string
+	dictionaryChecksumOnce sync.Once
+)
+
+// DictionaryChecksum returns a hex SHA-256 over every lookup table the
+// transliteration depends on: the vocabulary and Opus dictionaries, the
+// syllable dictionary, special cases, final cluster exceptions, royal
+// vocabulary, acronyms and the glosses of VocabGlosser.
+func DictionaryChecksum() string {
+	dictionaryChecksumOnce.Do(func() {
+		ensureDictionaryLoaded()
+		h := sha256.New()
+		tables := []struct {
+			name string
+			m    map[string]string
+		}{
+			{"dictionary", dictionary.romanizations()},
+			{"syllables", syllableDict},
+			{"opus", opusDictionary.romanizations()},
+			{"special", specialCasesGlobal},
+			{"final clusters", finalClusterExceptions},
+			{"royal", royalVocabulary},
+			{"glosses", vocabGlosses},
+		}
+		for _, t := range tables {
+			hashTable(h, t.name, t.m)
+		}
+		expansions := make(map[string]string, len(acronyms))
+		for abbr, a := range acronyms {
+			expansions[abbr] = a.Expansion + "\t" + a.Roman
+		}
+		hashTable(h, "acronyms", expansions)
+		dictionaryChecksum = hex.EncodeToString(h.Sum(nil))
+	})
+	return dictionaryChecksum
+}
+
+// hashTable writes m to w in key order
+func hashTable[V any](w io.Writer, name string, m map[string]V) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(w, "[%s:%d]\n", name, len(keys))
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s\t%v\n", k, m[k])
+	}
+}
+
+// CacheKey returns a stable key for the transliteration of text under opts.
+// The key changes whenever the dictionaries, the rule engine version, the
+// vowel patterns added at runtime or any option changes, so host
+// applications can keep persistent caches that invalidate correctly when
+// paiboonizer is upgraded.
+func CacheKey(text string, opts Options) string {
+	return CacheKeyFingerprint(text, OptionsFingerprint(opts))
+}
+
+// OptionsFingerprint returns a hex SHA-256 of opts: every field, since
+// they all change the output, hashed by value. The tables (Overrides,
+// ConnectedSpeech, Frequency, Sensitive and a GlossMap) are hashed entry
+// by entry, so this is the part of CacheKey that costs as much as they
+// are large; hosts keying many texts under the same options compute it
+// once and call CacheKeyFingerprint, and Transliterator.Fingerprint keeps
+// it up to date. Any other Glosser is identified by its Name when it is a
+// NamedGlosser and by its type otherwise.
+func OptionsFingerprint(opts Options) string {
+	h := sha256.New()
+	// A field added to Options has to be added here
+	fmt.Fprintf(h, "SpellOut=%t\nDetectAcronyms=%t\nAcronyms=%d\nRoyalVocabulary=%t\n",
+		opts.SpellOut, opts.DetectAcronyms, opts.Acronyms, opts.RoyalVocabulary)
+	fmt.Fprintf(h, "PreserveWhitespace=%t\nParticles=%d\nPunctuate=%t\nOnFailure=%d\nJoin=%d\n",
+		opts.PreserveWhitespace, opts.Particles, opts.Punctuate, opts.OnFailure, opts.Join)
+	fmt.Fprintf(h, "Separator=%d/%d\nASCII=%t\nToneless=%t\nScheme=%q\nToneStyle=%d\nOutputForm=%d\n",
+		opts.Separator.Mark, opts.Separator.Scope, opts.ASCII, opts.Toneless, opts.Scheme, opts.ToneStyle, opts.OutputForm)
+	fmt.Fprintf(h, "FixLayoutTypos=%t\nForeign=%d\nDictionary=%d\nEngine=%d\nNormalizeInput=%t\n",
+		opts.FixLayoutTypos, opts.Foreign, opts.Dictionary, opts.Engine, opts.NormalizeInput)
+	fmt.Fprintf(h, "SensitivePolicy=%d\nLayers=%t%d\n", opts.SensitivePolicy, opts.Layers != nil, opts.Layers)
+	hashOptionalTable(h, "ConnectedSpeech", opts.ConnectedSpeech)
+	hashOptionalTable(h, "Frequency", opts.Frequency)
+	hashOptionalTable(h, "Sensitive", opts.Sensitive)
+	switch g := opts.Glosser.(type) {
+	case nil:
+		fmt.Fprintf(h, "Glosser=nil\n")
+	case GlossMap:
+		hashTable(h, "Glosser", g)
+	case NamedGlosser:
+		fmt.Fprintf(h, "Glosser=%q\n", g.Name())
+	default:
+		fmt.Fprintf(h, "Glosser=%T\n", g)
+	}
+	hashTable(h, "Overrides.lines", opts.Overrides.lines)
+	words := make(map[string]string, len(opts.Overrides.words))
+	for word, o := range opts.Overrides.words {
+		words[word] = strings.Join(o.parts, "|") + "\t" + strings.Join(o.romans, "|")
+	}
+	hashTable(h, "Overrides.words", words)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashOptionalTable is hashTable for the tables of Options, which turn a
+// behaviour off when nil
+func hashOptionalTable[V any](w io.Writer, name string, m map[string]V) {
+	if m == nil {
+		fmt.Fprintf(w, "%s=nil\n", name)
+		return
+	}
+	hashTable(w, name, m)
+}
+
+// CacheKeyFingerprint is CacheKey for the options of fingerprint, as
+// returned by OptionsFingerprint or Transliterator.Fingerprint
+func CacheKeyFingerprint(text, fingerprint string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "paiboonizer/%d\n%s\n%s\n", rulesVersion, DictionaryChecksum(), fingerprint)
+	// Patterns added at runtime change the output too
+	if patterns := vowelPatternsFingerprint(); patterns != "" {
+		fmt.Fprintf(h, "patterns\n%s", patterns)
+	}
+	h.Write([]byte(text))
+	return hex.EncodeToString(h.Sum(nil))
+}
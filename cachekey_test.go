@@ -0,0 +1,71 @@
+package paiboonizer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// pointerGlosser is a Glosser without a Name whose instances are pointers
+type pointerGlosser struct{ glosses map[string]string }
+
+func (g *pointerGlosser) Gloss(word string) (string, bool) {
+	gloss, ok := g.glosses[word]
+	return gloss, ok
+}
+
+func TestOptionsFingerprintGlosser(t *testing.T) {
+	fingerprint := func(g Glosser) string {
+		opts := DefaultOptions()
+		opts.Glosser = g
+		return OptionsFingerprint(opts)
+	}
+	if fingerprint(&pointerGlosser{}) != fingerprint(&pointerGlosser{}) {
+		t.Error("two identical glossers give different fingerprints")
+	}
+	if fingerprint(GlossMap{"ดี": "good"}) == fingerprint(GlossMap{"ดี": "well"}) {
+		t.Error("GlossMaps with different glosses give the same fingerprint")
+	}
+	if fingerprint(VocabGlosser()) == fingerprint(nil) {
+		t.Error("VocabGlosser gives the fingerprint of no glosser")
+	}
+}
+
+func TestOptionsFingerprintCoversEveryField(t *testing.T) {
+	overrides, err := ReadOverrides(strings.NewReader("ดี\tdee\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := OptionsFingerprint(Options{})
+	typ := reflect.TypeOf(Options{})
+	for i := 0; i < typ.NumField(); i++ {
+		var opts Options
+		field := reflect.ValueOf(&opts).Elem().Field(i)
+		switch typ.Field(i).Name {
+		case "Separator":
+			opts.Separator.Mark = 1
+		case "Overrides":
+			opts.Overrides = overrides
+		case "Glosser":
+			opts.Glosser = GlossMap{}
+		default:
+			switch field.Kind() {
+			case reflect.Bool:
+				field.SetBool(true)
+			case reflect.Int:
+				field.SetInt(1)
+			case reflect.String:
+				field.SetString("x")
+			case reflect.Map:
+				field.Set(reflect.MakeMap(field.Type()))
+			case reflect.Slice:
+				field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+			default:
+				t.Fatalf("field %s of kind %v: teach this test to set it", typ.Field(i).Name, field.Kind())
+			}
+		}
+		if OptionsFingerprint(opts) == base {
+			t.Errorf("field %s doesn't change the fingerprint", typ.Field(i).Name)
+		}
+	}
+}
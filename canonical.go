@@ -0,0 +1,145 @@
+package paiboonizer
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Canonicalization is a dictionary entry whose romanization was rewritten
+// at load to the conventions the rules write
+type Canonicalization struct {
+	Thai     string
+	File     string // Embedded file the entry was read from
+	Original string
+	Roman    string   // Romanization used
+	Changes  []string // What was rewritten, one description per change
+}
+
+// canonicalizations lists the entries rewritten by canonicalRoman during
+// loadDictionary
+var canonicalizations []Canonicalization
+
+// legacySymbols maps the vowel symbols of older Paiboon editions and their
+// look-alikes to the current ones, in NFD
+var legacySymbols = []struct{ old, new string }{
+	{"ǝ", "ə"}, {"ɘ", "ə"}, {"ɜ", "ə"},
+	{"ɯ", "ʉ"}, {"ü", "ʉ"},
+	{"ɒ", "ɔ"},
+}
+
+// longDiphthongs are the diphthongs older editions wrote with a single
+// first letter (sǐa, rʉa, dtua), with the Thai that spells their long
+// form. The short forms (เ-ียะ, เ-ือะ, -ัวะ) are written with ะ.
+var longDiphthongs = []struct {
+	first rune
+	thai  []string // Spelled with one of these
+}{
+	{'i', []string{"ีย"}},
+	{'ʉ', []string{"ือ"}},
+	{'u', []string{"ัว", "ว"}},
+}
+
+// canonicalRoman rewrites a romanization to the current conventions: NFC,
+// current vowel symbols and doubled long diphthongs (sǐa → sǐia) when thai
+// has no ะ to spell them short. Returns the description of each change
+// beyond NFC, which only changes the encoding.
+func canonicalRoman(thai, roman string) (string, []string) {
+	s := norm.NFD.String(roman)
+	var changes []string
+	for _, ls := range legacySymbols {
+		if strings.Contains(s, ls.old) {
+			s = strings.ReplaceAll(s, ls.old, ls.new)
+			changes = append(changes, norm.NFC.String(ls.old)+" → "+ls.new)
+		}
+	}
+	if !strings.ContainsRune(thai, 'ะ') {
+		toneless := stripToneMarks(thai)
+		for _, d := range longDiphthongs {
+			if !containsAny(toneless, d.thai) {
+				continue
+			}
+			if doubled, ok := doubleDiphthong(s, d.first); ok {
+				s = doubled
+				changes = append(changes, string(d.first)+"a → "+string(d.first)+string(d.first)+"a")
+			}
+		}
+	}
+	return norm.NFC.String(s), changes
+}
+
+// doubleDiphthong doubles the first letter of each first+a diphthong of
+// the NFD romanization s written with a single one. The tone mark stays on
+// the first letter, as Paiboon writes it (sǐia).
+func doubleDiphthong(s string, first rune) (string, bool) {
+	runes := []rune(s)
+	var out []rune
+	changed := false
+	for i := 0; i < len(runes); i++ {
+		out = append(out, runes[i])
+		if runes[i] != first || previousLetter(runes, i) == first {
+			continue
+		}
+		j := i + 1
+		for j < len(runes) && unicode.Is(unicode.Mn, runes[j]) {
+			j++
+		}
+		if j < len(runes) && runes[j] == 'a' {
+			out = append(out, runes[i+1:j]...)
+			out = append(out, first)
+			i = j - 1
+			changed = true
+		}
+	}
+	return string(out), changed
+}
+
+// previousLetter returns the letter before runes[i], skipping combining
+// marks, 0 at the start
+func previousLetter(runes []rune, i int) rune {
+	for i--; i >= 0; i-- {
+		if !unicode.Is(unicode.Mn, runes[i]) {
+			return runes[i]
+		}
+	}
+	return 0
+}
+
+// containsAny reports whether s contains one of subs
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalEntry returns the canonical romanization of a dictionary entry
+// read from file, recording it if it was rewritten
+func canonicalEntry(thai, roman, file string) string {
+	canonical, changes := canonicalRoman(thai, roman)
+	if len(changes) > 0 {
+		canonicalizations = append(canonicalizations, Canonicalization{thai, file, roman, canonical, changes})
+	}
+	return canonical
+}
+
+// DictionaryCanonicalizations returns the dictionary entries rewritten at
+// load to the current Paiboon conventions (vowel symbols of older editions,
+// undoubled long diphthongs), sorted by Thai then file. Entries only
+// normalized to NFC aren't listed. The fix belongs in the data files; the
+// list shows which entries need it.
+func DictionaryCanonicalizations() []Canonicalization {
+	ensureDictionaryLoaded()
+	list := append([]Canonicalization(nil), canonicalizations...)
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Thai != list[j].Thai {
+			return list[i].Thai < list[j].Thai
+		}
+		return list[i].File < list[j].File
+	})
+	return list
+}
@@ -0,0 +1,160 @@
+package paiboonizer
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Orthographic categories: spelling features the rules handle with a
+// dedicated path, so that accuracy can be followed separately for each.
+// Dictionary entries are tagged with theirs at load time (EntriesByTag).
+const (
+	CategoryHoLead  = "ho-lead" // Silent ห before a sonorant (หมา, ไหน)
+	CategoryOLead   = "o-lead"  // Silent อ before ย (อยู่, อยาก)
+	CategoryCluster = "cluster" // True initial cluster (กลาง, ปลา, ความ)
+	CategoryUea     = "uea"     // เ-ือ vowel (เมือง, เรื่อง)
+	CategoryIa      = "ia"      // เ-ีย vowel (เรียน, เสียง)
+	CategorySilent  = "silent"  // Silenced consonant with ์ (จันทร์, สัตว์)
+	CategoryRoHan   = "ro-han"  // Doubled ร (ธรรม, สรรพ)
+	CategoryRue     = "rue"     // ฤ or ฦ (ฤดู, อังกฤษ)
+	// Likely Pali or Sanskrit loan (ธรรม, ประโยชน์, คุณ, พันธุ์), see
+	// IsIndicLoan
+	CategoryIndicLoan = "indic-loan"
+)
+
+// indicLetters are the letters found almost only in Pali and Sanskrit loans
+const indicLetters = "ฆฌฎฏฐฑฒณธภศษฬฤฦ"
+
+// tagIndex maps categories to the dictionary entries (official and Opus)
+// tagged with them, sorted by Thai
+var tagIndex = make(map[string][]Entry)
+
+// tagDictionaryEntries tags every dictionary entry with its orthographic
+// categories, once the dictionaries are loaded
+func tagDictionaryEntries() {
+	for _, e := range dictionaryEntries() {
+		for _, c := range orthographicCategories(stripSpecialMarkers(e.Thai)) {
+			tagIndex[c] = append(tagIndex[c], e)
+		}
+	}
+}
+
+// EntriesByTag returns the dictionary entries tagged with an orthographic
+// category (CategoryHoLead, ...), official and Opus ones alike, sorted by
+// Thai. Useful to test or review one spelling feature at a time.
+func EntriesByTag(tag string) []Entry {
+	ensureDictionaryLoaded()
+	return append([]Entry(nil), tagIndex[tag]...)
+}
+
+// TagsOf returns the orthographic categories of a word, sorted
+func TagsOf(word string) []string {
+	ensureDictionaryLoaded()
+	return orthographicCategories(word)
+}
+
+// inCategories reports whether word falls in at least one of categories,
+// or categories is empty
+func inCategories(word string, categories []string) bool {
+	if len(categories) == 0 {
+		return true
+	}
+	for _, c := range orthographicCategories(stripSpecialMarkers(word)) {
+		if slices.Contains(categories, c) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	ueaRegex = regexp.MustCompile(`เ[ก-ฮ]{1,2}ื[่้๊๋]?อ`)
+	iaRegex  = regexp.MustCompile(`เ[ก-ฮ]{1,2}ี[่้๊๋]?ย`)
+)
+
+// orthographicCategories returns the categories the spelling of word falls
+// in, sorted. A word may fall in several or none.
+func orthographicCategories(word string) []string {
+	var categories []string
+	runes := []rune(word)
+	for i, r := range runes {
+		if r == 'ห' && i+1 < len(runes) && sonorants[string(runes[i+1])] {
+			categories = append(categories, CategoryHoLead)
+			break
+		}
+	}
+	if strings.HasPrefix(word, "อย") {
+		categories = append(categories, CategoryOLead)
+	}
+	// Clusters only count at the start of a syllable: at the start of the
+	// word or after a leading vowel, so that มกราคม doesn't qualify. The
+	// cluster table also holds the silent ห and อ leads, counted above.
+	for i := 0; i+1 < len(runes); i++ {
+		if (i > 0 && !isLeadingVowel(string(runes[i-1]))) || runes[i] == 'ห' || runes[i] == 'อ' {
+			continue
+		}
+		if _, ok := clusters[string(runes[i:i+2])]; ok && !(i+2 < len(runes) && runes[i+2] == '์') {
+			categories = append(categories, CategoryCluster)
+			break
+		}
+	}
+	if ueaRegex.MatchString(word) {
+		categories = append(categories, CategoryUea)
+	}
+	if iaRegex.MatchString(word) {
+		categories = append(categories, CategoryIa)
+	}
+	if strings.ContainsRune(word, '์') {
+		categories = append(categories, CategorySilent)
+	}
+	if strings.Contains(word, "รร") {
+		categories = append(categories, CategoryRoHan)
+	}
+	if strings.ContainsAny(word, "ฤฦ") {
+		categories = append(categories, CategoryRue)
+	}
+	if IsIndicLoan(word) {
+		categories = append(categories, CategoryIndicLoan)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// CategoryAccuracy is the dictionary test accuracy on the entries of one
+// orthographic category
+type CategoryAccuracy struct {
+	Category string
+	Total    int
+	Passed   int
+	Accuracy float64
+}
+
+// RunCategoryTest runs the dictionary test on the single-word official
+// entries of each orthographic category (CategoryHoLead, ...), so that a
+// rule change improving one category at the expense of another shows.
+// Results are sorted by category.
+func RunCategoryTest(mode TestMode) []CategoryAccuracy {
+	ensureDictionaryLoaded()
+	results := make([]CategoryAccuracy, 0, len(tagIndex))
+	for category, entries := range tagIndex {
+		acc := CategoryAccuracy{Category: category}
+		for _, e := range entries {
+			if e.Source != "official" || strings.Contains(e.Thai, " ") {
+				continue
+			}
+			acc.Total++
+			if dictionaryTestMatches(dictionaryTestTransliterate(mode, e.Thai), dictionary[e.Thai].Roman, false) {
+				acc.Passed++
+			}
+		}
+		if acc.Total == 0 {
+			continue
+		}
+		acc.Accuracy = float64(acc.Passed) * 100 / float64(acc.Total)
+		results = append(results, acc)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Category < results[j].Category })
+	return results
+}
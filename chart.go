@@ -0,0 +1,193 @@
+package paiboonizer
+
+import (
+	"html/template"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ChartConsonant is a row of the consonant table of the reference chart
+type ChartConsonant struct {
+	Letter  string
+	Name    string // Letter name, e.g. "gɔɔ gài"
+	Class   string // Tone class: high, mid, low or "" (ฤ, ฦ)
+	Initial string // Paiboon value as an initial
+	Final   string // Paiboon value as a final, "" if silent
+	Example Example
+}
+
+// ChartVowel is a row of the vowel table of the reference chart
+type ChartVowel struct {
+	Form    string // Thai vowel form with - standing for consonants
+	Paiboon string
+	Example Example
+}
+
+// Example is a dictionary word illustrating a chart row
+type Example struct {
+	Thai  string
+	Roman string
+}
+
+// Chart is the learner-facing reference chart built from the code tables
+type Chart struct {
+	Consonants []ChartConsonant
+	Vowels     []ChartVowel
+}
+
+// BuildChart assembles the reference chart from the tables the engine
+// actually uses (initialConsonants, finalConsonants, tone classes and the
+// vowel patterns), with examples pulled from the dictionary, so that the
+// documentation can't drift from the behaviour.
+func BuildChart() Chart {
+	ensureDictionaryLoaded()
+	words := sortedDictionaryWords()
+	chart := Chart{}
+
+	for r := 'ก'; r <= 'ฮ'; r++ {
+		letter := string(r)
+		initial, ok := initialConsonants[letter]
+		if !ok {
+			continue
+		}
+		c := ChartConsonant{
+			Letter:  letter,
+			Class:   toneClassName(letter),
+			Initial: initial,
+			Final:   finalConsonants[letter],
+		}
+		c.Name, _ = LetterName(r)
+		for _, w := range words {
+			if strings.HasPrefix(w, letter) {
+				c.Example = Example{w, norm.NFC.String(dictionary[w].Roman)}
+				break
+			}
+		}
+		chart.Consonants = append(chart.Consonants, c)
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range thaiVowelPatterns {
+		// K (cluster) and T (tone mark) variants repeat the C forms
+		if strings.ContainsAny(p.pattern, "KT") {
+			continue
+		}
+		form := strings.ReplaceAll(p.pattern, "C", "-")
+		if seen[form] {
+			continue
+		}
+		seen[form] = true
+		v := ChartVowel{Form: form, Paiboon: p.paiboon}
+		re := vowelPatternRegex(p.pattern)
+		for _, w := range words {
+			if re.MatchString(stripToneMarks(w)) {
+				v.Example = Example{w, norm.NFC.String(dictionary[w].Roman)}
+				break
+			}
+		}
+		chart.Vowels = append(chart.Vowels, v)
+	}
+	return chart
+}
+
+// WriteChartHTML renders BuildChart as a standalone, printable HTML page
+func WriteChartHTML(w io.Writer) error {
+	return chartTemplate.Execute(w, BuildChart())
+}
+
+// chartExcludedExamples are dictionary words unfit for a learner chart
+var chartExcludedExamples = map[string]bool{
+	"ควย": true,
+}
+
+// sortedDictionaryWords returns the dictionary keys usable as examples,
+// shortest first, so that examples are the simplest words available.
+// Abbreviations and single letters are left out.
+func sortedDictionaryWords() []string {
+	words := make([]string, 0, len(dictionary))
+	for w := range dictionary {
+		if len([]rune(w)) < 2 || strings.Contains(w, ".") || chartExcludedExamples[w] {
+			continue
+		}
+		words = append(words, w)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		li, lj := len([]rune(words[i])), len([]rune(words[j]))
+		if li != lj {
+			return li < lj
+		}
+		return words[i] < words[j]
+	})
+	return words
+}
+
+// vowelPatternRegex turns a VowelPattern pattern into a regexp matching a
+// whole word without tone marks
+func vowelPatternRegex(pattern string) *regexp.Regexp {
+	expr := strings.ReplaceAll(regexp.QuoteMeta(pattern), "C", "[ก-ฮ]")
+	return regexp.MustCompile("^" + expr + "$")
+}
+
+// stripToneMarks removes the four tone marks from s
+func stripToneMarks(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isToneMark(string(r)) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// toneClassName returns the tone class of a consonant, "" for letters
+// outside the three classes (ฤ, ฦ)
+func toneClassName(letter string) string {
+	switch {
+	case highClass[letter]:
+		return "high"
+	case midClass[letter]:
+		return "mid"
+	case lowClass[letter]:
+		return "low"
+	}
+	return ""
+}
+
+var chartTemplate = template.Must(template.New("chart").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Paiboon romanization chart</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #999; padding: 0.2em 0.6em; text-align: left; }
+th { background: #eee; }
+.thai { font-size: 1.3em; }
+.high { color: #b00; } .mid { color: #060; } .low { color: #00b; }
+@media print { body { margin: 0; } table { page-break-inside: auto; } tr { page-break-inside: avoid; } }
+</style>
+</head>
+<body>
+<h1>Paiboon romanization chart</h1>
+<p>Generated from the paiboonizer rule tables; examples come from the embedded dictionary.</p>
+<h2>Consonants</h2>
+<table>
+<tr><th>Letter</th><th>Name</th><th>Class</th><th>Initial</th><th>Final</th><th>Example</th></tr>
+{{- range .Consonants}}
+<tr><td class="thai">{{.Letter}}</td><td>{{.Name}}</td><td class="{{.Class}}">{{.Class}}</td><td>{{.Initial}}</td><td>{{if .Final}}{{.Final}}{{else}}–{{end}}</td><td><span class="thai">{{.Example.Thai}}</span> {{.Example.Roman}}</td></tr>
+{{- end}}
+</table>
+<h2>Vowels</h2>
+<table>
+<tr><th>Form</th><th>Paiboon</th><th>Example</th></tr>
+{{- range .Vowels}}
+<tr><td class="thai">{{.Form}}</td><td>{{.Paiboon}}</td><td><span class="thai">{{.Example.Thai}}</span> {{.Example.Roman}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
@@ -1,21 +1,22 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
-	"golang.org/x/text/unicode/norm"
 
 	"github.com/tassa-yoniso-manasi-karoto/go-pythainlp"
 	"github.com/tassa-yoniso-manasi-karoto/paiboonizer"
+	"github.com/tassa-yoniso-manasi-karoto/paiboonizer/corpustest"
 
 	_ "github.com/tassa-yoniso-manasi-karoto/translitkit"
 	"github.com/tassa-yoniso-manasi-karoto/translitkit/common"
@@ -23,25 +24,114 @@ import (
 
 const failuresFile = "testing_files/failures_translitkit.txt"
 
-// testPair represents a matched pair of Thai input and expected transliteration
-type testPair struct {
-	name          string
-	inputLines    []string
-	expectedLines []string
-}
-
-// corpusFailure represents a single failed transliteration
-type corpusFailure struct {
-	file     string
-	lineNum  int
-	input    string
-	expected string
-	got      string
-}
-
 func main() {
+	// Verbs that don't need pythainlp run before the test suite starts it
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "inventory":
+			// Syllable inventory as CSV on stdout
+			if err := paiboonizer.WriteSyllableInventoryCSV(os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing inventory: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "chart":
+			// Learner reference chart as HTML on stdout
+			if err := paiboonizer.WriteChartHTML(os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing chart: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "csv":
+			// Romanize selected columns of a CSV/TSV file to stdout
+			if err := runCSV(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "roundtrip":
+			// Dictionary entries whose romanization doesn't map back
+			printRoundTripResults(paiboonizer.RunRoundTripTest())
+			return
+		case "corpus":
+			// Aligned parallel corpus TSV on stdout
+			if err := runCorpusBuild(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "review":
+			// Subtitle lines whose romanization disagrees with the engine
+			if err := runReview(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "gate":
+			// Lint, sampled dictionary test and corpus test against thresholds
+			pass, err := runQualityGate(os.Args[2:])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(2)
+			}
+			if !pass {
+				os.Exit(1)
+			}
+			return
+		case "adversarial":
+			// Random rare syllables the rules romanize badly
+			clean, err := runAdversarial(os.Args[2:])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(2)
+			}
+			if !clean {
+				os.Exit(1)
+			}
+			return
+		case "patterns":
+			// Vowel pattern table anomalies, or the table itself
+			if err := runPatterns(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "canonical":
+			// Dictionary entries rewritten at load to the current conventions
+			printCanonicalizations(paiboonizer.DictionaryCanonicalizations())
+			return
+		case "determinism":
+			// Identical outputs across GOMAXPROCS values and worker counts
+			if !runDeterminism() {
+				os.Exit(1)
+			}
+			return
+		case "soak":
+			// Managers created and closed for hours, checking for leaks
+			clean, err := runSoak(os.Args[2:])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(2)
+			}
+			if !clean {
+				os.Exit(1)
+			}
+			return
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown command %q (available: inventory, chart, csv, roundtrip, corpus, review, gate, adversarial, patterns, canonical, determinism, soak)\n", os.Args[1])
+			os.Exit(2)
+		}
+	}
+
 	header := color.New(color.Bold, color.FgYellow)
 
+	// Stats go to stderr so that they don't end up in piped CLI output
+	stats := paiboonizer.Stats()
+	fmt.Fprintf(os.Stderr, "Dictionary built: %d entries, %d syllables\n", stats.Entries, stats.Syllables)
+	if stats.OpusEntries > 0 {
+		fmt.Fprintf(os.Stderr, "Opus dictionary: %d entries\n", stats.OpusEntries)
+	}
+
 	// Initialize translitkit module (starts pythainlp, sets default manager)
 	// Keep it alive for both tests
 	module, err := common.GetSchemeModule("tha", "paiboon-hybrid")
@@ -68,273 +158,415 @@ func main() {
 	// Test 3: Dictionary accuracy test (paiboonizer rules vs dictionary ground truth)
 	// Reuses the pythainlp container via default manager
 	header.Println("\n=== DICTIONARY TEST (PAIBOONIZER ACCURACY) ===")
-	dictResults := paiboonizer.RunDictionaryTest(paiboonizer.TestModePythainlp)
+	dictResults := paiboonizer.RunDictionaryTest(paiboonizer.DictTestOptions{Mode: paiboonizer.TestModePythainlp})
 	printDictResults(dictResults)
 }
 
-// printDictResults formats dictionary test results with color
-func printDictResults(r paiboonizer.DictTestResults) {
-	fmt.Println("Testing pythainlp syllable tokenization + rule-based transliteration")
-	fmt.Printf("Dictionary entries: %d, Syllable dict: %d\n\n", 4981, 2772) // TODO: export these
-
-	fmt.Println("=== RESULTS ===")
-	fmt.Printf("Total: %d | Passed: %d | Failed: %d\n", r.Total, r.Passed, r.Failed)
-	if r.PythainlpFallbacks > 0 {
-		fmt.Printf("Pythainlp fallbacks: %d (%.1f%%)\n", r.PythainlpFallbacks, float64(r.PythainlpFallbacks)*100/float64(r.Total))
-	}
+// columnList collects --column flags, repeated or comma separated
+type columnList []int
 
-	boldGreen := color.New(color.Bold, color.FgGreen)
-	boldGreen.Printf("\nDICTIONARY ACCURACY: %.2f%%\n", r.Accuracy)
+func (c *columnList) String() string {
+	return fmt.Sprint(*c)
+}
 
-	// Sample failures
-	if len(r.Failures) > 0 {
-		fmt.Println("\n=== Sample Failures (first 20) ===")
-		for i, f := range r.Failures {
-			if i >= 20 {
-				break
-			}
-			fmt.Printf("%s: got '%s', expected '%s'\n", f.Thai, f.Got, f.Expected)
+func (c *columnList) Set(value string) error {
+	for _, field := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return fmt.Errorf("invalid column %q", field)
 		}
-
-		fmt.Println("\n=== Failure Analysis ===")
-		fmt.Printf("Tone: ~%d (%.1f%%) | Vowel/length: ~%d (%.1f%%) | Consonant: ~%d (%.1f%%)\n",
-			r.ToneErrors, float64(r.ToneErrors)*100/float64(len(r.Failures)),
-			r.VowelErrors, float64(r.VowelErrors)*100/float64(len(r.Failures)),
-			r.ConsonantErrors, float64(r.ConsonantErrors)*100/float64(len(r.Failures)))
+		*c = append(*c, n)
 	}
+	return nil
 }
 
-// getTestDir returns the directory containing the test files
-func getTestDir() string {
-	_, filename, _, ok := runtime.Caller(0)
-	if !ok {
-		return "."
+// runCSV implements "csv [--column N]... [--header] [--delimiter D] [file]":
+// the selected columns are romanized and every other cell is copied. The
+// delimiter defaults to a tab for .tsv and .txt files and stdin, to a
+// comma otherwise.
+func runCSV(args []string) error {
+	fs := flag.NewFlagSet("csv", flag.ContinueOnError)
+	var columns columnList
+	fs.Var(&columns, "column", "1-based column to romanize (repeatable, or comma separated)")
+	header := fs.Bool("header", false, "copy the first row unchanged")
+	delimiter := fs.String("delimiter", "", `field separator, "\t" for tab (default from the file extension)`)
+	overrides := fs.String("overrides", "", "TSV file of fixed word and line readings")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	opts := paiboonizer.DefaultOptions()
+	if *overrides != "" {
+		var err error
+		if opts.Overrides, err = paiboonizer.LoadOverrides(*overrides); err != nil {
+			return err
+		}
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("at least one --column is required")
+	}
+
+	in := os.Stdin
+	comma := '\t'
+	if path := fs.Arg(0); path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+		if ext := strings.ToLower(filepath.Ext(path)); ext != ".tsv" && ext != ".txt" {
+			comma = ','
+		}
 	}
-	return filepath.Dir(filename)
-}
-
-// discoverCorpus finds all testN.txt + testN_Opus4.5_transliterated.txt pairs
-func discoverCorpus(dir string) ([]testPair, error) {
-	pattern := filepath.Join(dir, "testing_files", "test*.txt")
-	matches, err := filepath.Glob(pattern)
-	if err != nil {
-		return nil, err
+	switch *delimiter {
+	case "":
+	case `\t`, "tab":
+		comma = '\t'
+	default:
+		r := []rune(*delimiter)
+		if len(r) != 1 {
+			return fmt.Errorf("delimiter must be a single character, got %q", *delimiter)
+		}
+		comma = r[0]
 	}
 
-	warn := color.New(color.FgYellow)
-	errColor := color.New(color.FgRed)
+	return paiboonizer.TransliterateColumns(in, os.Stdout, paiboonizer.ColumnOptions{
+		Columns: columns,
+		Comma:   comma,
+		Header:  *header,
+		Text:    opts,
+	})
+}
 
-	var pairs []testPair
-	for _, inputPath := range matches {
-		// Skip transliterated files
-		if strings.Contains(inputPath, "_Opus4.5_transliterated") {
-			continue
+// runCorpusBuild implements "corpus [thai.txt roman.txt]...": each Thai
+// subtitle file is aligned with its romanized counterpart and the clean
+// pairs are written as a parallel corpus TSV. Without arguments the
+// testing_files testN.txt / testN_Opus4.5_transliterated.txt pairs are
+// used. Rejected lines are reported on stderr.
+func runCorpusBuild(args []string) error {
+	if len(args)%2 != 0 {
+		return fmt.Errorf("expected pairs of Thai and romanized files")
+	}
+	if len(args) == 0 {
+		matches, err := filepath.Glob(filepath.Join(getTestDir(), "testing_files", "test*.txt"))
+		if err != nil {
+			return err
 		}
-
-		// Derive expected path: testN.txt -> testN_Opus4.5_transliterated.txt
-		base := strings.TrimSuffix(filepath.Base(inputPath), ".txt")
-		expectedPath := filepath.Join(filepath.Dir(inputPath), base+"_Opus4.5_transliterated.txt")
-
-		// Check expected file exists
-		if _, err := os.Stat(expectedPath); os.IsNotExist(err) {
-			warn.Printf("WARNING: No transliteration for %s, skipping\n", base)
-			continue
+		sort.Slice(matches, func(i, j int) bool { return corpustest.NaturalLess(matches[i], matches[j]) })
+		for _, thaiPath := range matches {
+			if strings.Contains(thaiPath, corpustest.ReferenceSuffix) {
+				continue
+			}
+			romanPath := strings.TrimSuffix(thaiPath, ".txt") + corpustest.ReferenceSuffix + ".txt"
+			if _, err := os.Stat(romanPath); err == nil {
+				args = append(args, thaiPath, romanPath)
+			}
 		}
+	}
 
-		// Load files
-		inputs, err := loadLines(inputPath)
+	var corpus []paiboonizer.CorpusPair
+	for i := 0; i < len(args); i += 2 {
+		thaiLines, err := corpustest.LoadLines(args[i])
 		if err != nil {
-			errColor.Printf("ERROR: Failed to load %s: %v\n", inputPath, err)
-			continue
+			return err
 		}
-		expected, err := loadLines(expectedPath)
+		romanLines, err := corpustest.LoadLines(args[i+1])
 		if err != nil {
-			errColor.Printf("ERROR: Failed to load %s: %v\n", expectedPath, err)
-			continue
+			return err
 		}
-
-		// VALIDATION: Line count must match
-		if len(inputs) != len(expected) {
-			errColor.Printf("ERROR: Line mismatch in %s: %d vs %d, skipping\n",
-				base, len(inputs), len(expected))
-			continue
+		source := strings.TrimSuffix(filepath.Base(args[i]), filepath.Ext(args[i]))
+		pairs, rejections := paiboonizer.AlignSubtitles(source, thaiLines, romanLines)
+		for _, r := range rejections {
+			fmt.Fprintf(os.Stderr, "%s:%d: %s: %s\n", r.Source, r.Line, r.Reason, r.Text)
 		}
-
-		pairs = append(pairs, testPair{
-			name:          base,
-			inputLines:    inputs,
-			expectedLines: expected,
-		})
+		fmt.Fprintf(os.Stderr, "%s: %d pairs, %d rejected lines\n", source, len(pairs), len(rejections))
+		corpus = append(corpus, pairs...)
 	}
+	return paiboonizer.WriteCorpusTSV(os.Stdout, corpus)
+}
 
-	// Sort for consistent order (test1, test2, test8...)
-	sort.Slice(pairs, func(i, j int) bool {
-		return naturalLess(pairs[i].name, pairs[j].name)
-	})
+// runReview implements "review [--threshold t] thai.txt roman.txt": the
+// review list of the subtitle pair as TSV on stdout. Rejected lines are
+// reported on stderr.
+func runReview(args []string) error {
+	fs := flag.NewFlagSet("review", flag.ContinueOnError)
+	threshold := fs.Float64("threshold", 0.3, "share of differing syllables above which a line is listed")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("expected a Thai and a romanized file")
+	}
+	thaiLines, err := corpustest.LoadLines(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	romanLines, err := corpustest.LoadLines(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	source := strings.TrimSuffix(filepath.Base(fs.Arg(0)), filepath.Ext(fs.Arg(0)))
+	items, rejections := paiboonizer.ReviewSubtitles(source, thaiLines, romanLines, *threshold, paiboonizer.DefaultOptions())
+	for _, r := range rejections {
+		fmt.Fprintf(os.Stderr, "%s:%d: %s: %s\n", r.Source, r.Line, r.Reason, r.Text)
+	}
+	fmt.Fprintf(os.Stderr, "%s: %d lines to review, %d rejected lines\n", source, len(items), len(rejections))
+	return paiboonizer.WriteReviewTSV(os.Stdout, items)
+}
 
-	return pairs, nil
+// runQualityGate implements "gate [--corpus corpus.tsv] [--sample n]
+// [--expectations file] [--write-expectations]", printing the report and
+// returning whether every threshold was met. With --write-expectations the
+// expectations file is rewritten to the failures of this run, keeping
+// their issues.
+func runQualityGate(args []string) (bool, error) {
+	cfg := paiboonizer.DefaultQualityConfig()
+	fs := flag.NewFlagSet("gate", flag.ContinueOnError)
+	fs.StringVar(&cfg.CorpusPath, "corpus", "", "parallel corpus TSV written by the corpus command")
+	fs.IntVar(&cfg.DictionarySample, "sample", cfg.DictionarySample, "dictionary entries to test, 0 for all")
+	fs.StringVar(&cfg.ExpectationsPath, "expectations", "", "known failures file; failures it doesn't list fail the gate")
+	write := fs.Bool("write-expectations", false, "rewrite the expectations file to the failures of this run")
+	if err := fs.Parse(args); err != nil {
+		return false, err
+	}
+	if *write && cfg.ExpectationsPath == "" {
+		return false, fmt.Errorf("--write-expectations needs --expectations")
+	}
+	pass, report := paiboonizer.QualityGate(cfg)
+	fmt.Print(report)
+	if *write {
+		if err := writeExpectationsFile(cfg.ExpectationsPath, report.Expectations.Current); err != nil {
+			return false, err
+		}
+		fmt.Printf("Wrote %s\n", cfg.ExpectationsPath)
+	}
+	if pass {
+		color.New(color.Bold, color.FgGreen).Println("PASS")
+	} else {
+		color.New(color.Bold, color.FgRed).Println("FAIL")
+	}
+	return pass, nil
 }
 
-// naturalLess compares strings with embedded numbers naturally
-// e.g., "test2" < "test10"
-func naturalLess(a, b string) bool {
-	numA := extractNumber(a)
-	numB := extractNumber(b)
-	if numA != numB {
-		return numA < numB
+// writeExpectationsFile writes e to path
+func writeExpectationsFile(path string, e paiboonizer.Expectations) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
 	}
-	return a < b
+	if err := paiboonizer.WriteExpectations(f, e); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// runAdversarial implements "adversarial [--n count] [--seed seed]": the
+// generated syllables the rules can't romanize cleanly are written as TSV
+// on stdout. Returns whether there were none.
+func runAdversarial(args []string) (bool, error) {
+	fs := flag.NewFlagSet("adversarial", flag.ContinueOnError)
+	n := fs.Int("n", 5000, "number of syllables to generate")
+	seed := fs.Int64("seed", 1, "random seed")
+	if err := fs.Parse(args); err != nil {
+		return false, err
+	}
+	syllables := paiboonizer.AdversarialSyllables(*n, *seed)
+	issues := paiboonizer.CheckSyllableCoverage(syllables)
+	for _, issue := range issues {
+		fmt.Printf("%s\t%s\t%s\n", issue.Thai, issue.Roman, issue.Problem)
+	}
+	fmt.Fprintf(os.Stderr, "Syllables: %d | Issues: %d\n", len(syllables), len(issues))
+	return len(issues) == 0, nil
 }
 
-// extractNumber extracts the first number from a string
-func extractNumber(s string) int {
-	re := regexp.MustCompile(`\d+`)
-	match := re.FindString(s)
-	if match == "" {
-		return 0
+// runDeterminism romanizes the dictionary words and random rare syllables
+// under several GOMAXPROCS values, with and without the dictionaries, and
+// reports whether each configuration gave the same output checksum
+func runDeterminism() bool {
+	var words []string
+	for _, e := range paiboonizer.SearchDictionary(paiboonizer.DictQuery{}) {
+		words = append(words, e.Thai)
+	}
+	words = append(words, paiboonizer.AdversarialSyllables(2000, 1)...)
+
+	rules := paiboonizer.DefaultOptions()
+	rules.Dictionary = paiboonizer.DictionaryNone
+	configs := []struct {
+		name string
+		opts paiboonizer.Options
+	}{{"default", paiboonizer.DefaultOptions()}, {"rules", rules}}
+
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	deterministic := true
+	for _, cfg := range configs {
+		want := ""
+		for _, procs := range []int{1, 2, runtime.NumCPU()} {
+			runtime.GOMAXPROCS(procs)
+			sum := paiboonizer.OutputChecksum(words, cfg.opts, procs*2)
+			status := "ok"
+			if want == "" {
+				want = sum
+			} else if sum != want {
+				status = "MISMATCH"
+				deterministic = false
+			}
+			fmt.Printf("%s\tGOMAXPROCS=%d\tworkers=%d\t%s\t%s\n", cfg.name, procs, procs*2, sum[:16], status)
+		}
 	}
-	n, _ := strconv.Atoi(match)
-	return n
+	fmt.Fprintf(os.Stderr, "Words: %d | Deterministic: %v\n", len(words), deterministic)
+	return deterministic
 }
 
-// loadLines reads a file and returns all lines
-// Aegisub \N markers are replaced with single spaces
-func loadLines(path string) ([]string, error) {
-	file, err := os.Open(path)
+// runSoak implements "soak [--duration d] [--cycle d]": the corpus lines
+// are streamed through Managers created and closed every cycle, with a
+// sample of the process state after each Close on stdout. Returns whether
+// no leak was found.
+func runSoak(args []string) (bool, error) {
+	fs := flag.NewFlagSet("soak", flag.ContinueOnError)
+	duration := fs.Duration("duration", 2*time.Hour, "total run time")
+	cycle := fs.Duration("cycle", 5*time.Minute, "lifetime of each manager")
+	fdGrowth := fs.Int("fd-growth", 0, "open file descriptors growth tolerated")
+	if err := fs.Parse(args); err != nil {
+		return false, err
+	}
+	corpus, ok := discoverCorpus(getTestDir())
+	if !ok {
+		return false, fmt.Errorf("no corpus to stream")
+	}
+	var lines []string
+	for _, pair := range corpus {
+		lines = append(lines, pair.Input...)
+	}
+	report, err := paiboonizer.SoakManagers(context.Background(), paiboonizer.SoakConfig{
+		Duration:    *duration,
+		Cycle:       *cycle,
+		Lines:       lines,
+		MaxFDGrowth: *fdGrowth,
+		Progress:    func(s paiboonizer.SoakSample) { fmt.Println(s) },
+	})
 	if err != nil {
-		return nil, err
+		return false, err
+	}
+	for _, p := range report.Problems {
+		color.New(color.FgRed).Printf("Problem: %s\n", p)
 	}
-	defer file.Close()
+	fmt.Fprintf(os.Stderr, "Cycles: %d | Lines: %d | Clean: %v\n", len(report.Samples)-1, len(lines), report.OK())
+	return report.OK(), nil
+}
 
-	var lines []string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		// Replace Aegisub subtitle line breaks with single space
-		line = strings.ReplaceAll(line, "\\N", " ")
-		lines = append(lines, line)
-	}
-	return lines, scanner.Err()
+// runPatterns implements "patterns [--dump]": the vowel pattern conflicts
+// on stdout, or with --dump the effective pattern table as TSV
+func runPatterns(args []string) error {
+	fs := flag.NewFlagSet("patterns", flag.ContinueOnError)
+	dump := fs.Bool("dump", false, "write the vowel pattern table instead")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dump {
+		return paiboonizer.WriteVowelPatterns(os.Stdout)
+	}
+	conflicts := paiboonizer.VowelPatternConflicts()
+	for _, c := range conflicts {
+		fmt.Println(c)
+	}
+	fmt.Fprintf(os.Stderr, "Patterns: %d | Conflicts: %d\n", len(paiboonizer.VowelPatterns()), len(conflicts))
+	return nil
 }
 
-// punctuationRegex matches Unicode punctuation characters
-var punctuationRegex = regexp.MustCompile(`[\p{P}\p{S}]`)
-
-// normalize prepares strings for comparison
-func normalize(s string) string {
-	// Remove BOM if present
-	s = strings.TrimPrefix(s, "\ufeff")
-	s = norm.NFC.String(s)
-	s = strings.TrimSpace(s)
-	s = strings.ToLower(s)
-	// Remove all Unicode punctuation and symbols
-	s = punctuationRegex.ReplaceAllString(s, " ")
-	// Normalize ALL whitespace (tabs, multiple spaces, etc.) to single space
-	fields := strings.Fields(s)
-	s = strings.Join(fields, " ")
-	// Normalize ambiguous tones (both are valid for ไหม question particle)
-	s = strings.ReplaceAll(s, " mǎi ", " mai ")
-	s = strings.ReplaceAll(s, " mái ", " mai ")
-	if strings.HasSuffix(s, " mǎi") {
-		s = s[:len(s)-len(" mǎi")] + " mai"
-	}
-	if strings.HasSuffix(s, " mái") {
-		s = s[:len(s)-len(" mái")] + " mai"
-	}
-	// Normalize ambiguous tones (both wà and wâ valid for ว่ะ particle)
-	s = strings.ReplaceAll(s, " wà ", " wa ")
-	s = strings.ReplaceAll(s, " wâ ", " wa ")
-	if strings.HasSuffix(s, " wà") {
-		s = s[:len(s)-len(" wà")] + " wa"
-	}
-	if strings.HasSuffix(s, " wâ") {
-		s = s[:len(s)-len(" wâ")] + " wa"
-	}
-	// Normalize numbers to Thai romanization for fair comparison
-	s = normalizeNumbers(s)
-	return s
+// printCanonicalizations lists the dictionary entries rewritten at load,
+// to be fixed in their data file
+func printCanonicalizations(list []paiboonizer.Canonicalization) {
+	for _, c := range list {
+		fmt.Printf("%s\t%s\t%s\t%s\t%s\n", c.File, c.Thai, c.Original, c.Roman, strings.Join(c.Changes, ", "))
+	}
+	fmt.Fprintf(os.Stderr, "Rewritten entries: %d\n", len(list))
 }
 
-// normalizeNumbers converts Arabic numerals to Thai number romanization
-func normalizeNumbers(s string) string {
-	// Find and replace number sequences
-	var result strings.Builder
-	i := 0
-	runes := []rune(s)
-
-	for i < len(runes) {
-		if runes[i] >= '0' && runes[i] <= '9' {
-			// Collect the full number
-			numStart := i
-			for i < len(runes) && runes[i] >= '0' && runes[i] <= '9' {
-				i++
-			}
-			numStr := string(runes[numStart:i])
-			thai := numberToThai(numStr)
-			if result.Len() > 0 && result.String()[result.Len()-1] != ' ' {
-				result.WriteString(" ")
-			}
-			result.WriteString(thai)
-		} else {
-			result.WriteRune(runes[i])
-			i++
-		}
+// printRoundTripResults lists the dictionary entries failing the round trip
+func printRoundTripResults(r paiboonizer.RoundTripResults) {
+	for _, issue := range r.Issues {
+		fmt.Printf("%s\t%s\t%s does not map back to %s (candidates: %s)\n",
+			issue.Thai, issue.Roman, issue.Syllable, issue.Remaining, strings.Join(issue.Candidates, " "))
 	}
-	return result.String()
+	fmt.Fprintf(os.Stderr, "Checked: %d | Skipped: %d | Likely data errors: %d\n", r.Checked, r.Skipped, len(r.Issues))
 }
 
-// numberToThai converts an Arabic numeral string to Thai romanization
-func numberToThai(num string) string {
-	units := []string{"", "nʉ̀ng", "sɔ̌ɔng", "sǎam", "sìi", "hâa", "hòk", "jèt", "bpɛ̀ɛt", "gâao"}
-	tens := []string{"", "sìp", "yîi sìp", "sǎam sìp", "sìi sìp", "hâa sìp", "hòk sìp", "jèt sìp", "bpɛ̀ɛt sìp", "gâao sìp"}
+// printDictResults formats dictionary test results with color
+func printDictResults(r paiboonizer.DictTestResults) {
+	fmt.Println("Testing pythainlp syllable tokenization + rule-based transliteration")
+	fmt.Printf("Dictionary entries: %d, Syllable dict: %d\n\n", 4981, 2772) // TODO: export these
 
-	// Handle single digit
-	if len(num) == 1 {
-		d := int(num[0] - '0')
-		if d == 0 {
-			return "sǔun"
-		}
-		return units[d]
-	}
-
-	// Handle two digits (10-99)
-	if len(num) == 2 {
-		t := int(num[0] - '0')
-		u := int(num[1] - '0')
-		result := tens[t]
-		if u > 0 {
-			if u == 1 && t > 0 {
-				result += " èt" // Special: 11, 21, 31... use "èt" not "nʉ̀ng"
-			} else {
-				result += " " + units[u]
+	fmt.Println("=== RESULTS ===")
+	fmt.Printf("Total: %d | Passed: %d | Failed: %d\n", r.Total, r.Passed, r.Failed)
+	if r.PythainlpFallbacks > 0 {
+		fmt.Printf("Pythainlp fallbacks: %d (%.1f%%)\n", r.PythainlpFallbacks, float64(r.PythainlpFallbacks)*100/float64(r.Total))
+	}
+	if r.SeparatorErrors > 0 {
+		fmt.Printf("Separator-only mismatches (segmentation boundaries): %d\n", r.SeparatorErrors)
+	}
+
+	boldGreen := color.New(color.Bold, color.FgGreen)
+	boldGreen.Printf("\nDICTIONARY ACCURACY: %.2f%%\n", r.Accuracy)
+
+	if syl := r.Syllables; syl.Syllables > 0 {
+		fmt.Printf("Syllable accuracy: %.2f%% (%d/%d syllables in %d separated entries), boundary errors: %d\n",
+			syl.Accuracy(), syl.Correct, syl.Syllables, syl.Words, syl.BoundaryErrors)
+		var positions []string
+		for i, p := range syl.ByPosition {
+			if i >= 5 {
+				break
 			}
+			positions = append(positions, fmt.Sprintf("#%d %.1f%%", i+1, float64(p.Correct)*100/float64(p.Total)))
 		}
-		return result
+		fmt.Println("By position: " + strings.Join(positions, " | "))
 	}
 
-	// For larger numbers, just convert digit by digit for simplicity
-	var parts []string
-	for _, r := range num {
-		d := int(r - '0')
-		if d == 0 {
-			parts = append(parts, "sǔun")
-		} else {
-			parts = append(parts, units[d])
+	// Sample failures
+	if len(r.Failures) > 0 {
+		fmt.Println("\n=== Sample Failures (first 20) ===")
+		for i, f := range r.Failures {
+			if i >= 20 {
+				break
+			}
+			exp, got := paiboonizer.DiffANSI(f.Expected, f.Got)
+			fmt.Printf("%s: got '%s', expected '%s'\n", f.Thai, got, exp)
 		}
+
+		fmt.Println("Differences: " + diffLegend())
+
+		fmt.Println("\n=== Failure Analysis ===")
+		fmt.Printf("Tone: ~%d (%.1f%%) | Vowel/length: ~%d (%.1f%%) | Consonant: ~%d (%.1f%%)\n",
+			r.ToneErrors, float64(r.ToneErrors)*100/float64(len(r.Failures)),
+			r.VowelErrors, float64(r.VowelErrors)*100/float64(len(r.Failures)),
+			r.ConsonantErrors, float64(r.ConsonantErrors)*100/float64(len(r.Failures)))
+	}
+}
+
+// diffLegend explains the colors used by paiboonizer.DiffANSI
+func diffLegend() string {
+	return strings.Join([]string{
+		color.YellowString("tone"),
+		color.MagentaString("vowel"),
+		color.RedString("consonant"),
+		color.CyanString("other"),
+	}, ", ")
+}
+
+// getTestDir returns the directory containing the test files
+func getTestDir() string {
+	_, filename, _, ok := runtime.Caller(0)
+	if !ok {
+		return "."
 	}
-	return strings.Join(parts, " ")
+	return filepath.Dir(filename)
 }
 
 // runCorpusTranslitkit runs corpus test via translitkit with full failure analysis
 func runCorpusTranslitkit(module *common.Module) {
 	dir := getTestDir()
-	corpus, err := discoverCorpus(dir)
-	if err != nil {
-		fmt.Printf("Error discovering corpus: %v\n", err)
-		return
-	}
-	if len(corpus) == 0 {
-		fmt.Println("No valid test pairs found")
+	corpus, ok := discoverCorpus(dir)
+	if !ok {
 		return
 	}
 
@@ -342,97 +574,24 @@ func runCorpusTranslitkit(module *common.Module) {
 	fmt.Printf("Discovered %d test files:\n", len(corpus))
 	totalCorpusLines := 0
 	for _, p := range corpus {
-		fmt.Printf("  %s: %d lines\n", p.name, len(p.inputLines))
-		totalCorpusLines += len(p.inputLines)
+		fmt.Printf("  %s: %d lines\n", p.Name, len(p.Input))
+		totalCorpusLines += len(p.Input)
 	}
 	fmt.Printf("Total corpus: %d lines\n\n", totalCorpusLines)
 
-	// Flatten corpus for processing, tracking source file for each line
-	type lineInfo struct {
-		input    string
-		expected string
-		file     string
-		lineNum  int // Line number within the source file
-	}
-	var allLines []lineInfo
-	for _, p := range corpus {
-		for i := range p.inputLines {
-			allLines = append(allLines, lineInfo{
-				input:    p.inputLines[i],
-				expected: p.expectedLines[i],
-				file:     p.name,
-				lineNum:  i + 1,
-			})
-		}
-	}
-
-	lineCorrect := 0
-	totalLines := 0
-	wordCorrect := 0
-	totalWords := 0
-	fallbacks := 0
-
-	var failures []corpusFailure
-
-	for _, line := range allLines {
-		input := strings.TrimSpace(line.input)
-		exp := normalize(line.expected)
-
-		if input == "" || exp == "" {
-			continue
-		}
-		// Skip Aegisub header lines
-		if strings.HasPrefix(input, "#") && strings.Contains(input, "Aegisub") {
-			continue
-		}
-		// Skip lines containing Arabic numerals (unfair to measure)
-		if containsDigit(input) {
-			continue
-		}
-		// Skip lines where ground truth uses precomposed accented characters
-		// (can't reliably compare with engine output which uses combining marks)
-		if hasPrecomposedAccents(line.expected) {
-			continue
-		}
-		// Skip lines containing ๆ (Thai repetition marker) - requires ML to parse correctly
-		if strings.Contains(input, "ๆ") {
-			continue
-		}
-		totalLines++
-
-		// Use translitkit for transliteration
-		result, err := module.Roman(input)
-		if err != nil {
-			fmt.Printf("Error on [%s:%d]: %v\n", line.file, line.lineNum, err)
-			fallbacks++
-			continue
-		}
-
-		got := normalize(result)
-
-		// Line-level accuracy
-		if got == exp {
-			lineCorrect++
-		} else {
-			failures = append(failures, corpusFailure{
-				file:     line.file,
-				lineNum:  line.lineNum,
-				input:    input,
-				expected: line.expected,
-				got:      result,
-			})
-		}
-
-		// Word-level accuracy
-		expWords := splitWords(exp)
-		gotWords := splitWords(got)
-		totalWords += len(expWords)
-		wordCorrect += countMatchingWords(expWords, gotWords)
-	}
+	result := corpustest.Run(corpus, module.Roman, corpustest.Options{
+		Accents:        corpustest.PolicySkip,
+		SkipRepetition: true,
+	})
+	failures := result.Failures
+	printSkipped(result.Skipped)
 
 	// Report fallbacks
-	if fallbacks > 0 {
-		fmt.Printf("WARNING: Fallbacks occurred: %d\n", fallbacks)
+	for _, e := range result.Errors {
+		fmt.Printf("Error on [%s:%d]: %v\n", e.File, e.Line, e.Err)
+	}
+	if len(result.Errors) > 0 {
+		fmt.Printf("WARNING: Fallbacks occurred: %d\n", len(result.Errors))
 	} else {
 		fmt.Printf("Fallbacks: 0 (good!)\n")
 	}
@@ -448,11 +607,13 @@ func runCorpusTranslitkit(module *common.Module) {
 		fmt.Println(strings.Repeat("-", 80))
 		for i := 0; i < showCount; i++ {
 			f := failures[i]
-			fmt.Printf("[%s:%d] %s\n", f.file, f.lineNum, f.input)
-			fmt.Printf("  Expected: %s\n", f.expected)
-			fmt.Printf("  Got:      %s\n", f.got)
+			exp, got := paiboonizer.DiffANSI(f.Expected, f.Got)
+			fmt.Printf("[%s:%d] %s\n", f.File, f.Line, f.Input)
+			fmt.Printf("  Expected: %s\n", exp)
+			fmt.Printf("  Got:      %s\n", got)
 		}
 		fmt.Println(strings.Repeat("-", 80))
+		fmt.Println("Differences: " + diffLegend())
 	}
 
 	// Write all failures to file
@@ -464,15 +625,15 @@ func runCorpusTranslitkit(module *common.Module) {
 		} else {
 			defer file.Close()
 			for _, f := range failures {
-				fmt.Fprintf(file, "[%s:%d] %s\n", f.file, f.lineNum, f.input)
-				fmt.Fprintf(file, "  Expected: %s\n", f.expected)
-				fmt.Fprintf(file, "  Got:      %s\n\n", f.got)
+				fmt.Fprintf(file, "[%s:%d] %s\n", f.File, f.Line, f.Input)
+				fmt.Fprintf(file, "  Expected: %s\n", f.Expected)
+				fmt.Fprintf(file, "  Got:      %s\n\n", f.Got)
 			}
 			fmt.Printf("\nAll %d failures written to: %s\n", len(failures), failuresFile)
 		}
 	}
 
-	// Generate draft dictionary from failing words
+	// Generate draft dictionary from failing words, most useful first
 	failedWords := extractFailingWords(failures)
 	if len(failedWords) > 0 {
 		draftPath := filepath.Join(dir, "testing_files/draft_dictionary.tsv")
@@ -481,157 +642,132 @@ func runCorpusTranslitkit(module *common.Module) {
 			fmt.Printf("Error creating draft dictionary: %v\n", err)
 		} else {
 			defer file.Close()
-			// Sort for consistent output
-			sortedWords := make([]string, 0, len(failedWords))
-			for word := range failedWords {
-				sortedWords = append(sortedWords, word)
-			}
-			sort.Strings(sortedWords)
-			for _, word := range sortedWords {
-				fmt.Fprintf(file, "%s\t\n", word)
+			selected := paiboonizer.SelectForAnnotation(failedWords, draftDictionarySize)
+			proposals := proposeDraftRomanizations(selected)
+			for _, c := range selected {
+				fmt.Fprintf(file, "%s\t%s\n", c.Word, proposals[c.Word])
 			}
-			fmt.Printf("Draft dictionary: %d words written to %s\n", len(failedWords), "testing_files/draft_dictionary.tsv")
+			fmt.Printf("Draft dictionary: %d of %d words written to %s\n", len(selected), len(failedWords), "testing_files/draft_dictionary.tsv")
 		}
 	}
 
-	lineAccuracy := float64(lineCorrect) / float64(totalLines) * 100
-	wordAccuracy := float64(wordCorrect) / float64(totalWords) * 100
-
 	bold := color.New(color.Bold)
 	boldCyan := color.New(color.Bold, color.FgCyan)
 
 	fmt.Println()
-	bold.Printf("Line-level accuracy: %.2f%% (%d/%d lines)\n", lineAccuracy, lineCorrect, totalLines)
-	boldCyan.Printf("CORPUS WORD-LEVEL ACCURACY: %.2f%% (%d/%d words)\n", wordAccuracy, wordCorrect, totalWords)
+	bold.Printf("Line-level accuracy: %.2f%% (%d/%d lines)\n", result.LineAccuracy(), result.LinesCorrect, result.Lines)
+	if result.SeparatorMismatches > 0 {
+		fmt.Printf("Separator-only mismatches: %d lines\n", result.SeparatorMismatches)
+	}
+	boldCyan.Printf("CORPUS WORD-LEVEL ACCURACY: %.2f%% (%d/%d words)\n", result.WordAccuracy(), result.WordsCorrect, result.Words)
+}
+
+// printSkipped reports the lines the corpus policies left out, by policy
+func printSkipped(skipped map[string]int) {
+	reasons := make([]string, 0, len(skipped))
+	for reason := range skipped {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		fmt.Printf("Skipped (%s): %d lines\n", reason, skipped[reason])
+	}
 }
 
 // runCorpusPureRules runs corpus test with pythainlp tokenization + pure rule-based transliteration
 // (no dictionary lookup). Silent output - just accuracy %.
 func runCorpusPureRules() {
-	dir := getTestDir()
-	corpus, err := discoverCorpus(dir)
-	if err != nil || len(corpus) == 0 {
-		fmt.Println("No valid test pairs found")
+	corpus, ok := discoverCorpus(getTestDir())
+	if !ok {
 		return
 	}
 
-	// Flatten corpus
-	var allInputs, allExpected []string
-	for _, p := range corpus {
-		allInputs = append(allInputs, p.inputLines...)
-		allExpected = append(allExpected, p.expectedLines...)
-	}
-
-	wordCorrect := 0
-	totalWords := 0
-
-	for i := 0; i < len(allInputs); i++ {
-		input := strings.TrimSpace(allInputs[i])
-		// Remove BOM
-		input = strings.TrimPrefix(input, "\ufeff")
-		exp := normalize(allExpected[i])
+	result := corpustest.Run(corpus, pureRulesLine, corpustest.Options{})
+	boldMagenta := color.New(color.Bold, color.FgMagenta)
+	boldMagenta.Printf("CORPUS PURE RULES WORD-LEVEL ACCURACY: %.2f%% (%d/%d words)\n", result.WordAccuracy(), result.WordsCorrect, result.Words)
+}
 
-		if input == "" || exp == "" {
-			continue
-		}
-		// Skip Aegisub header lines
-		if strings.HasPrefix(input, "#") && strings.Contains(input, "Aegisub") {
-			continue
-		}
-		// Skip lines containing Arabic numerals (unfair to measure)
-		if containsDigit(input) {
-			continue
-		}
+// pureRulesLine tokenizes a line with pythainlp and transliterates each
+// Thai word with the rules only
+func pureRulesLine(input string) (string, error) {
+	// Use pythainlp for word tokenization (via package-level function)
+	tokenResult, err := pythainlp.Tokenize(input)
+	if err != nil {
+		return "", err
+	}
+	if tokenResult == nil || len(tokenResult.Raw) == 0 {
+		return "", fmt.Errorf("no tokens")
+	}
 
-		// Use pythainlp for word tokenization (via package-level function)
-		tokenResult, err := pythainlp.Tokenize(input)
-		if err != nil || tokenResult == nil || len(tokenResult.Raw) == 0 {
+	// Transliterate each word using pure rules (no dictionary); non-Thai
+	// passes through (punctuation, numbers)
+	opts := paiboonizer.Options{Dictionary: paiboonizer.DictionaryNone, Engine: paiboonizer.EngineComprehensive}
+	var romanParts []string
+	for _, word := range tokenResult.Raw {
+		word = strings.TrimSpace(word)
+		if word == "" {
 			continue
 		}
-
-		// Transliterate each word using pure rules (no dictionary)
-		var romanParts []string
-		for _, word := range tokenResult.Raw {
-			word = strings.TrimSpace(word)
-			if word == "" {
-				continue
-			}
-			// Check if it's Thai text
-			if containsThai(word) {
-				roman := paiboonizer.ComprehensiveTransliterate(word)
-				romanParts = append(romanParts, roman)
-			} else {
-				// Non-Thai passes through (spaces, punctuation, numbers)
-				romanParts = append(romanParts, word)
-			}
+		var roman strings.Builder
+		for _, tok := range paiboonizer.ClassifyWords([]string{word}, opts) {
+			roman.WriteString(tok.Roman)
 		}
-
-		got := normalize(strings.Join(romanParts, " "))
-
-		// Word-level accuracy
-		expWords := splitWords(exp)
-		gotWords := splitWords(got)
-		totalWords += len(expWords)
-		wordCorrect += countMatchingWords(expWords, gotWords)
+		romanParts = append(romanParts, roman.String())
 	}
-
-	wordAccuracy := float64(wordCorrect) / float64(totalWords) * 100
-	boldMagenta := color.New(color.Bold, color.FgMagenta)
-	boldMagenta.Printf("CORPUS PURE RULES WORD-LEVEL ACCURACY: %.2f%% (%d/%d words)\n", wordAccuracy, wordCorrect, totalWords)
+	return strings.Join(romanParts, " "), nil
 }
 
-// containsThai checks if a string contains Thai characters
-func containsThai(s string) bool {
-	for _, r := range s {
-		if r >= 0x0E00 && r <= 0x0E7F {
-			return true
-		}
+// discoverCorpus loads the testing_files corpus, printing warnings about
+// the files left out. Returns false if there is nothing to test.
+func discoverCorpus(dir string) ([]corpustest.Pair, bool) {
+	corpus, warnings, err := corpustest.Discover(filepath.Join(dir, "testing_files"))
+	for _, w := range warnings {
+		color.New(color.FgYellow).Printf("WARNING: %s\n", w)
 	}
-	return false
-}
-
-// containsDigit checks if a string contains Arabic numerals (0-9)
-func containsDigit(s string) bool {
-	for _, r := range s {
-		if r >= '0' && r <= '9' {
-			return true
-		}
+	if err != nil {
+		fmt.Printf("Error discovering corpus: %v\n", err)
+		return nil, false
 	}
-	return false
+	if len(corpus) == 0 {
+		fmt.Println("No valid test pairs found")
+		return nil, false
+	}
+	return corpus, true
 }
 
-// hasPrecomposedAccents checks if ground truth uses precomposed accented vowels
-// that official Paiboon doesn't use. Paiboon uses precomposed à, á, â, ǎ, ě, ǐ, ǒ, ǔ
-// but uses combining marks for e, i, o, u with grave/acute/circumflex.
-// Skip only if ground truth has precomposed forms Paiboon doesn't use.
-func hasPrecomposedAccents(s string) bool {
-	for _, r := range s {
-		switch r {
-		// e with grave/acute/circumflex (Paiboon uses combining, not precomposed)
-		case 'è', 'é', 'ê': // U+00E8-EA
-			return true
-		// i with grave/acute/circumflex
-		case 'ì', 'í', 'î': // U+00EC-EE
-			return true
-		// o with grave/acute/circumflex
-		case 'ò', 'ó', 'ô': // U+00F2-F4
-			return true
-		// u with grave/acute/circumflex
-		case 'ù', 'ú', 'û': // U+00F9-FB
-			return true
-		}
+// draftDictionarySize is how many words the draft dictionary proposes for
+// romanization, ranked by paiboonizer.SelectForAnnotation
+const draftDictionarySize = 300
+
+// proposeDraftRomanizations pre-fills the draft dictionary with the
+// proposals of the oracle command named by PAIBOONIZER_ORACLE (program and
+// arguments separated by spaces, word on stdin, romanization on stdout).
+// Without it the second column is left empty.
+func proposeDraftRomanizations(candidates []paiboonizer.AnnotationCandidate) map[string]string {
+	fields := strings.Fields(os.Getenv("PAIBOONIZER_ORACLE"))
+	if len(fields) == 0 {
+		return nil
+	}
+	words := make([]string, len(candidates))
+	for i, c := range candidates {
+		words[i] = c.Word
+	}
+	oracle := paiboonizer.CommandOracle{Name: fields[0], Args: fields[1:]}
+	proposals, err := paiboonizer.ProposeRomanizations(context.Background(), oracle, words)
+	if err != nil {
+		color.New(color.FgYellow).Printf("WARNING: oracle: %v\n", err)
 	}
-	return false
+	return proposals
 }
 
-// extractFailingWords tokenizes failing Thai inputs and collects unique words
+// extractFailingWords tokenizes failing Thai inputs and counts the words
 // that aren't in the official dictionary
-func extractFailingWords(failures []corpusFailure) map[string]struct{} {
-	failedWords := make(map[string]struct{})
+func extractFailingWords(failures []corpustest.Failure) map[string]int {
+	failedWords := make(map[string]int)
 
 	for _, f := range failures {
 		// Tokenize the Thai input
-		input := strings.TrimPrefix(f.input, "\ufeff")
+		input := strings.TrimPrefix(f.Input, "\ufeff")
 		tokenResult, err := pythainlp.Tokenize(input)
 		if err != nil || tokenResult == nil || len(tokenResult.Raw) == 0 {
 			continue
@@ -640,7 +776,7 @@ func extractFailingWords(failures []corpusFailure) map[string]struct{} {
 		// Collect Thai words not in official dictionary
 		for _, word := range tokenResult.Raw {
 			word = strings.TrimSpace(word)
-			if word == "" || !containsThai(word) {
+			if word == "" || !paiboonizer.ContainsThai(word) {
 				continue
 			}
 			// Skip if already in official dictionary
@@ -659,40 +795,9 @@ func extractFailingWords(failures []corpusFailure) map[string]struct{} {
 			if strings.Contains(word, "ๆ") {
 				continue
 			}
-			failedWords[word] = struct{}{}
+			failedWords[word]++
 		}
 	}
 
 	return failedWords
 }
-
-// splitWords splits a romanized string into words by spaces
-func splitWords(s string) []string {
-	var words []string
-	for _, w := range strings.Fields(s) {
-		w = strings.TrimSpace(w)
-		if w != "" && w != "-" {
-			words = append(words, w)
-		}
-	}
-	return words
-}
-
-// countMatchingWords counts how many words from expected appear in got (order-sensitive)
-func countMatchingWords(expected, got []string) int {
-	matches := 0
-	gotIdx := 0
-
-	for _, expWord := range expected {
-		// Look for this expected word in the remaining got words
-		for gotIdx < len(got) {
-			if got[gotIdx] == expWord {
-				matches++
-				gotIdx++
-				break
-			}
-			gotIdx++
-		}
-	}
-	return matches
-}
@@ -0,0 +1,113 @@
+package paiboonizer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ColumnOptions selects what TransliterateColumns romanizes
+type ColumnOptions struct {
+	Columns []int // 1-based columns to romanize; the others are copied
+	// Comma is the field separator. A tab selects TSV, read and written
+	// line by line without quoting, as Anki exports are; anything else is
+	// read and written as CSV with encoding/csv.
+	Comma  rune
+	Header bool // Copy the first record unchanged
+	Text   Options
+}
+
+// TransliterateColumns copies a CSV or TSV file from r to w with the cells
+// of the selected columns romanized by TransliterateText. Leading lines
+// starting with # (Anki's #separator:tab, #html:true) are copied verbatim,
+// as are records too short to have a selected column.
+func TransliterateColumns(r io.Reader, w io.Writer, opts ColumnOptions) error {
+	if len(opts.Columns) == 0 {
+		return fmt.Errorf("no column selected")
+	}
+	selected := make(map[int]bool)
+	for _, c := range opts.Columns {
+		if c < 1 {
+			return fmt.Errorf("invalid column %d: columns start at 1", c)
+		}
+		selected[c-1] = true
+	}
+	romanize := func(record []string) []string {
+		for i := range record {
+			if selected[i] {
+				record[i] = TransliterateText(record[i], opts.Text)
+			}
+		}
+		return record
+	}
+
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	for {
+		next, err := br.Peek(1)
+		if err != nil || next[0] != '#' {
+			break
+		}
+		line, err := br.ReadString('\n')
+		bw.WriteString(line)
+		if err != nil {
+			break
+		}
+	}
+
+	if opts.Comma == '\t' {
+		if err := transliterateTSV(br, bw, opts.Header, romanize); err != nil {
+			return err
+		}
+		return bw.Flush()
+	}
+
+	cr := csv.NewReader(br)
+	cr.Comma = opts.Comma
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	cw := csv.NewWriter(bw)
+	cw.Comma = opts.Comma
+	for first := true; ; first = false {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if !first || !opts.Header {
+			record = romanize(record)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// transliterateTSV romanizes tab-separated lines, keeping line endings
+func transliterateTSV(r *bufio.Reader, w *bufio.Writer, header bool, romanize func([]string) []string) error {
+	for first := true; ; first = false {
+		line, err := r.ReadString('\n')
+		if line != "" {
+			body := strings.TrimRight(line, "\r\n")
+			if !first || !header {
+				body = strings.Join(romanize(strings.Split(body, "\t")), "\t")
+			}
+			w.WriteString(body + line[len(strings.TrimRight(line, "\r\n")):])
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
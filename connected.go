@@ -0,0 +1,30 @@
+package paiboonizer
+
+// DefaultConnectedSpeech returns the reduced forms used by the connected
+// speech pass: function words that lose vowel length or stress before a
+// content word in running speech. The map is a fresh copy that callers may
+// edit before setting it as Options.ConnectedSpeech.
+func DefaultConnectedSpeech() map[string]string {
+	return map[string]string{
+		"ที่": "tî",   // tîi, relative pronoun before a clause
+		"จะ":  "ja",   // jà, future marker
+		"ก็":  "gɔ̂",  // gɔ̂ɔ
+		"ไม่": "mai",  // mâi, negation before a verb or adjective
+		"เขา": "káo",  // kǎo
+		"ฉัน": "chán", // chǎn
+	}
+}
+
+// connectedSpeechForm returns the reduced reading of word when the
+// connected speech pass is on and next, the following word, is a content
+// word (not itself a reducible function word).
+func connectedSpeechForm(word, next string, opts Options) (string, bool) {
+	reduced, ok := opts.ConnectedSpeech[word]
+	if !ok || next == "" || next == "ๆ" {
+		return "", false
+	}
+	if _, function := opts.ConnectedSpeech[next]; function {
+		return "", false
+	}
+	return opts.render(reduced), true
+}
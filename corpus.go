@@ -0,0 +1,228 @@
+package paiboonizer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// CorpusPair is a Thai subtitle line aligned with its romanization
+type CorpusPair struct {
+	Source string // Name of the subtitle pair the line comes from
+	Line   int    // 1-based line number in the Thai file
+	Thai   string
+	Roman  string
+}
+
+// CorpusRejection is a line left out of the parallel corpus
+type CorpusRejection struct {
+	Source string
+	Line   int // 1-based line number in the Thai file, 0 for romanized lines
+	Text   string
+	Reason string
+}
+
+const (
+	// alignWindow bounds how far the Thai and romanized line numbers may
+	// drift apart during alignment
+	alignWindow = 20
+	// minAlignScore is the share of the romanized words the rules must
+	// reproduce (tones ignored) for two lines to be considered a pair
+	minAlignScore = 0.3
+)
+
+// corpusHeader starts every parallel corpus TSV file
+const corpusHeader = "# Paiboonizer parallel corpus\n# Format: Source<TAB>Line<TAB>Thai<TAB>Romanization\n"
+
+// AlignSubtitles pairs the lines of a Thai subtitle file with the lines of
+// its romanized counterpart (such as the Opus transliterations). Lines are
+// normalized first (BOM, \N breaks, NFC, spacing) and empty lines and
+// Aegisub headers are dropped. Pairs are found by a monotonic alignment
+// scored by how many romanized words the rules reproduce from the Thai
+// line, so that a missing or extra line on one side only costs that line
+// instead of shifting the rest of the file. Lines that find no partner, or
+// whose partner scores below minAlignScore, are returned as rejections.
+func AlignSubtitles(source string, thaiLines, romanLines []string) ([]CorpusPair, []CorpusRejection) {
+	ensureDictionaryLoaded()
+	type line struct {
+		num  int
+		text string
+	}
+	clean := func(lines []string) []line {
+		out := []line{}
+		for i, l := range lines {
+			l = normalizeSubtitleLine(l)
+			if l == "" || (strings.HasPrefix(l, "#") && strings.Contains(l, "Aegisub")) {
+				continue
+			}
+			out = append(out, line{i + 1, l})
+		}
+		return out
+	}
+	thai, roman := clean(thaiLines), clean(romanLines)
+
+	// Romanize each Thai line once; the alignment compares word sets
+	guesses := make([]map[string]bool, len(thai))
+	for i, l := range thai {
+		guesses[i] = alignWords(TransliterateText(l.text, DefaultOptions()))
+	}
+	targets := make([][]string, len(roman))
+	for j, l := range roman {
+		for w := range alignWords(l.text) {
+			targets[j] = append(targets[j], w)
+		}
+	}
+	score := func(i, j int) float64 {
+		if len(targets[j]) == 0 {
+			return 0
+		}
+		hits := 0
+		for _, w := range targets[j] {
+			if guesses[i][w] {
+				hits++
+			}
+		}
+		return float64(hits) / float64(len(targets[j]))
+	}
+
+	// best(i, j) is the best total score aligning thai[i:] with roman[j:].
+	// Only pairs within alignWindow lines of the diagonal are scored, so
+	// only that band of each row is stored: band[i][j-lo(i)].
+	n, m := len(thai), len(roman)
+	inWindow := func(i, j int) bool {
+		return abs(i*m-j*n) <= alignWindow*n
+	}
+	lo := func(i int) int { return ceilDiv(i*m-alignWindow*n, n) }
+	hi := func(i int) int { return min(m-1, (i*m+alignWindow*n)/n) }
+	band := make([][]float64, n)
+	best := func(i, j int) float64 {
+		// Right of the band, rows that can't reach roman line j add
+		// nothing; left of it, roman lines that no later row can reach
+		// add nothing
+		if i < n && j < m && j > hi(i) {
+			i = max(i, ceilDiv(j*n-alignWindow*n, m))
+		}
+		if i >= n || j >= m {
+			return 0
+		}
+		return band[i][max(j, lo(i))-lo(i)]
+	}
+	for i := n - 1; i >= 0; i-- {
+		band[i] = make([]float64, hi(i)-lo(i)+1)
+		for j := hi(i); j >= lo(i); j-- {
+			b := max(best(i+1, j), best(i, j+1))
+			if inWindow(i, j) {
+				if s := score(i, j); s >= minAlignScore && best(i+1, j+1)+s > b {
+					b = best(i+1, j+1) + s
+				}
+			}
+			band[i][j-lo(i)] = b
+		}
+	}
+
+	pairs := []CorpusPair{}
+	rejections := []CorpusRejection{}
+	i, j := 0, 0
+	for i < n && j < m {
+		if inWindow(i, j) {
+			if s := score(i, j); s >= minAlignScore && best(i, j) == best(i+1, j+1)+s {
+				pairs = append(pairs, CorpusPair{source, thai[i].num, thai[i].text, roman[j].text})
+				i++
+				j++
+				continue
+			}
+		}
+		if best(i, j) == best(i+1, j) {
+			rejections = append(rejections, CorpusRejection{source, thai[i].num, thai[i].text, "no matching romanized line"})
+			i++
+		} else {
+			rejections = append(rejections, CorpusRejection{source, 0, roman[j].text, "no matching Thai line"})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		rejections = append(rejections, CorpusRejection{source, thai[i].num, thai[i].text, "no matching romanized line"})
+	}
+	for ; j < m; j++ {
+		rejections = append(rejections, CorpusRejection{source, 0, roman[j].text, "no matching Thai line"})
+	}
+	return pairs, rejections
+}
+
+// normalizeSubtitleLine strips a BOM, turns Aegisub \N breaks into spaces
+// and collapses whitespace
+func normalizeSubtitleLine(s string) string {
+	s = strings.TrimPrefix(s, "\ufeff")
+	s = strings.ReplaceAll(s, `\N`, " ")
+	return strings.Join(strings.Fields(norm.NFC.String(s)), " ")
+}
+
+// alignWords returns the toneless syllables of a romanized line, the unit
+// AlignSubtitles compares lines on
+func alignWords(roman string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.Fields(strings.ToLower(stripRomanTones(roman))) {
+		for _, syl := range romanSyllableSep.Split(strings.Trim(w, ".,!?\"'()…"), -1) {
+			if syl != "" {
+				words[syl] = true
+			}
+		}
+	}
+	return words
+}
+
+// WriteCorpusTSV writes pairs as a parallel corpus TSV file: a commented
+// header, then one Source, Line, Thai, Romanization row per pair. Tabs
+// inside fields are replaced with spaces.
+func WriteCorpusTSV(w io.Writer, pairs []CorpusPair) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(corpusHeader)
+	field := func(s string) string { return strings.ReplaceAll(s, "\t", " ") }
+	for _, p := range pairs {
+		fmt.Fprintf(bw, "%s\t%d\t%s\t%s\n", field(p.Source), p.Line, field(p.Thai), field(p.Roman))
+	}
+	return bw.Flush()
+}
+
+// ReadCorpusTSV reads a file written by WriteCorpusTSV. Comment and empty
+// lines are skipped.
+func ReadCorpusTSV(r io.Reader) ([]CorpusPair, error) {
+	pairs := []CorpusPair{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		text := scanner.Text()
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("line %d: expected 4 fields, got %d", n, len(fields))
+		}
+		line, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid line number %q", n, fields[1])
+		}
+		pairs = append(pairs, CorpusPair{fields[0], line, fields[2], fields[3]})
+	}
+	return pairs, scanner.Err()
+}
+
+// ceilDiv returns a/b rounded up, 0 when a is negative; b is positive
+func ceilDiv(a, b int) int {
+	if a <= 0 {
+		return 0
+	}
+	return (a + b - 1) / b
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
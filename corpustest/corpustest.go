@@ -0,0 +1,526 @@
+// Package corpustest evaluates a transliteration pipeline against a corpus
+// of Thai subtitle files and their reference romanizations, the way the
+// paiboonizer test suite does, so that other pipelines (translitkit,
+// downstream apps) can be measured on the same terms.
+package corpustest
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ReferenceSuffix is appended to the base name of a Thai subtitle file
+// (testN.txt) to name its reference romanization
+const ReferenceSuffix = "_Opus4.5_transliterated"
+
+// Pair is a Thai subtitle file and its reference romanization, line by line
+type Pair struct {
+	Name     string
+	Input    []string
+	Expected []string
+}
+
+// Discover finds all testN.txt + testN_Opus4.5_transliterated.txt pairs in
+// dir, sorted naturally (test2 before test10). Files without a reference
+// or whose line counts differ are left out and reported as warnings.
+func Discover(dir string) ([]Pair, []string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "test*.txt"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pairs []Pair
+	var warnings []string
+	for _, inputPath := range matches {
+		// Skip transliterated files
+		if strings.Contains(inputPath, ReferenceSuffix) {
+			continue
+		}
+
+		// Derive expected path: testN.txt -> testN_Opus4.5_transliterated.txt
+		base := strings.TrimSuffix(filepath.Base(inputPath), ".txt")
+		expectedPath := filepath.Join(filepath.Dir(inputPath), base+ReferenceSuffix+".txt")
+
+		// Check expected file exists
+		if _, err := os.Stat(expectedPath); os.IsNotExist(err) {
+			warnings = append(warnings, fmt.Sprintf("No transliteration for %s, skipping", base))
+			continue
+		}
+
+		// Load files
+		inputs, err := LoadLines(inputPath)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Failed to load %s: %v", inputPath, err))
+			continue
+		}
+		expected, err := LoadLines(expectedPath)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Failed to load %s: %v", expectedPath, err))
+			continue
+		}
+
+		// VALIDATION: Line count must match
+		if len(inputs) != len(expected) {
+			warnings = append(warnings, fmt.Sprintf("Line mismatch in %s: %d vs %d, skipping", base, len(inputs), len(expected)))
+			continue
+		}
+
+		pairs = append(pairs, Pair{
+			Name:     base,
+			Input:    inputs,
+			Expected: expected,
+		})
+	}
+
+	// Sort for consistent order (test1, test2, test8...)
+	sort.Slice(pairs, func(i, j int) bool {
+		return NaturalLess(pairs[i].Name, pairs[j].Name)
+	})
+
+	return pairs, warnings, nil
+}
+
+var numberRegex = regexp.MustCompile(`\d+`)
+
+// NaturalLess compares strings with embedded numbers naturally
+// e.g., "test2" < "test10"
+func NaturalLess(a, b string) bool {
+	numA := extractNumber(a)
+	numB := extractNumber(b)
+	if numA != numB {
+		return numA < numB
+	}
+	return a < b
+}
+
+// extractNumber extracts the first number from a string
+func extractNumber(s string) int {
+	match := numberRegex.FindString(s)
+	if match == "" {
+		return 0
+	}
+	n, _ := strconv.Atoi(match)
+	return n
+}
+
+// LoadLines reads a file and returns all lines
+// Aegisub \N markers are replaced with single spaces
+func LoadLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// Replace Aegisub subtitle line breaks with single space
+		line = strings.ReplaceAll(line, "\\N", " ")
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// Func transliterates one line of Thai text
+type Func func(line string) (string, error)
+
+// Policy selects how Run treats a class of lines the references and the
+// engines write inconsistently, so that its effect on the reported
+// accuracy is a visible choice
+type Policy int
+
+const (
+	PolicyDefault   Policy = iota // The handling documented for each Options field
+	PolicySkip                    // Leave the lines out of the measure
+	PolicyNormalize               // Measure the lines, the difference normalized away
+	PolicyStrict                  // Measure the lines as written: the difference counts
+)
+
+// String returns the name of the policy
+func (p Policy) String() string {
+	switch p {
+	case PolicyDefault:
+		return "default"
+	case PolicySkip:
+		return "skip"
+	case PolicyNormalize:
+		return "normalize"
+	case PolicyStrict:
+		return "strict"
+	}
+	return "unknown"
+}
+
+// or returns p, def for PolicyDefault
+func (p Policy) or(def Policy) Policy {
+	if p == PolicyDefault {
+		return def
+	}
+	return p
+}
+
+// Options selects the lines Run leaves out besides empty lines and
+// Aegisub headers, and how the lines measured are compared
+type Options struct {
+	// Digits covers lines whose input has Arabic numerals, which the
+	// references read in many ways. The default, PolicySkip, leaves them
+	// out; PolicyNormalize spells out the numerals of both sides as Thai
+	// numbers; PolicyStrict compares them as written.
+	Digits Policy
+	// Accents covers lines whose reference uses precomposed accented
+	// vowels Paiboon doesn't use (see HasPrecomposedAccents). The default,
+	// PolicyNormalize, compares every line in NFC; PolicyStrict compares
+	// code points as written, on every line.
+	Accents Policy
+	// Particles covers the tone of the question particle ไหม (mǎi, mái)
+	// and of ว่ะ (wà, wâ), on which the references disagree. The default,
+	// PolicyNormalize, accepts either tone; PolicySkip leaves out the
+	// lines containing them.
+	Particles Policy
+	// SkipRepetition leaves out lines containing ๆ, which needs ML
+	// segmentation to be parsed correctly
+	SkipRepetition bool
+	// StrictSeparators keeps the syllable separators - and ~ significant
+	// instead of splitting words on them, so that segmentation boundary
+	// errors count (see NormalizeStrict)
+	StrictSeparators bool
+}
+
+// skip returns the name of the policy leaving out the line of input
+// whose reference is expected, "" if it is measured
+func (o Options) skip(input, expected string) string {
+	switch {
+	case o.Digits.or(PolicySkip) == PolicySkip && containsDigit(input):
+		return "digits"
+	case o.Accents.or(PolicyNormalize) == PolicySkip && HasPrecomposedAccents(expected):
+		return "accents"
+	case o.Particles.or(PolicyNormalize) == PolicySkip && (strings.Contains(input, "ไหม") || strings.Contains(input, "ว่ะ")):
+		return "particles"
+	case o.SkipRepetition && strings.Contains(input, "ๆ"):
+		return "repetition"
+	}
+	return ""
+}
+
+// Failure is a line whose transliteration doesn't match its reference
+type Failure struct {
+	File     string
+	Line     int // 1-based line number in the source file
+	Input    string
+	Expected string
+	Got      string
+}
+
+// LineError is a line the transliteration function failed on
+type LineError struct {
+	File string
+	Line int
+	Err  error
+}
+
+// Result contains the results of Run
+type Result struct {
+	Lines        int // Lines measured, errors included
+	LinesCorrect int
+	Words        int // Words in the references of the lines measured
+	WordsCorrect int
+	// SeparatorMismatches counts the lines that only differ from their
+	// reference in syllable separators; they are failures in strict mode
+	// and matches otherwise
+	SeparatorMismatches int
+	// Skipped counts the lines left out by each policy: "digits",
+	// "accents", "particles" and "repetition"
+	Skipped  map[string]int
+	Failures []Failure
+	Errors   []LineError
+}
+
+// LineAccuracy returns the percentage of lines matching their reference
+func (r Result) LineAccuracy() float64 {
+	if r.Lines == 0 {
+		return 0
+	}
+	return float64(r.LinesCorrect) / float64(r.Lines) * 100
+}
+
+// WordAccuracy returns the percentage of reference words found, in order,
+// in the transliteration
+func (r Result) WordAccuracy() float64 {
+	if r.Words == 0 {
+		return 0
+	}
+	return float64(r.WordsCorrect) / float64(r.Words) * 100
+}
+
+// Run transliterates every line of corpus with transliterate and compares
+// the output with the references, normalized as opts says
+func Run(corpus []Pair, transliterate Func, opts Options) Result {
+	result := Result{Skipped: make(map[string]int)}
+	loose, strict := opts, opts
+	loose.StrictSeparators, strict.StrictSeparators = false, true
+	for _, p := range corpus {
+		for i := range p.Input {
+			input := strings.TrimSpace(p.Input[i])
+			// Remove BOM
+			input = strings.TrimPrefix(input, "\ufeff")
+			exp := opts.normalize(p.Expected[i])
+
+			if input == "" || exp == "" {
+				continue
+			}
+			// Skip Aegisub header lines
+			if strings.HasPrefix(input, "#") && strings.Contains(input, "Aegisub") {
+				continue
+			}
+			if reason := opts.skip(input, p.Expected[i]); reason != "" {
+				result.Skipped[reason]++
+				continue
+			}
+			result.Lines++
+
+			out, err := transliterate(input)
+			if err != nil {
+				result.Errors = append(result.Errors, LineError{File: p.Name, Line: i + 1, Err: err})
+				continue
+			}
+
+			got := opts.normalize(out)
+			if loose.normalize(out) == loose.normalize(p.Expected[i]) && strict.normalize(out) != strict.normalize(p.Expected[i]) {
+				result.SeparatorMismatches++
+			}
+
+			// Line-level accuracy
+			if got == exp {
+				result.LinesCorrect++
+			} else {
+				result.Failures = append(result.Failures, Failure{
+					File:     p.Name,
+					Line:     i + 1,
+					Input:    input,
+					Expected: p.Expected[i],
+					Got:      out,
+				})
+			}
+
+			// Word-level accuracy
+			expWords := SplitWords(exp)
+			result.Words += len(expWords)
+			result.WordsCorrect += CountMatchingWords(expWords, SplitWords(got))
+		}
+	}
+	return result
+}
+
+// punctuationRegex matches Unicode punctuation characters
+var punctuationRegex = regexp.MustCompile(`[\p{P}\p{S}]`)
+
+// Normalize prepares strings for comparison: NFC, lower case, no
+// punctuation (syllable separators included), single spaces, ambiguous
+// particle tones and Arabic numerals normalized. It is the normalization
+// of Run under the default Options.
+func Normalize(s string) string {
+	return Options{}.normalize(s)
+}
+
+// NormalizeStrict is Normalize keeping the syllable separators - and ~
+func NormalizeStrict(s string) string {
+	return Options{StrictSeparators: true}.normalize(s)
+}
+
+// normalize implements Normalize under the policies of o
+func (o Options) normalize(s string) string {
+	// Remove BOM if present
+	s = strings.TrimPrefix(s, "\ufeff")
+	if o.Accents.or(PolicyNormalize) != PolicyStrict {
+		s = norm.NFC.String(s)
+	}
+	s = strings.TrimSpace(s)
+	s = strings.ToLower(s)
+	// Remove all Unicode punctuation and symbols
+	s = punctuationRegex.ReplaceAllStringFunc(s, func(m string) string {
+		if o.StrictSeparators && (m == "-" || m == "~") {
+			return m
+		}
+		return " "
+	})
+	// Normalize ALL whitespace (tabs, multiple spaces, etc.) to single space
+	fields := strings.Fields(s)
+	s = strings.Join(fields, " ")
+	if o.Particles.or(PolicyNormalize) == PolicyNormalize {
+		s = normalizeParticles(s)
+	}
+	// Normalize numbers to Thai romanization for fair comparison
+	if o.Digits.or(PolicySkip) != PolicyStrict {
+		s = normalizeNumbers(s)
+	}
+	return s
+}
+
+// normalizeParticles gives the particles whose tone the references
+// disagree on a single toneless reading
+func normalizeParticles(s string) string {
+	// Normalize ambiguous tones (both are valid for ไหม question particle)
+	s = strings.ReplaceAll(s, " mǎi ", " mai ")
+	s = strings.ReplaceAll(s, " mái ", " mai ")
+	if strings.HasSuffix(s, " mǎi") {
+		s = s[:len(s)-len(" mǎi")] + " mai"
+	}
+	if strings.HasSuffix(s, " mái") {
+		s = s[:len(s)-len(" mái")] + " mai"
+	}
+	// Normalize ambiguous tones (both wà and wâ valid for ว่ะ particle)
+	s = strings.ReplaceAll(s, " wà ", " wa ")
+	s = strings.ReplaceAll(s, " wâ ", " wa ")
+	if strings.HasSuffix(s, " wà") {
+		s = s[:len(s)-len(" wà")] + " wa"
+	}
+	if strings.HasSuffix(s, " wâ") {
+		s = s[:len(s)-len(" wâ")] + " wa"
+	}
+	return s
+}
+
+// normalizeNumbers converts Arabic numerals to Thai number romanization
+func normalizeNumbers(s string) string {
+	// Find and replace number sequences
+	var result strings.Builder
+	i := 0
+	runes := []rune(s)
+
+	for i < len(runes) {
+		if runes[i] >= '0' && runes[i] <= '9' {
+			// Collect the full number
+			numStart := i
+			for i < len(runes) && runes[i] >= '0' && runes[i] <= '9' {
+				i++
+			}
+			numStr := string(runes[numStart:i])
+			thai := numberToThai(numStr)
+			if result.Len() > 0 && result.String()[result.Len()-1] != ' ' {
+				result.WriteString(" ")
+			}
+			result.WriteString(thai)
+		} else {
+			result.WriteRune(runes[i])
+			i++
+		}
+	}
+	return result.String()
+}
+
+// numberToThai converts an Arabic numeral string to Thai romanization
+func numberToThai(num string) string {
+	units := []string{"", "nʉ̀ng", "sɔ̌ɔng", "sǎam", "sìi", "hâa", "hòk", "jèt", "bpɛ̀ɛt", "gâao"}
+	tens := []string{"", "sìp", "yîi sìp", "sǎam sìp", "sìi sìp", "hâa sìp", "hòk sìp", "jèt sìp", "bpɛ̀ɛt sìp", "gâao sìp"}
+
+	// Handle single digit
+	if len(num) == 1 {
+		d := int(num[0] - '0')
+		if d == 0 {
+			return "sǔun"
+		}
+		return units[d]
+	}
+
+	// Handle two digits (10-99)
+	if len(num) == 2 {
+		t := int(num[0] - '0')
+		u := int(num[1] - '0')
+		result := tens[t]
+		if u > 0 {
+			if u == 1 && t > 0 {
+				result += " èt" // Special: 11, 21, 31... use "èt" not "nʉ̀ng"
+			} else {
+				result += " " + units[u]
+			}
+		}
+		return result
+	}
+
+	// For larger numbers, just convert digit by digit for simplicity
+	var parts []string
+	for _, r := range num {
+		d := int(r - '0')
+		if d == 0 {
+			parts = append(parts, "sǔun")
+		} else {
+			parts = append(parts, units[d])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// containsDigit checks if a string contains Arabic numerals (0-9)
+func containsDigit(s string) bool {
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPrecomposedAccents checks if ground truth uses precomposed accented vowels
+// that official Paiboon doesn't use. Paiboon uses precomposed à, á, â, ǎ, ě, ǐ, ǒ, ǔ
+// but uses combining marks for e, i, o, u with grave/acute/circumflex.
+// Skip only if ground truth has precomposed forms Paiboon doesn't use.
+func HasPrecomposedAccents(s string) bool {
+	for _, r := range s {
+		switch r {
+		// e with grave/acute/circumflex (Paiboon uses combining, not precomposed)
+		case 'è', 'é', 'ê': // U+00E8-EA
+			return true
+		// i with grave/acute/circumflex
+		case 'ì', 'í', 'î': // U+00EC-EE
+			return true
+		// o with grave/acute/circumflex
+		case 'ò', 'ó', 'ô': // U+00F2-F4
+			return true
+		// u with grave/acute/circumflex
+		case 'ù', 'ú', 'û': // U+00F9-FB
+			return true
+		}
+	}
+	return false
+}
+
+// SplitWords splits a romanized string into words by spaces
+func SplitWords(s string) []string {
+	var words []string
+	for _, w := range strings.Fields(s) {
+		w = strings.TrimSpace(w)
+		if w != "" && w != "-" && w != "~" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// CountMatchingWords counts how many words from expected appear in got (order-sensitive)
+func CountMatchingWords(expected, got []string) int {
+	matches := 0
+	gotIdx := 0
+
+	for _, expWord := range expected {
+		// Look for this expected word in the remaining got words
+		for gotIdx < len(got) {
+			if got[gotIdx] == expWord {
+				matches++
+				gotIdx++
+				break
+			}
+			gotIdx++
+		}
+	}
+	return matches
+}
@@ -0,0 +1,43 @@
+package paiboonizer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// OutputChecksum romanizes words with TransliterateWordWithOptions on
+// workers goroutines and returns a checksum of the outputs in input order.
+// Romanization has no stochastic component: segmentation takes the
+// longest match, ties in the tables are broken by fixed orders (sorted
+// keys, file order) and the only randomized code, AdversarialSyllables,
+// takes an explicit seed. The checksum is therefore identical for
+// identical words, options and dictionary whatever the number of workers
+// or GOMAXPROCS, which the determinism command of cmd checks.
+func OutputChecksum(words []string, opts Options, workers int) string {
+	ensureDictionaryLoaded()
+	outputs := make([]string, len(words))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				outputs[i] = TransliterateWordWithOptions(words[i], opts)
+			}
+		}()
+	}
+	for i := range words {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	h := sha256.New()
+	for _, out := range outputs {
+		h.Write([]byte(out))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
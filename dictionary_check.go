@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"unicode"
 
 	"github.com/tassa-yoniso-manasi-karoto/go-pythainlp"
@@ -31,8 +33,8 @@ const (
 	TestModeFullDictionary                 // Full dictionary lookup (baseline)
 )
 
-// Track pythainlp failures that fell back to pure rules
-var pythainlpFallbackCount int
+// Track pythainlp failures that fell back to pure rules, from any worker
+var pythainlpFallbackCount atomic.Int64
 
 // DictTestFailure represents a single test failure
 type DictTestFailure struct {
@@ -53,66 +55,104 @@ type DictTestResults struct {
 	ToneErrors         int
 	VowelErrors        int
 	ConsonantErrors    int
+	// Strict is set when syllable separators (- and ~) were significant
+	Strict bool
+	// SeparatorErrors counts entries whose romanization is right but for
+	// its syllable separators, i.e. a segmentation boundary error. They
+	// fail in strict mode and pass otherwise.
+	SeparatorErrors int
+	// Syllables aligns the syllables of the entries where both the
+	// expected and the produced romanization are syllable separated
+	Syllables SyllableStats
 }
 
-// RunDictionaryTest runs dictionary test and returns results
-func RunDictionaryTest(mode TestMode) DictTestResults {
+// DefaultMaxFailures is the number of failures RunDictionaryTest records
+// when DictTestOptions.MaxFailures is 0
+const DefaultMaxFailures = 50
+
+// DictTestOptions configures RunDictionaryTest. The zero value tests every
+// single-word entry in TestModePureRules on one goroutine, separators
+// ignored, recording up to DefaultMaxFailures failures.
+type DictTestOptions struct {
+	Mode TestMode
+	// MaxFailures caps DictTestResults.Failures, and so the failures the
+	// tone, vowel and consonant error counts are computed from:
+	// DefaultMaxFailures if 0, no cap if negative
+	MaxFailures int
+	// Workers is the number of goroutines romanizing entries, 1 if <= 0.
+	// Results are identical whatever the number.
+	Workers int
+	// Sample is the number of entries tested, spread evenly over the
+	// sorted dictionary; all of them if <= 0
+	Sample int
+	// CompareStrict makes syllable separators significant: "nâa-dtàang"
+	// and "nâa~dtàang" don't match, exposing segmentation boundary errors
+	// the default comparison hides
+	CompareStrict bool
+	// Categories restricts the test to the entries tagged with at least
+	// one of these orthographic categories (CategoryHoLead, ...); all
+	// entries if empty. Sampling applies after the restriction.
+	Categories []string
+}
+
+// RunDictionaryTest romanizes the single-word official dictionary entries
+// as opts.Mode prescribes and compares them with their dictionary
+// romanization
+func RunDictionaryTest(opts DictTestOptions) DictTestResults {
 	ensureDictionaryLoaded()
-	if mode == TestModePythainlp {
-		pythainlpFallbackCount = 0
+	if opts.Mode == TestModePythainlp {
+		pythainlpFallbackCount.Store(0)
+	}
+	maxFailures := opts.MaxFailures
+	if maxFailures == 0 {
+		maxFailures = DefaultMaxFailures
 	}
 
 	passed := 0
 	total := 0
+	separatorErrors := 0
+	var syllables SyllableStats
 	var failures []DictTestFailure
 
-	// Sort dictionary keys for deterministic iteration order
-	sortedKeys := make([]string, 0, len(dictionary))
-	for k := range dictionary {
-		sortedKeys = append(sortedKeys, k)
+	sortedKeys := dictionaryTestKeys(opts)
+
+	// Romanize on the workers, then tally in deterministic order
+	results := make([]string, len(sortedKeys))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range max(opts.Workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = dictionaryTestTransliterate(opts.Mode, sortedKeys[i])
+			}
+		}()
 	}
-	sort.Strings(sortedKeys)
+	for i := range sortedKeys {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 
 	// Test each dictionary entry in deterministic order
-	for _, thai := range sortedKeys {
-		expected := dictionary[thai]
-		// Skip multi-word phrases for now
-		if strings.Contains(thai, " ") {
-			continue
-		}
-
+	for i, thai := range sortedKeys {
+		expected := dictionary[thai].Roman
 		total++
 
-		// Strip special markers from Thai text before transliteration
-		cleanThai := stripSpecialMarkers(thai)
-
-		// Transliterate based on mode
-		var result string
-		switch mode {
-		case TestModePureRules:
-			result = ComprehensiveTransliterate(cleanThai)
-		case TestModePythainlp:
-			result = transliterateWithPythainlp(cleanThai)
-		case TestModeFullDictionary:
-			result = TransliterateWordRulesOnly(cleanThai)
+		result := results[i]
+		if cleanExpected := stripSpecialMarkers(expected); strings.ContainsAny(cleanExpected, "-~") && strings.ContainsAny(result, "-~") {
+			syllables.Add(AlignSyllables(cleanExpected, result))
 		}
-
-		// Strip special markers from expected result too
-		cleanExpected := stripSpecialMarkers(expected)
-
-		// Remove hyphens and tildes for comparison
-		expectedNoSep := strings.ReplaceAll(strings.ReplaceAll(cleanExpected, "-", ""), "~", "")
-		resultNoSep := strings.ReplaceAll(strings.ReplaceAll(result, "-", ""), "~", "")
-
-		// Also normalize Unicode for fair comparison
-		t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
-		resultNorm, _, _ := transform.String(t, resultNoSep)
-		expectedNorm, _, _ := transform.String(t, expectedNoSep)
-
-		if resultNoSep == expectedNoSep || resultNorm == expectedNorm {
+		match := dictionaryTestMatches(result, expected, false)
+		if match && !dictionaryTestMatches(result, expected, true) {
+			separatorErrors++
+			match = !opts.CompareStrict
+		}
+		if match {
 			passed++
 		} else {
-			if len(failures) < 50 {
+			if maxFailures < 0 || len(failures) < maxFailures {
 				failures = append(failures, DictTestFailure{
 					Thai:     thai,
 					Expected: expected,
@@ -142,20 +182,90 @@ func RunDictionaryTest(mode TestMode) DictTestResults {
 		}
 	}
 
+	// Slim builds have no dictionary to test
+	accuracy := 0.0
+	if total > 0 {
+		accuracy = float64(passed) * 100 / float64(total)
+	}
 	return DictTestResults{
-		Mode:               mode,
+		Mode:               opts.Mode,
 		Total:              total,
 		Passed:             passed,
 		Failed:             total - passed,
-		Accuracy:           float64(passed) * 100 / float64(total),
-		PythainlpFallbacks: pythainlpFallbackCount,
+		Accuracy:           accuracy,
+		PythainlpFallbacks: int(pythainlpFallbackCount.Load()),
 		Failures:           failures,
 		ToneErrors:         toneErrors,
 		VowelErrors:        vowelErrors,
 		ConsonantErrors:    consonantErrors,
+		Strict:             opts.CompareStrict,
+		SeparatorErrors:    separatorErrors,
+		Syllables:          syllables,
 	}
 }
 
+// dictionaryTestKeys returns the entries RunDictionaryTest tests under
+// opts, sorted
+func dictionaryTestKeys(opts DictTestOptions) []string {
+	// Sort dictionary keys for deterministic iteration order, skipping
+	// multi-word phrases for now
+	sortedKeys := make([]string, 0, len(dictionary))
+	for k := range dictionary {
+		if !strings.Contains(k, " ") && inCategories(k, opts.Categories) {
+			sortedKeys = append(sortedKeys, k)
+		}
+	}
+	sort.Strings(sortedKeys)
+	if opts.Sample > 0 && opts.Sample < len(sortedKeys) {
+		sampled := make([]string, opts.Sample)
+		for i := range sampled {
+			sampled[i] = sortedKeys[i*len(sortedKeys)/opts.Sample]
+		}
+		sortedKeys = sampled
+	}
+	return sortedKeys
+}
+
+// dictionaryTestTransliterate transliterates a dictionary entry the way
+// mode prescribes
+func dictionaryTestTransliterate(mode TestMode, thai string) string {
+	// Strip special markers from Thai text before transliteration
+	cleanThai := stripSpecialMarkers(thai)
+
+	// Transliterate based on mode
+	switch mode {
+	case TestModePureRules:
+		return ComprehensiveTransliterate(cleanThai)
+	case TestModePythainlp:
+		return transliterateWithPythainlp(cleanThai)
+	case TestModeFullDictionary:
+		return TransliterateWordRulesOnly(cleanThai)
+	}
+	return ""
+}
+
+// dictionaryTestMatches reports whether result counts as a pass against
+// the expected romanization of a dictionary entry. Syllable separators are
+// ignored unless strict is set.
+func dictionaryTestMatches(result, expected string, strict bool) bool {
+	// Strip special markers from expected result too
+	cleanExpected := stripSpecialMarkers(expected)
+
+	// Remove hyphens and tildes for comparison
+	expectedNoSep, resultNoSep := cleanExpected, result
+	if !strict {
+		expectedNoSep = strings.ReplaceAll(strings.ReplaceAll(cleanExpected, "-", ""), "~", "")
+		resultNoSep = strings.ReplaceAll(strings.ReplaceAll(result, "-", ""), "~", "")
+	}
+
+	// Also normalize Unicode for fair comparison
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	resultNorm, _, _ := transform.String(t, resultNoSep)
+	expectedNorm, _, _ := transform.String(t, expectedNoSep)
+
+	return resultNoSep == expectedNoSep || resultNorm == expectedNorm
+}
+
 // transliterateWithPythainlp uses pythainlp for syllable tokenization
 // then transliterates each syllable using rules (no whole-word dictionary lookup)
 func transliterateWithPythainlp(word string) string {
@@ -163,18 +273,17 @@ func transliterateWithPythainlp(word string) string {
 
 	if globalManager != nil && globalManager.nlpManager != nil {
 		// Use paiboonizer's own manager (standalone mode)
-		ctx := context.Background()
-		result, err := globalManager.nlpManager.SyllableTokenize(ctx, word)
-		if err != nil || result == nil || len(result.Syllables) == 0 {
-			pythainlpFallbackCount++
+		var err error
+		syllables, err = globalManager.syllableTokenize(context.Background(), word)
+		if err != nil || len(syllables) == 0 {
+			pythainlpFallbackCount.Add(1)
 			return ComprehensiveTransliterate(word)
 		}
-		syllables = result.Syllables
 	} else {
 		// Try package-level function (uses default manager set by translitkit)
 		result, err := pythainlp.SyllableTokenize(word)
 		if err != nil || result == nil || len(result.Syllables) == 0 {
-			pythainlpFallbackCount++
+			pythainlpFallbackCount.Add(1)
 			return ComprehensiveTransliterate(word)
 		}
 		syllables = result.Syllables
@@ -202,11 +311,8 @@ func transliterateWithPythainlp(word string) string {
 
 		var trans string
 
-		// Try syllable dictionary first (but NOT whole-word dictionary)
-		if t, ok := syllableDict[cleanSyllable]; ok {
-			trans = t
-		} else if t, ok := specialCasesGlobal[cleanSyllable]; ok {
-			// Try special cases for this syllable
+		// Try syllable dictionary, then special cases (but NOT whole-word dictionary)
+		if t, _, ok := lookupWord(cleanSyllable, Options{Layers: pythainlpSyllableLayers}); ok {
 			trans = t
 		} else {
 			// Fall back to rule-based transliteration for this syllable
@@ -259,7 +365,7 @@ func DebugTransliteration(word string) {
 	fmt.Printf("\n=== Debug: %s ===\n", word)
 
 	// Show expected from dictionary
-	if expected, ok := dictionary[word]; ok {
+	if expected, ok := dictionary.roman(word); ok {
 		fmt.Printf("Expected (dictionary): %s\n", expected)
 	}
 
@@ -0,0 +1,92 @@
+package paiboonizer
+
+import (
+	"strings"
+)
+
+// DictValue is the value of a dictionary entry. Only Roman is used for
+// romanization; the other fields are optional and carry what a bare
+// romanization string couldn't without another format migration.
+type DictValue struct {
+	Roman string // The reading used when romanizing
+	// Variants are other accepted readings of the spelling, such as the
+	// readings of a homograph or a colloquial pronunciation
+	Variants []string
+	// Tags are free-form labels such as part of speech or register
+	// ("pos:n", "register:formal")
+	Tags  []string
+	Notes string
+}
+
+// String returns the romanization, so that a DictValue prints like the
+// bare string dictionary values used to be
+func (v DictValue) String() string {
+	return v.Roman
+}
+
+// HasTag reports whether v is tagged tag
+func (v DictValue) HasTag(tag string) bool {
+	for _, t := range v.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// dictTable maps Thai spellings to their dictionary values
+type dictTable map[string]DictValue
+
+// roman returns the romanization of word, the lookup most callers need
+func (t dictTable) roman(word string) (string, bool) {
+	v, ok := t[word]
+	return v.Roman, ok
+}
+
+// romanizations returns the table with its values reduced to their
+// romanization
+func (t dictTable) romanizations() map[string]string {
+	m := make(map[string]string, len(t))
+	for k, v := range t {
+		m[k] = v.Roman
+	}
+	return m
+}
+
+// parseDictValue reads the columns of a dictionary TSV row after the Thai
+// spelling: the romanization, then optionally comma-separated variants,
+// comma-separated tags and notes. Missing columns are left empty, so
+// two-column files read as before.
+func parseDictValue(columns []string) DictValue {
+	list := func(i int) []string {
+		if i >= len(columns) {
+			return nil
+		}
+		var items []string
+		for _, item := range strings.Split(columns[i], ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	v := DictValue{Variants: list(1), Tags: list(2)}
+	if len(columns) > 0 {
+		v.Roman = strings.TrimSpace(columns[0])
+	}
+	if len(columns) > 3 {
+		v.Notes = strings.TrimSpace(columns[3])
+	}
+	return v
+}
+
+// LookupDictionaryValue is LookupDictionary returning the whole value of
+// the entry, with its variants, tags and notes
+func LookupDictionaryValue(word string) (DictValue, bool) {
+	ensureDictionaryLoaded()
+	if v, ok := dictionary[word]; ok {
+		return v, true
+	}
+	v, ok := opusDictionary[word]
+	return v, ok
+}
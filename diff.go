@@ -0,0 +1,204 @@
+package paiboonizer
+
+import (
+	"html"
+	"strings"
+
+	"github.com/gookit/color"
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DiffKind classifies a difference between two romanizations
+type DiffKind int
+
+const (
+	DiffEqual     DiffKind = iota // Same graphemes on both sides
+	DiffTone                      // Same letters, different tone mark
+	DiffVowel                     // Only vowel letters differ
+	DiffConsonant                 // At least one consonant letter differs
+	DiffOther                     // Spacing, hyphens, punctuation
+)
+
+// String returns the kind name
+func (k DiffKind) String() string {
+	switch k {
+	case DiffEqual:
+		return "equal"
+	case DiffTone:
+		return "tone"
+	case DiffVowel:
+		return "vowel"
+	case DiffConsonant:
+		return "consonant"
+	}
+	return "other"
+}
+
+// DiffSegment is a run of graphemes that is either equal on both sides or
+// differs between the expected and the actual romanization
+type DiffSegment struct {
+	Expected string
+	Got      string
+	Kind     DiffKind
+}
+
+// DiffGraphemes aligns expected and got grapheme by grapheme (a letter and
+// its tone mark count as one grapheme) and returns the equal and differing
+// runs in order. Inputs are compared in NFC.
+func DiffGraphemes(expected, got string) []DiffSegment {
+	a := graphemeClusters(norm.NFC.String(expected))
+	b := graphemeClusters(norm.NFC.String(got))
+
+	// Longest common subsequence table, filled from the end
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	segments := []DiffSegment{}
+	var exp, gt strings.Builder
+	flushDiff := func() {
+		if exp.Len() > 0 || gt.Len() > 0 {
+			segments = append(segments, DiffSegment{
+				Expected: exp.String(),
+				Got:      gt.String(),
+				Kind:     classifyDiff(exp.String(), gt.String()),
+			})
+			exp.Reset()
+			gt.Reset()
+		}
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			flushDiff()
+			if n := len(segments); n > 0 && segments[n-1].Kind == DiffEqual {
+				segments[n-1].Expected += a[i]
+				segments[n-1].Got += a[i]
+			} else {
+				segments = append(segments, DiffSegment{Expected: a[i], Got: a[i], Kind: DiffEqual})
+			}
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			gt.WriteString(b[j])
+			j++
+		default:
+			exp.WriteString(a[i])
+			i++
+		}
+	}
+	flushDiff()
+	return segments
+}
+
+// DiffANSI returns expected and got with the differing graphemes colored
+// for a terminal: tone in yellow, vowel in magenta, consonant in red and
+// anything else in cyan.
+func DiffANSI(expected, got string) (string, string) {
+	var exp, gt strings.Builder
+	colors := map[DiffKind]color.Color{
+		DiffTone:      color.FgYellow,
+		DiffVowel:     color.FgMagenta,
+		DiffConsonant: color.FgRed,
+		DiffOther:     color.FgCyan,
+	}
+	write := func(b *strings.Builder, text string, kind DiffKind) {
+		if c, ok := colors[kind]; ok && text != "" {
+			text = c.Render(text)
+		}
+		b.WriteString(text)
+	}
+	for _, s := range DiffGraphemes(expected, got) {
+		write(&exp, s.Expected, s.Kind)
+		write(&gt, s.Got, s.Kind)
+	}
+	return exp.String(), gt.String()
+}
+
+// DiffHTML returns expected and got as escaped HTML where each differing
+// run is wrapped in <span class="diff-KIND">, KIND being one of tone,
+// vowel, consonant or other.
+func DiffHTML(expected, got string) (string, string) {
+	var exp, gt strings.Builder
+	write := func(b *strings.Builder, text string, kind DiffKind) {
+		if text == "" {
+			return
+		}
+		if kind == DiffEqual {
+			b.WriteString(html.EscapeString(text))
+			return
+		}
+		b.WriteString(`<span class="diff-` + kind.String() + `">`)
+		b.WriteString(html.EscapeString(text))
+		b.WriteString("</span>")
+	}
+	for _, s := range DiffGraphemes(expected, got) {
+		write(&exp, s.Expected, s.Kind)
+		write(&gt, s.Got, s.Kind)
+	}
+	return exp.String(), gt.String()
+}
+
+// graphemeClusters splits s into user-perceived characters
+func graphemeClusters(s string) []string {
+	clusters := []string{}
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		clusters = append(clusters, g.Str())
+	}
+	return clusters
+}
+
+// classifyDiff decides what kind of difference separates exp from got
+func classifyDiff(exp, got string) DiffKind {
+	baseExp, baseGot := stripRomanTones(exp), stripRomanTones(got)
+	if baseExp == baseGot {
+		return DiffTone
+	}
+	onlyVowels := true
+	for _, r := range baseExp + baseGot {
+		switch {
+		case isRomanVowel(r):
+		case r >= 'a' && r <= 'z':
+			return DiffConsonant
+		default:
+			onlyVowels = false
+		}
+	}
+	if onlyVowels {
+		return DiffVowel
+	}
+	return DiffOther
+}
+
+// StripTones removes the tone diacritics from a romanization, keeping the
+// Paiboon vowels (ʉ, ɛ, ɔ, ə) and any other marks: "mɛ̂ɛ" becomes "mɛɛ".
+// It converts the output of functions that take no Options; the others
+// leave tones out themselves under Options.Toneless.
+func StripTones(roman string) string {
+	return stripRomanTones(roman)
+}
+
+// stripRomanTones removes the Paiboon tone diacritics from s
+func stripRomanTones(s string) string {
+	return norm.NFC.String(strings.Map(func(r rune) rune {
+		if _, ok := toneByMark[r]; ok {
+			return -1
+		}
+		return r
+	}, norm.NFD.String(s)))
+}
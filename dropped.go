@@ -0,0 +1,74 @@
+package paiboonizer
+
+import (
+	"unicode/utf8"
+)
+
+// DroppedSpan is a piece of Thai input the rules romanized to nothing, a
+// coverage gap that would otherwise vanish from the output without a
+// trace. What takes its place in the output is set by a FailurePolicy.
+type DroppedSpan struct {
+	Text   string // Thai syllable or character
+	Offset int    // Position in the input, in runes
+}
+
+// FailurePolicy selects what the text pipelines write in place of Thai the
+// rules can't romanize (see DroppedSpan)
+type FailurePolicy int
+
+const (
+	FailurePassThrough FailurePolicy = iota // The Thai as is: bpai ๅ
+	FailureBracket                          // The Thai in brackets: bpai [ๅ]
+	FailureDrop                             // Nothing: bpai
+)
+
+// failureText returns what policy writes in place of thai
+func failureText(thai string, policy FailurePolicy) string {
+	switch policy {
+	case FailureBracket:
+		return "[" + thai + "]"
+	case FailureDrop:
+		return ""
+	}
+	return thai
+}
+
+// romanizeSyllables romanizes the syllables of a word one by one and joins
+// them as join says. Syllables romanized to nothing are reported with their
+// offset in the word and replaced as onFailure says.
+func romanizeSyllables(syllables []string, join JoinPolicy, onFailure FailurePolicy, romanize func(string) (string, []DroppedSpan)) (string, []DroppedSpan) {
+	results := []string{}
+	var dropped []DroppedSpan
+	offset := 0
+	for _, syl := range syllables {
+		trans, sylDropped := romanize(syl)
+		dropped = append(dropped, shiftDroppedSpans(sylDropped, offset)...)
+		if trans == "" && len(sylDropped) == 0 && syl != "" {
+			dropped = append(dropped, DroppedSpan{Text: syl, Offset: offset})
+			trans = failureText(syl, onFailure)
+		}
+		if trans != "" {
+			results = append(results, trans)
+		}
+		offset += utf8.RuneCountInString(syl)
+	}
+	return join.join(results), dropped
+}
+
+// lookupOrRuleSyllable romanizes a syllable with the syllable dictionary,
+// then transliterateSyllable
+func lookupOrRuleSyllable(syl string) (string, []DroppedSpan) {
+	if trans, ok := syllableDict[syl]; ok {
+		return trans, nil
+	}
+	return transliterateSyllable(syl), nil
+}
+
+// shiftDroppedSpans moves spans found in a part of the input starting at
+// offset to input positions
+func shiftDroppedSpans(spans []DroppedSpan, offset int) []DroppedSpan {
+	for i := range spans {
+		spans[i].Offset += offset
+	}
+	return spans
+}
@@ -0,0 +1,21 @@
+//go:build !paiboonizer_slim
+
+package paiboonizer
+
+import "embed"
+
+//go:generate go run gen_syllables.go
+//go:generate go run gen_frequency.go
+
+//go:embed csv/*.txt
+var vocabFS embed.FS
+
+//go:embed opus_dictionary.tsv
+var opusDictFS embed.FS
+
+// syllableFS is empty: full builds extract the syllable dictionary from the
+// vocab files at load time
+var syllableFS embed.FS
+
+// slimBuild reports whether the word dictionaries were left out of the build
+const slimBuild = false
@@ -0,0 +1,20 @@
+//go:build paiboonizer_slim
+
+package paiboonizer
+
+import "embed"
+
+// Slim builds (-tags paiboonizer_slim) embed only the syllable dictionary,
+// generated from the vocab files by go generate (see gen_syllables.go), for
+// small WASM and mobile binaries. The official and Opus word dictionaries
+// are left out: words go through the rules, TransliterateText can't split
+// Thai runs into words, and the functions working on dictionary entries
+// (search, lint, dictionary tests) find none.
+//
+//go:embed syllables.tsv
+var syllableFS embed.FS
+
+var vocabFS, opusDictFS embed.FS
+
+// slimBuild reports whether the word dictionaries were left out of the build
+const slimBuild = true
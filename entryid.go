@@ -0,0 +1,46 @@
+package paiboonizer
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// entryIDOf derives the ID of a dictionary entry from its Thai spelling
+// alone: "th-" and the 64-bit FNV-1a hash of the NFC spelling in hex
+func entryIDOf(thai string) string {
+	h := fnv.New64a()
+	h.Write([]byte(norm.NFC.String(thai)))
+	return fmt.Sprintf("th-%016x", h.Sum64())
+}
+
+// EntryID returns the stable ID of the dictionary entry for word (official,
+// Opus or royal tier), so that databases can reference a romanization by
+// entry rather than by its text. The ID only depends on the Thai spelling:
+// it survives corrections of the romanization and moves between the
+// dictionaries across releases, and a spelling correction gives a new
+// entry. Returns ("", false) for words in no dictionary.
+func EntryID(word string) (string, bool) {
+	if _, ok := LookupDictionary(word); !ok {
+		if _, ok := LookupRoyal(word); !ok {
+			return "", false
+		}
+	}
+	return entryIDOf(word), true
+}
+
+// entryIDFor returns the ID of the entry word is romanized from under
+// opts, "" when it is left to the rules, spelled out, fixed by an
+// override or found in the special cases or syllable dictionary
+func entryIDFor(word string, opts Options) string {
+	_, layer, ok := lookupWord(word, opts)
+	if !ok {
+		return ""
+	}
+	switch layer {
+	case LayerRoyal, LayerOfficial, LayerOpus:
+		return entryIDOf(word)
+	}
+	return ""
+}
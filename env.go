@@ -0,0 +1,139 @@
+package paiboonizer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/tassa-yoniso-manasi-karoto/dockerutil"
+	"github.com/tassa-yoniso-manasi-karoto/go-pythainlp"
+)
+
+// pythainlpImage is the image go-pythainlp runs its service from
+const pythainlpImage = "ghcr.io/tassa-yoniso-manasi-karoto/langkit-pythainlp:latest"
+
+// envCheckTimeout bounds each network probe made by CheckEnvironment
+const envCheckTimeout = 5 * time.Second
+
+// EnvironmentReport is the result of CheckEnvironment
+type EnvironmentReport struct {
+	DockerAvailable  bool     // Docker daemon answered a ping
+	DockerBackend    string   // "Docker Engine" or "Docker Desktop"
+	ImagePresent     bool     // pythainlp image is in the local cache
+	Image            string   // Image that was looked up
+	PythainlpVersion string   // Version of a running pythainlp service, if any
+	Containers       []string // Existing pythainlp containers, with state
+	Problems         []string // Actionable description of each problem found
+}
+
+// OK reports whether no problem was found
+func (r EnvironmentReport) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// String formats the report for display
+func (r EnvironmentReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Docker available:  %v (%s)\n", r.DockerAvailable, r.DockerBackend)
+	fmt.Fprintf(&b, "Image present:     %v (%s)\n", r.ImagePresent, r.Image)
+	if r.PythainlpVersion != "" {
+		fmt.Fprintf(&b, "PyThaiNLP version: %s\n", r.PythainlpVersion)
+	}
+	for _, c := range r.Containers {
+		fmt.Fprintf(&b, "Container:         %s\n", c)
+	}
+	if r.OK() {
+		b.WriteString("No problem found\n")
+	}
+	for _, p := range r.Problems {
+		fmt.Fprintf(&b, "Problem: %s\n", p)
+	}
+	return b.String()
+}
+
+// CheckEnvironment diagnoses the pythainlp setup without starting anything:
+// Docker availability, presence of the service image, the version of an
+// already running service and stale containers whose port mapping no longer
+// answers or collides with another process. Call it before NewManager to get
+// actionable messages instead of a wrapped startup error.
+func CheckEnvironment(ctx context.Context) EnvironmentReport {
+	r := EnvironmentReport{
+		DockerBackend: dockerutil.DockerBackendName(),
+		Image:         pythainlpImage,
+	}
+
+	if err := dockerutil.EngineIsReachable(); err != nil {
+		r.Problems = append(r.Problems, fmt.Sprintf("%v: start %s, or use the rule-based API (TransliterateWord, TransliterateText) which doesn't need Docker", err, r.DockerBackend))
+		return r
+	}
+	r.DockerAvailable = true
+
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		r.Problems = append(r.Problems, fmt.Sprintf("failed to create Docker client: %v", err))
+		return r
+	}
+	defer cli.Close()
+
+	if _, err := cli.ImageInspect(ctx, pythainlpImage); err == nil {
+		r.ImagePresent = true
+	} else {
+		// Not fatal: the image is pulled on first Init, but that takes a while
+		r.Problems = append(r.Problems, fmt.Sprintf("image %s is not cached locally: the first NewManager call will download it, run `docker pull %s` beforehand to avoid the wait", pythainlpImage, pythainlpImage))
+	}
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.service=pythainlp")),
+	})
+	if err != nil {
+		r.Problems = append(r.Problems, fmt.Sprintf("failed to list containers: %v", err))
+		return r
+	}
+	for _, c := range containers {
+		r.checkContainer(ctx, c)
+	}
+	return r
+}
+
+// checkContainer records c in the report and checks its published ports
+func (r *EnvironmentReport) checkContainer(ctx context.Context, c container.Summary) {
+	name := strings.TrimPrefix(strings.Join(c.Names, ","), "/")
+	r.Containers = append(r.Containers, fmt.Sprintf("%s (%s)", name, c.State))
+	for _, p := range c.Ports {
+		if p.PublicPort == 0 {
+			continue
+		}
+		if c.State != "running" {
+			if !portFree(p.PublicPort) {
+				r.Problems = append(r.Problems, fmt.Sprintf("port %d of stopped container %s is used by another process: recreate the container with InitPythainlpWithRecreate(true) or remove it with `docker rm %s`", p.PublicPort, name, name))
+			}
+			continue
+		}
+		probeCtx, cancel := context.WithTimeout(ctx, envCheckTimeout)
+		health, err := pythainlp.NewClient(fmt.Sprintf("http://localhost:%d", p.PublicPort), envCheckTimeout).Health(probeCtx)
+		cancel()
+		if err != nil {
+			r.Problems = append(r.Problems, fmt.Sprintf("container %s is running but its service doesn't answer on port %d (stale port mapping): use InitPythainlpWithRecreate(true)", name, p.PublicPort))
+			continue
+		}
+		if r.PythainlpVersion == "" {
+			r.PythainlpVersion = health.Version
+		}
+	}
+}
+
+// portFree reports whether a local TCP port can be bound
+func portFree(port uint16) bool {
+	l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return false
+	}
+	l.Close()
+	return true
+}
@@ -0,0 +1,116 @@
+package paiboonizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNoThaiContent is returned for input without Thai characters,
+	// which the word functions romanize to an empty string
+	ErrNoThaiContent = errors.New("no Thai content")
+	// ErrAlreadyRomanized is returned instead of ErrNoThaiContent for input
+	// that LooksRomanized, which a pipeline may have romanized already
+	ErrAlreadyRomanized = errors.New("already romanized")
+	// ErrUnparsableSyllable is wrapped by a SyllableError
+	ErrUnparsableSyllable = errors.New("unparsable syllable")
+	// ErrNeedsPythainlp is returned by NewOfflineTransliterator for
+	// options that need the pythainlp backend
+	ErrNeedsPythainlp = errors.New("needs the pythainlp backend")
+)
+
+// SyllableError reports the syllables of a word the rules romanized to
+// nothing. It matches ErrUnparsableSyllable with errors.Is.
+type SyllableError struct {
+	Word    string
+	Dropped []DroppedSpan
+}
+
+// Error lists the dropped syllables with their offset in the word
+func (e *SyllableError) Error() string {
+	spans := make([]string, len(e.Dropped))
+	for i, d := range e.Dropped {
+		spans[i] = fmt.Sprintf("%q at %d", d.Text, d.Offset)
+	}
+	return fmt.Sprintf("%v in %q: %s", ErrUnparsableSyllable, e.Word, strings.Join(spans, ", "))
+}
+
+// Unwrap returns ErrUnparsableSyllable
+func (e *SyllableError) Unwrap() error {
+	return ErrUnparsableSyllable
+}
+
+// wordError returns the error for a word romanized with dropped syllables,
+// nil if there are none
+func wordError(word string, dropped []DroppedSpan) error {
+	if len(dropped) == 0 {
+		return nil
+	}
+	return &SyllableError{Word: word, Dropped: dropped}
+}
+
+// noThaiError returns ErrAlreadyRomanized or ErrNoThaiContent for input
+// without Thai, nil for Thai input
+func noThaiError(word string) error {
+	switch {
+	case containsThai(word):
+		return nil
+	case LooksRomanized(word):
+		return ErrAlreadyRomanized
+	}
+	return ErrNoThaiContent
+}
+
+// TransliterateE is TransliterateWordWithOptions returning an error instead
+// of romanizing silently: ErrNoThaiContent when word has no Thai
+// (ErrAlreadyRomanized when it looks romanized already), and a
+// *SyllableError when the rules couldn't romanize part of it. The
+// romanization is returned along with a SyllableError, the dropped
+// syllables replaced as opts.OnFailure says, so callers can keep it or
+// fall back to something else.
+func TransliterateE(word string, opts Options) (string, error) {
+	return TransliterateEContext(context.Background(), word, opts)
+}
+
+// TransliterateEContext is TransliterateE with a context bounding the
+// pythainlp call of EngineAuto. Returns the context's error if it ends
+// before the word is romanized.
+func TransliterateEContext(ctx context.Context, word string, opts Options) (string, error) {
+	if opts.NormalizeInput {
+		word = normalizeInput(word)
+	}
+	if err := noThaiError(word); err != nil {
+		return "", err
+	}
+	trans, dropped, _ := transliterateWordDetailed(ctx, word, opts)
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return trans, wordError(word, dropped)
+}
+
+// TransliterateWordE is TransliterateWord returning ErrNoThaiContent or a
+// *SyllableError as TransliterateE does
+func TransliterateWordE(word string) (string, error) {
+	if err := noThaiError(word); err != nil {
+		return "", err
+	}
+	ensureDictionaryLoaded()
+	if trans, ok := dictionary.roman(word); ok {
+		return trans, nil
+	}
+	trans, dropped := romanizeSyllables(ExtractSyllables(word), JoinDictionary, FailureDrop, lookupOrRuleSyllable)
+	return trans, wordError(word, dropped)
+}
+
+// ComprehensiveTransliterateE is ComprehensiveTransliterate returning
+// ErrNoThaiContent or a *SyllableError as TransliterateE does
+func ComprehensiveTransliterateE(word string) (string, error) {
+	if err := noThaiError(word); err != nil {
+		return "", err
+	}
+	trans, dropped := comprehensiveTransliterate(word, FailureDrop, JoinDictionary)
+	return trans, wordError(word, dropped)
+}
@@ -0,0 +1,130 @@
+// Command repl looks up Thai words and romanizations interactively.
+//
+//	go run ./examples/repl
+//	> หน้าต่าง
+//	> :reverse kâa
+//	> :search waan
+//
+// A Thai word shows its dictionary entry with provenance and part of
+// speech, the rules' romanization, homophones and rhymes. Lines starting
+// with ":" are commands; ":help" lists them.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tassa-yoniso-manasi-karoto/paiboonizer"
+)
+
+// listLimit caps the homophones, rhymes and search results shown
+const listLimit = 10
+
+const help = `<thai word>        dictionary entry, rules, homophones and rhymes
+<thai text>        romanization of running text
+:reverse <roman>   Thai spellings of a romanization (kâa)
+:search <roman>    entries whose romanization contains <roman>, tones optional
+:prefix <thai>     entries starting with <thai>
+:tones <syllable>  the syllable with each tone mark
+:readings <syl>    every reading of a syllable, by source
+:help              this help
+:quit              exit`
+
+func main() {
+	scanner := bufio.NewScanner(os.Stdin)
+	fmt.Print("> ")
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == ":quit" || line == ":q" {
+			return
+		}
+		if line != "" {
+			run(line)
+		}
+		fmt.Print("> ")
+	}
+	fmt.Println()
+}
+
+// run answers one line of input
+func run(line string) {
+	cmd, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	switch cmd {
+	case ":help", ":h":
+		fmt.Println(help)
+	case ":reverse":
+		printList("spellings", paiboonizer.ReverseLookup(arg))
+	case ":search":
+		printEntries("matches", paiboonizer.SearchDictionary(paiboonizer.DictQuery{RomanContains: arg, Limit: listLimit}))
+	case ":prefix":
+		printEntries("matches", paiboonizer.SearchDictionary(paiboonizer.DictQuery{Prefix: arg, Limit: listLimit}))
+	case ":tones":
+		for _, v := range paiboonizer.ToneVariants(arg) {
+			fmt.Printf("  %-8s %s\t%s\n", v.Tone, v.Thai, v.Paiboon)
+		}
+	case ":readings":
+		for _, r := range paiboonizer.SyllableReadings(arg) {
+			fmt.Printf("  %-10s %s\n", r.Roman, strings.Join(r.Sources, ", "))
+		}
+	default:
+		if strings.HasPrefix(cmd, ":") {
+			fmt.Printf("unknown command %s, see :help\n", cmd)
+			return
+		}
+		if strings.ContainsAny(line, " \t") {
+			fmt.Println(paiboonizer.TransliterateText(line, paiboonizer.DefaultOptions()))
+			return
+		}
+		describe(line)
+	}
+}
+
+// describe prints what the library knows about a word
+func describe(word string) {
+	if roman, ok := paiboonizer.LookupDictionary(word); ok {
+		fmt.Printf("  dictionary  %s\n", roman)
+		if src, ok := paiboonizer.EntrySource(word); ok {
+			fmt.Printf("  source      %s (%s, %s)\n", src.File, src.Source, src.License)
+		}
+		if pos, ok := paiboonizer.PartOfSpeech(word); ok {
+			fmt.Printf("  pos         %s\n", pos)
+		}
+	} else {
+		fmt.Println("  dictionary  -")
+	}
+	fmt.Printf("  rules       %s\n", paiboonizer.ComprehensiveTransliterate(word))
+	if tags := paiboonizer.TagsOf(word); len(tags) > 0 {
+		fmt.Printf("  tags        %s\n", strings.Join(tags, " "))
+	}
+	printEntries("homophones", limit(paiboonizer.FindHomophones(word)))
+	printEntries("rhymes", limit(paiboonizer.FindRhymes(word)))
+}
+
+// limit keeps the first listLimit entries
+func limit(entries []paiboonizer.Entry) []paiboonizer.Entry {
+	if len(entries) > listLimit {
+		return entries[:listLimit]
+	}
+	return entries
+}
+
+// printEntries prints entries on one line under a label
+func printEntries(label string, entries []paiboonizer.Entry) {
+	items := make([]string, len(entries))
+	for i, e := range entries {
+		items[i] = e.Thai + " " + e.Roman
+	}
+	printList(label, items)
+}
+
+// printList prints items on one line under a label, "-" if there are none
+func printList(label string, items []string) {
+	if len(items) == 0 {
+		fmt.Printf("  %-11s -\n", label)
+		return
+	}
+	fmt.Printf("  %-11s %s\n", label, strings.Join(items, ", "))
+}
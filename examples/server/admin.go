@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/tassa-yoniso-manasi-karoto/paiboonizer"
+)
+
+// maxOverridesBody caps the body of POST /admin/overrides, in bytes
+const maxOverridesBody = 1 << 20
+
+// registerAdmin adds the endpoints editing the overrides of the
+// namespaces, for requests carrying token as a bearer token
+func registerAdmin(mux *http.ServeMux, token string) {
+	mux.HandleFunc("GET /admin/overrides", authorized(token, handleDumpOverrides))
+	mux.HandleFunc("POST /admin/overrides", authorized(token, handleSetOverrides))
+	mux.HandleFunc("DELETE /admin/overrides", authorized(token, handleRemoveOverride))
+	mux.HandleFunc("POST /admin/reload", authorized(token, handleReload))
+}
+
+// authorized rejects the requests to h without "Authorization: Bearer
+// token" and passes h the romanizer of their ns parameter
+func authorized(token string, h func(http.ResponseWriter, *http.Request, *paiboonizer.Transliterator)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		t, err := romanizerFor(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h(w, r, t)
+	}
+}
+
+// handleDumpOverrides writes the overrides of t as an overrides file
+func handleDumpOverrides(w http.ResponseWriter, r *http.Request, t *paiboonizer.Transliterator) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err := paiboonizer.WriteOverrides(w, t.Options().Overrides); err != nil {
+		log.Printf("Error writing overrides: %v", err)
+	}
+}
+
+// handleSetOverrides sets the entries of the body, lines of an overrides
+// file, in order. The entries before a bad line are kept, as are those
+// before the end of a body cut at maxOverridesBody.
+func handleSetOverrides(w http.ResponseWriter, r *http.Request, t *paiboonizer.Transliterator) {
+	scanner := bufio.NewScanner(http.MaxBytesReader(w, r.Body, maxOverridesBody))
+	set := 0
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, roman, _ := strings.Cut(line, "\t")
+		if err := t.SetOverride(key, roman); err != nil {
+			http.Error(w, fmt.Sprintf("line %d: %v (%d entries set before it)", n, err, set), http.StatusBadRequest)
+			return
+		}
+		set++
+	}
+	if err := scanner.Err(); err != nil {
+		status := http.StatusBadRequest
+		if tooLarge := new(http.MaxBytesError); errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, fmt.Sprintf("%v (%d entries set before it)", err, set), status)
+		return
+	}
+	log.Printf("Admin: %d override entries set", set)
+	writeJSON(w, map[string]int{"set": set, "entries": t.Options().Overrides.Len()})
+}
+
+// handleRemoveOverride removes the entry of the key parameter
+func handleRemoveOverride(w http.ResponseWriter, r *http.Request, t *paiboonizer.Transliterator) {
+	key := r.URL.Query().Get("key")
+	removed, err := t.RemoveOverride(key)
+	switch {
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	case !removed:
+		http.Error(w, "no such entry", http.StatusNotFound)
+		return
+	}
+	log.Printf("Admin: override entry %q removed", key)
+	writeJSON(w, map[string]int{"entries": t.Options().Overrides.Len()})
+}
+
+// handleReload reads the overrides file again
+func handleReload(w http.ResponseWriter, r *http.Request, t *paiboonizer.Transliterator) {
+	if err := t.Reload(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Printf("Admin: overrides reloaded")
+	writeJSON(w, map[string]int{"entries": t.Options().Overrides.Len()})
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lineCache keeps the responses of /api/romanize by CacheKey, since
+// subtitle re-renders and client retries send the same lines over and
+// over. Entries expire after ttl; past size entries the least recently
+// used one is evicted.
+type lineCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List // Most recently used first
+	entries map[string]*list.Element
+	stats   cacheStats
+}
+
+// cacheEntry is an element of lineCache.order
+type cacheEntry struct {
+	key     string
+	resp    romanizeResponse
+	expires time.Time
+}
+
+// cacheStats is the cache section of /health
+type cacheStats struct {
+	Entries   int    `json:"entries"`
+	Size      int    `json:"size"`
+	TTL       string `json:"ttl"`
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Expired   uint64 `json:"expired"`
+}
+
+// newLineCache returns a cache of size entries kept for ttl; nil, which
+// caches nothing, when size is 0
+func newLineCache(size int, ttl time.Duration) *lineCache {
+	if size <= 0 {
+		return nil
+	}
+	return &lineCache{size: size, ttl: ttl, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// get returns the response cached under key
+func (c *lineCache) get(key string) (romanizeResponse, bool) {
+	if c == nil {
+		return romanizeResponse{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return romanizeResponse{}, false
+	}
+	e := el.Value.(*cacheEntry)
+	if c.ttl > 0 && time.Now().After(e.expires) {
+		c.remove(el)
+		c.stats.Expired++
+		c.stats.Misses++
+		return romanizeResponse{}, false
+	}
+	c.order.MoveToFront(el)
+	c.stats.Hits++
+	return e.resp, true
+}
+
+// put caches resp under key, evicting the least recently used entry when
+// the cache is full
+func (c *lineCache) put(key string, resp romanizeResponse) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires := time.Now().Add(c.ttl)
+	if el, ok := c.entries[key]; ok {
+		el.Value = &cacheEntry{key, resp, expires}
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key, resp, expires})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// remove drops el from the cache; the caller holds mu
+func (c *lineCache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry).key)
+}
+
+// snapshot returns the current statistics
+func (c *lineCache) snapshot() cacheStats {
+	if c == nil {
+		return cacheStats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries, stats.Size, stats.TTL = c.order.Len(), c.size, c.ttl.String()
+	return stats
+}
@@ -0,0 +1,411 @@
+// Command server is a demo web UI and JSON API around paiboonizer.
+//
+//	go run ./examples/server -addr :8080 -cache-size 10000 -cache-ttl 10m
+//	go run ./examples/server -overrides names.tsv -namespace flashcards=cards.tsv
+//	go run ./examples/server -jsonl < lines.txt | jq -r .roman
+//
+// The page at / romanizes text as it is typed. Every request picks its
+// own output conventions, so that one server can serve several products:
+// scheme is a registered paiboonizer Scheme, colloquial turns on the
+// connected speech readings, royal the royal and ecclesiastical
+// vocabulary, and ns selects a -namespace, whose overrides apply instead
+// of those of -overrides. The API:
+//
+//	GET /api/romanize?text=...&join=dictionary|hyphen|none&sep=hyphen|tilde|space|none&scope=word&particles=1
+//	                 &scheme=ipa&colloquial=1&royal=1&ns=flashcards
+//	    {"roman": ..., "tokens": [...], "warnings": [...]}
+//	GET /api/lookup?word=...
+//	    {"word": ..., "dictionary": ..., "source": ..., "rules": ..., "homophones": [...]}
+//	POST /api/stream?join=...&sep=...&scope=...&particles=1&scheme=...&colloquial=1&royal=1&ns=..., lines of text as the body
+//	    {"line": 1, "text": ..., "roman": ..., "warnings": [...]} per line, each
+//	    written as soon as its line is read (see TransliterateJSONL); the
+//	    stream stops at a line over 64 KiB or past 64 MiB of body
+//	GET /health
+//	    {"status": "ok", "cache": {"entries": ..., "hits": ..., "misses": ..., ...}}
+//
+// With -admin-token (or PAIBOONIZER_ADMIN_TOKEN) an editor can correct the
+// romanization live, with "Authorization: Bearer <token>":
+//
+//	GET /admin/overrides?ns=...             the overrides in use, as an overrides file
+//	POST /admin/overrides?ns=...            sets the entries of the body, overrides file lines
+//	DELETE /admin/overrides?key=...&ns=...  removes an entry
+//	POST /admin/reload?ns=...               reads the overrides file again
+//
+// Edits are saved to the overrides file of the namespace when it has one.
+//
+// Responses of /api/romanize are cached by paiboonizer.CacheKeyFingerprint
+// of the namespace's Fingerprint, so lines sent again by subtitle
+// re-renders and retries aren't romanized twice; -cache-size 0 disables
+// the cache.
+//
+// With -jsonl no server is started: stdin is romanized to stdout as
+// /api/stream does. The UI is embedded, so the binary serves it on its
+// own.
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tassa-yoniso-manasi-karoto/paiboonizer"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+// maxTextRunes caps the text romanized by one request
+const maxTextRunes = 10000
+
+const (
+	// maxStreamBody caps the body of one /api/stream request, in bytes
+	maxStreamBody = 64 << 20
+	// maxStreamLine caps a line of /api/stream, in bytes, so that a body
+	// without newlines isn't read into memory whole
+	maxStreamLine = 64 << 10
+)
+
+// cache holds the responses of /api/romanize
+var cache *lineCache
+
+// romanizer holds the options of the API and the overrides edited through
+// the admin endpoints
+var romanizer = paiboonizer.NewTransliterator(paiboonizer.WithOptions(baseOptions()))
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	jsonl := flag.Bool("jsonl", false, "romanize stdin to stdout as JSON lines instead of serving")
+	cacheSize := flag.Int("cache-size", 10000, "lines of /api/romanize cached, 0 to disable")
+	cacheTTL := flag.Duration("cache-ttl", 10*time.Minute, "how long a cached line is kept, 0 for ever")
+	overrides := flag.String("overrides", "", "overrides file, reloaded when it changes and saved by the admin endpoints")
+	namespaceFiles := namespaceFlag{}
+	flag.Var(namespaceFiles, "namespace", "name=overrides file of a namespace requests select with ns, repeatable")
+	adminToken := flag.String("admin-token", os.Getenv("PAIBOONIZER_ADMIN_TOKEN"), "bearer token of the admin endpoints, which are off without one")
+	flag.Parse()
+	cache = newLineCache(*cacheSize, *cacheTTL)
+	if *overrides != "" {
+		if err := loadOverrides(romanizer, *overrides); err != nil {
+			log.Fatal(err)
+		}
+	}
+	for name, path := range namespaceFiles {
+		if err := addNamespace(name, path); err != nil {
+			log.Fatal(err)
+		}
+	}
+	// Build the dictionaries before the first request rather than during it
+	paiboonizer.MustLoad()
+
+	if *jsonl {
+		if err := paiboonizer.TransliterateJSONL(os.Stdin, os.Stdout, streamOptions()); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(indexHTML)
+	})
+	mux.HandleFunc("GET /api/romanize", handleRomanize)
+	mux.HandleFunc("GET /api/lookup", handleLookup)
+	mux.HandleFunc("POST /api/stream", handleStream)
+	mux.HandleFunc("GET /health", handleHealth)
+	if *adminToken != "" {
+		registerAdmin(mux, *adminToken)
+	}
+
+	log.Printf("Listening on http://%s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+// tokenJSON is a Token as the API returns it
+type tokenJSON struct {
+	Text    string   `json:"text"`
+	Roman   string   `json:"roman"`
+	Kind    string   `json:"kind"`
+	Dropped []string `json:"dropped,omitempty"`
+}
+
+// warningJSON is a Warning as the API returns it
+type warningJSON struct {
+	Kind    string `json:"kind"`
+	Text    string `json:"text,omitempty"`
+	Offset  int    `json:"offset"`
+	Message string `json:"message"`
+}
+
+// romanizeResponse is the body of /api/romanize
+type romanizeResponse struct {
+	Roman    string        `json:"roman"`
+	Tokens   []tokenJSON   `json:"tokens"`
+	Warnings []warningJSON `json:"warnings"`
+}
+
+// baseOptions are the options of the API without query parameters or
+// overrides
+func baseOptions() paiboonizer.Options {
+	opts := paiboonizer.DefaultOptions()
+	opts.OnFailure = paiboonizer.FailureBracket
+	return opts
+}
+
+// streamOptions are the options of the API without query parameters
+func streamOptions() paiboonizer.Options {
+	return romanizer.Options()
+}
+
+// queryOptions reads the ns, join, sep, scope, particles, scheme,
+// colloquial and royal parameters. It also returns the Fingerprint of the
+// namespace, taken with its options from one Snapshot so that an admin
+// edit in between can't key new output under the old overrides.
+func queryOptions(query url.Values) (paiboonizer.Options, string, error) {
+	t, err := romanizerFor(query)
+	if err != nil {
+		return paiboonizer.Options{}, "", err
+	}
+	opts, fingerprint := t.Snapshot()
+	switch query.Get("join") {
+	case "", "dictionary":
+	case "hyphen":
+		opts.Join = paiboonizer.JoinHyphen
+	case "none":
+		opts.Join = paiboonizer.JoinNone
+	default:
+		return opts, "", errors.New("join: want dictionary, hyphen or none")
+	}
+	switch query.Get("sep") {
+	case "":
+	case "hyphen":
+		opts.Separator.Mark = paiboonizer.MarkHyphen
+	case "tilde":
+		opts.Separator.Mark = paiboonizer.MarkTilde
+	case "space":
+		opts.Separator.Mark = paiboonizer.MarkSpace
+	case "none":
+		opts.Separator.Mark = paiboonizer.MarkNone
+	default:
+		return opts, "", errors.New("sep: want hyphen, tilde, space or none")
+	}
+	switch query.Get("scope") {
+	case "", "syllable":
+	case "word":
+		opts.Separator.Scope = paiboonizer.ScopeWord
+	default:
+		return opts, "", errors.New("scope: want syllable or word")
+	}
+	if query.Get("particles") != "" {
+		opts.Particles = paiboonizer.ParticleParens
+	}
+	if scheme := query.Get("scheme"); scheme != "" {
+		if _, ok := paiboonizer.LookupScheme(scheme); !ok {
+			return opts, "", errors.New("scheme: want one of " + strings.Join(paiboonizer.Schemes(), ", "))
+		}
+		opts.Scheme = scheme
+	}
+	if query.Get("colloquial") != "" {
+		opts.ConnectedSpeech = paiboonizer.DefaultConnectedSpeech()
+	}
+	if query.Get("royal") != "" {
+		opts.RoyalVocabulary = true
+	}
+	return opts, fingerprint, nil
+}
+
+// cacheParams are the parameters queryOptions reads on top of the options
+// of the namespace
+var cacheParams = []string{"join", "sep", "scope", "particles", "scheme", "colloquial", "royal"}
+
+// cacheKey returns the key of text under the options queryOptions reads
+// from query, hashing the fingerprint of their namespace with the
+// parameters rather than the options, whose overrides would be hashed on
+// every request
+func cacheKey(text, fingerprint string, query url.Values) string {
+	var b strings.Builder
+	b.WriteString(fingerprint)
+	for _, p := range cacheParams {
+		b.WriteString("\n" + p + "=" + query.Get(p))
+	}
+	return paiboonizer.CacheKeyFingerprint(text, b.String())
+}
+
+// handleRomanize romanizes the text parameter
+func handleRomanize(w http.ResponseWriter, r *http.Request) {
+	text := r.URL.Query().Get("text")
+	if utf8.RuneCountInString(text) > maxTextRunes {
+		http.Error(w, "text too long", http.StatusRequestEntityTooLarge)
+		return
+	}
+	opts, fingerprint, err := queryOptions(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	key := cacheKey(text, fingerprint, r.URL.Query())
+	if resp, ok := cache.get(key); ok {
+		writeJSON(w, resp)
+		return
+	}
+	resp := newRomanizeResponse(text, paiboonizer.TransliterateTextDetailed(text, opts), opts)
+	cache.put(key, resp)
+	writeJSON(w, resp)
+}
+
+// newRomanizeResponse builds the response for text from its romanization
+// res: the Thai words are its aligned spans, with their part of res.Roman,
+// and the rest of text is classified as TransliterateTokens does, which
+// romanizes no Thai
+func newRomanizeResponse(text string, res paiboonizer.TextResult, opts paiboonizer.Options) romanizeResponse {
+	resp := romanizeResponse{Roman: res.Roman, Tokens: []tokenJSON{}, Warnings: []warningJSON{}}
+	addOther := func(s string) {
+		for _, tok := range paiboonizer.ClassifyWords([]string{s}, opts) {
+			resp.Tokens = append(resp.Tokens, tokenJSON{Text: tok.Text, Roman: tok.Roman, Kind: tok.Kind.String()})
+		}
+	}
+	runes, roman := []rune(text), []rune(res.Roman)
+	pos := 0
+	for _, span := range res.Alignment {
+		if span.Start > pos {
+			addOther(string(runes[pos:span.Start]))
+		}
+		pos = span.End
+		if !paiboonizer.ContainsThai(span.Text) {
+			addOther(span.Text)
+			continue
+		}
+		tj := tokenJSON{Text: span.Text, Roman: string(roman[span.RomanStart:span.RomanEnd]), Kind: paiboonizer.WordKind(span.Text).String()}
+		for _, d := range res.Dropped {
+			if d.Offset >= span.Start && d.Offset < span.End {
+				tj.Dropped = append(tj.Dropped, d.Text)
+			}
+		}
+		resp.Tokens = append(resp.Tokens, tj)
+	}
+	if pos < len(runes) {
+		addOther(string(runes[pos:]))
+	}
+	for _, wn := range res.Warnings {
+		resp.Warnings = append(resp.Warnings, warningJSON{wn.Kind.String(), wn.Text, wn.Offset, wn.Message})
+	}
+	return resp
+}
+
+// healthResponse is the body of /health
+type healthResponse struct {
+	Status string     `json:"status"`
+	Cache  cacheStats `json:"cache"`
+}
+
+// handleHealth reports that the server is up, with the cache statistics
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, healthResponse{"ok", cache.snapshot()})
+}
+
+// flushWriter flushes the response after every write, so that each JSON
+// line reaches the client as soon as it is romanized
+type flushWriter struct {
+	w  io.Writer
+	rc *http.ResponseController
+}
+
+func (f flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if err == nil {
+		err = f.rc.Flush()
+	}
+	return n, err
+}
+
+// handleStream romanizes the request body line by line as JSON lines
+func handleStream(w http.ResponseWriter, r *http.Request) {
+	opts, _, err := queryOptions(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rc := http.NewResponseController(w)
+	// HTTP/1 clients stream the body while reading the results; HTTP/2
+	// always allows it
+	rc.EnableFullDuplex()
+	w.Header().Set("Content-Type", "application/jsonl; charset=utf-8")
+	body := &lineLimitReader{r: http.MaxBytesReader(w, r.Body, maxStreamBody), max: maxStreamLine}
+	if err := paiboonizer.TransliterateJSONLContext(r.Context(), body, flushWriter{w, rc}, opts); err != nil {
+		log.Printf("Error streaming: %v", err)
+	}
+}
+
+// errLineTooLong is returned by lineLimitReader past its limit
+var errLineTooLong = errors.New("line too long")
+
+// lineLimitReader fails with errLineTooLong once a line of r runs past max
+// bytes, bounding what the line reader of TransliterateJSONL buffers
+type lineLimitReader struct {
+	r    io.Reader
+	max  int
+	line int // Bytes read since the last newline
+}
+
+func (l *lineLimitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	for i, b := range p[:n] {
+		if b == '\n' {
+			l.line = 0
+		} else if l.line++; l.line > l.max {
+			return i, errLineTooLong
+		}
+	}
+	return n, err
+}
+
+// lookupResponse is the body of /api/lookup
+type lookupResponse struct {
+	Word       string   `json:"word"`
+	Dictionary string   `json:"dictionary,omitempty"`
+	Source     string   `json:"source,omitempty"`
+	License    string   `json:"license,omitempty"`
+	POS        string   `json:"pos,omitempty"`
+	Rules      string   `json:"rules"`
+	Homophones []string `json:"homophones"`
+}
+
+// handleLookup describes the word parameter
+func handleLookup(w http.ResponseWriter, r *http.Request) {
+	word := strings.TrimSpace(r.URL.Query().Get("word"))
+	if word == "" || utf8.RuneCountInString(word) > 100 {
+		http.Error(w, "word: want a single word", http.StatusBadRequest)
+		return
+	}
+	resp := lookupResponse{Word: word, Rules: paiboonizer.ComprehensiveTransliterate(word), Homophones: []string{}}
+	if roman, ok := paiboonizer.LookupDictionary(word); ok {
+		resp.Dictionary = roman
+	}
+	if src, ok := paiboonizer.EntrySource(word); ok {
+		resp.Source, resp.License = src.File, src.License
+	}
+	if pos, ok := paiboonizer.PartOfSpeech(word); ok {
+		resp.POS = pos
+	}
+	for _, e := range paiboonizer.FindHomophones(word) {
+		resp.Homophones = append(resp.Homophones, e.Thai)
+	}
+	writeJSON(w, resp)
+}
+
+// writeJSON writes v as the JSON response
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
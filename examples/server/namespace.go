@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tassa-yoniso-manasi-karoto/paiboonizer"
+)
+
+// namespaces are the romanizers requests select with the ns parameter,
+// each with its own overrides file, so that products sharing the server
+// (subtitles, flashcards) keep their own corrections. "" is romanizer.
+var namespaces = map[string]*paiboonizer.Transliterator{"": romanizer}
+
+// namespaceFlag collects the -namespace flags, name=overrides file
+type namespaceFlag map[string]string
+
+func (f namespaceFlag) String() string {
+	var pairs []string
+	for name, path := range f {
+		pairs = append(pairs, name+"="+path)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f namespaceFlag) Set(value string) error {
+	name, path, ok := strings.Cut(value, "=")
+	if !ok || name == "" || path == "" {
+		return errors.New("want name=overrides file")
+	}
+	f[name] = path
+	return nil
+}
+
+// loadOverrides loads the overrides file at path into t and reloads it
+// when it changes
+func loadOverrides(t *paiboonizer.Transliterator, path string) error {
+	if err := t.LoadOverridesFile(path); err != nil {
+		return err
+	}
+	go t.Watch(context.Background(), 2*time.Second, func(err error) {
+		if err != nil {
+			log.Printf("Error reloading %s: %v", path, err)
+		}
+	})
+	return nil
+}
+
+// addNamespace adds the namespace name with the overrides file at path
+func addNamespace(name, path string) error {
+	t := paiboonizer.NewTransliterator(paiboonizer.WithOptions(baseOptions()))
+	if err := loadOverrides(t, path); err != nil {
+		return fmt.Errorf("namespace %s: %w", name, err)
+	}
+	namespaces[name] = t
+	return nil
+}
+
+// romanizerFor returns the romanizer of the ns parameter
+func romanizerFor(query url.Values) (*paiboonizer.Transliterator, error) {
+	t, ok := namespaces[query.Get("ns")]
+	if !ok {
+		return nil, fmt.Errorf("ns: unknown namespace %q", query.Get("ns"))
+	}
+	return t, nil
+}
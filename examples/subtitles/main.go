@@ -0,0 +1,154 @@
+// Command subtitles romanizes the Thai lines of an SRT file, writing each
+// subtitle with its Paiboon romanization below the Thai.
+//
+//	go run ./examples/subtitles movie.th.srt > movie.paiboon.srt
+//	go run ./examples/subtitles -only < movie.th.srt
+//	go run ./examples/subtitles -json movie.th.srt > captions.json
+//	go run ./examples/subtitles -overrides names.tsv movie.th.srt
+//
+// Index and timing lines are copied as is. Markup (<i>, {\an8}) is kept by
+// TransliterateText; words the rules can't romanize are reported on stderr.
+// With -json the events are written with per-word timing estimates for
+// caption renderers (see RomanizeSubtitleEvents). -overrides fixes the
+// reading of character names and catchphrases (see ReadOverrides).
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tassa-yoniso-manasi-karoto/paiboonizer"
+)
+
+func main() {
+	only := flag.Bool("only", false, "write the romanization in place of the Thai")
+	warn := flag.Bool("warn", true, "report unknown words and dropped spans on stderr")
+	asJSON := flag.Bool("json", false, "write the events with per-word timing as JSON")
+	overrides := flag.String("overrides", "", "TSV file of fixed word and line readings")
+	flag.Parse()
+
+	opts := paiboonizer.DefaultOptions()
+	opts.OnFailure = paiboonizer.FailureBracket
+	if *overrides != "" {
+		var err error
+		if opts.Overrides, err = paiboonizer.LoadOverrides(*overrides); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	in := io.Reader(os.Stdin)
+	if flag.NArg() > 0 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in = f
+	}
+	run := func() error { return romanizeSRT(in, os.Stdout, opts, *only, *warn) }
+	if *asJSON {
+		run = func() error { return writeEventsJSON(in, os.Stdout, opts) }
+	}
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// romanizeSRT copies an SRT file, following each text line holding Thai
+// with its romanization, or replacing it with only
+func romanizeSRT(r io.Reader, w io.Writer, opts paiboonizer.Options, only, warn bool) error {
+	bw := bufio.NewWriter(w)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimPrefix(scanner.Text(), "\uFEFF")
+		if !hasThai(line) {
+			fmt.Fprintln(bw, line)
+			continue
+		}
+		res := paiboonizer.TransliterateTextDetailed(line, opts)
+		if !only {
+			fmt.Fprintln(bw, line)
+		}
+		fmt.Fprintln(bw, res.Roman)
+		if warn {
+			for _, wn := range res.Warnings {
+				fmt.Fprintf(os.Stderr, "line %d: %s\n", n, wn)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// hasThai reports whether s holds Thai script; index, timing and blank
+// lines don't
+func hasThai(s string) bool {
+	for _, r := range s {
+		if r >= 0x0E00 && r <= 0x0E7F {
+			return true
+		}
+	}
+	return false
+}
+
+// timingRegex matches an SRT timing line
+var timingRegex = regexp.MustCompile(`^(\d+):(\d\d):(\d\d)[,.](\d{3}) --> (\d+):(\d\d):(\d\d)[,.](\d{3})`)
+
+// writeEventsJSON parses an SRT file and writes its romanized events as JSON
+func writeEventsJSON(r io.Reader, w io.Writer, opts paiboonizer.Options) error {
+	events, err := parseSRT(r)
+	if err != nil {
+		return err
+	}
+	return paiboonizer.WriteSubtitleEventsJSON(w, paiboonizer.RomanizeSubtitleEvents(events, opts))
+}
+
+// parseSRT reads the events of an SRT file. The lines of a multi-line
+// subtitle are joined with \N, which the romanization keeps.
+func parseSRT(r io.Reader) ([]paiboonizer.SubtitleEvent, error) {
+	var events []paiboonizer.SubtitleEvent
+	var current *paiboonizer.SubtitleEvent
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\uFEFF"))
+		if m := timingRegex.FindStringSubmatch(line); m != nil {
+			events = append(events, paiboonizer.SubtitleEvent{Start: srtTime(m[1:5]), End: srtTime(m[5:9])})
+			current = &events[len(events)-1]
+			continue
+		}
+		switch {
+		case line == "":
+			current = nil
+		case current == nil:
+			// Index line
+		case current.Text == "":
+			current.Text = line
+		default:
+			current.Text += `\N` + line
+		}
+	}
+	return events, scanner.Err()
+}
+
+// srtTime converts hours, minutes, seconds and milliseconds to a duration
+func srtTime(parts []string) time.Duration {
+	units := []time.Duration{time.Hour, time.Minute, time.Second, time.Millisecond}
+	var d time.Duration
+	for i, p := range parts {
+		n, _ := strconv.Atoi(p)
+		d += time.Duration(n) * units[i]
+	}
+	return d
+}
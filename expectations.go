@@ -0,0 +1,128 @@
+package paiboonizer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Expectations lists the dictionary entries and corpus lines known to
+// fail, each with a reference to the issue tracking it, so that the
+// quality gate can tell new regressions from long-standing gaps
+type Expectations struct {
+	Dictionary map[string]string // Thai spelling to issue
+	Corpus     map[string]string // "source:line" (see CorpusKey) to issue
+}
+
+// expectationsHeader starts every expectations file
+const expectationsHeader = "# Paiboonizer known failures\n# Format: dictionary<TAB>Thai<TAB>Issue or corpus<TAB>Source:Line<TAB>Issue\n"
+
+// CorpusKey identifies a corpus line in Expectations
+func CorpusKey(source string, line int) string {
+	return source + ":" + strconv.Itoa(line)
+}
+
+// ReadExpectations reads an expectations file: one known failure per
+// line, as "dictionary", the Thai spelling and the issue, or as "corpus",
+// the corpus key and the issue, tab separated. The issue column may be
+// left out. Comment and empty lines are skipped.
+func ReadExpectations(r io.Reader) (Expectations, error) {
+	e := Expectations{Dictionary: make(map[string]string), Corpus: make(map[string]string)}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		text := scanner.Text()
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) < 2 || len(fields) > 3 {
+			return Expectations{}, fmt.Errorf("line %d: expected 2 or 3 fields, got %d", n, len(fields))
+		}
+		issue := ""
+		if len(fields) == 3 {
+			issue = strings.TrimSpace(fields[2])
+		}
+		switch fields[0] {
+		case "dictionary":
+			e.Dictionary[fields[1]] = issue
+		case "corpus":
+			e.Corpus[fields[1]] = issue
+		default:
+			return Expectations{}, fmt.Errorf("line %d: unknown kind %q", n, fields[0])
+		}
+	}
+	return e, scanner.Err()
+}
+
+// WriteExpectations writes e as an expectations file: a commented header,
+// then the dictionary entries and the corpus lines, each sorted
+func WriteExpectations(w io.Writer, e Expectations) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(expectationsHeader)
+	for _, kind := range []struct {
+		name  string
+		items map[string]string
+	}{{"dictionary", e.Dictionary}, {"corpus", e.Corpus}} {
+		keys := make([]string, 0, len(kind.items))
+		for k := range kind.items {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(bw, "%s\t%s\t%s\n", kind.name, k, kind.items[k])
+		}
+	}
+	return bw.Flush()
+}
+
+// ExpectationCheck compares the failures of a quality gate run with
+// Expectations
+type ExpectationCheck struct {
+	// New are the failures not listed, as "dictionary ธรรม" or "corpus
+	// test1:42": the regressions
+	New []string
+	// Known is the number of failures listed
+	Known int
+	// Fixed are the listed entries and lines that were tested and passed,
+	// whose expectation can be removed
+	Fixed []string
+	// Current is the expectations rewritten for this run: every failure,
+	// with its issue when it was listed, and the listed entries and lines
+	// that weren't tested
+	Current Expectations
+}
+
+// check records the outcome of one tested entry or line of kind, listed
+// or not in listed
+func (c *ExpectationCheck) check(kind, key string, failed bool, listed, current map[string]string) {
+	issue, known := listed[key]
+	switch {
+	case failed && known:
+		c.Known++
+	case failed:
+		c.New = append(c.New, kind+" "+key)
+	case known:
+		c.Fixed = append(c.Fixed, kind+" "+key)
+	}
+	if failed {
+		current[key] = issue
+	} else {
+		delete(current, key)
+	}
+}
+
+// newExpectationCheck starts a check against e, Current holding every
+// listed entry and line until tested
+func newExpectationCheck(e Expectations) *ExpectationCheck {
+	c := &ExpectationCheck{Current: Expectations{Dictionary: make(map[string]string), Corpus: make(map[string]string)}}
+	for k, v := range e.Dictionary {
+		c.Current.Dictionary[k] = v
+	}
+	for k, v := range e.Corpus {
+		c.Current.Corpus[k] = v
+	}
+	return c
+}
@@ -0,0 +1,134 @@
+package paiboonizer
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Explanation is the trail of the romanization of a word under some
+// Options: the lookup layer that knew it, or the engine that split it and
+// where each of its syllables came from
+type Explanation struct {
+	Word  string
+	Roman string // As TransliterateWordWithOptions writes it
+	// Layer is the lookup layer the word was found in (see LookupLayer),
+	// "spelling" for tokens spelled out or already romanized, empty when
+	// it was left to the engine
+	Layer     string
+	Engine    SyllableEngine // The engine of the rules, when Layer is empty
+	Syllables []SyllableResult
+}
+
+// String writes the trail on one line: the layer or engine, then each
+// syllable with its source, as "rules/comprehensive: kwaam[syllable
+// dictionary] sùk[rules] → kwaam-sùk"
+func (e Explanation) String() string {
+	var b strings.Builder
+	if e.Layer != "" {
+		b.WriteString(e.Layer)
+	} else {
+		b.WriteString("rules/" + e.Engine.String())
+	}
+	b.WriteString(":")
+	for _, s := range e.Syllables {
+		roman := s.Roman
+		if roman == "" {
+			roman = s.Thai
+		}
+		b.WriteString(" " + roman + "[" + s.Source.String() + "]")
+	}
+	b.WriteString(" → " + e.Roman)
+	return b.String()
+}
+
+// Explain romanizes word with opts and returns how it was romanized.
+// EngineAuto asks pythainlp for the syllables a second time to report
+// them.
+func Explain(word string, opts Options) Explanation {
+	ensureDictionaryLoaded()
+	ctx := context.Background()
+	if opts.NormalizeInput {
+		word = normalizeInput(word)
+	}
+	roman, _, _ := transliterateWordDetailed(ctx, word, opts)
+	e := Explanation{Word: word, Roman: roman, Engine: opts.Engine, Syllables: []SyllableResult{}}
+	if trans, layer, ok := lookupWord(word, opts); ok {
+		e.Layer = layer.String()
+		e.Syllables = splitPiece(wordPiece{word, trans, layer.source()})
+		return e
+	}
+	for _, p := range opts.Engine.pieces(ctx, word, opts.OnFailure, opts.Separator.joinPolicy(opts.Join)) {
+		e.Syllables = append(e.Syllables, splitPiece(p)...)
+	}
+	return e
+}
+
+// source returns the RomanSource of the entries of the layer
+func (l LookupLayer) source() RomanSource {
+	switch l {
+	case LayerSpecialCases:
+		return SourceSpecialCase
+	case LayerSyllables:
+		return SourceSyllableDictionary
+	case layerSpelling:
+		return SourceRules
+	}
+	return SourceDictionary
+}
+
+// pieces splits word as the engine does and returns its romanized pieces
+func (e SyllableEngine) pieces(ctx context.Context, word string, onFailure FailurePolicy, join JoinPolicy) []wordPiece {
+	switch e {
+	case EngineComprehensive:
+		pieces, _ := comprehensivePieces(word, onFailure, join)
+		return pieces
+	case EngineSegmenter:
+		pieces := []wordPiece{}
+		for _, syl := range ExtractSyllables(word) {
+			trans, _ := lookupOrRuleSyllable(syl)
+			source := SourceRules
+			if _, ok := syllableDict[syl]; ok {
+				source = SourceSyllableDictionary
+			}
+			pieces = append(pieces, wordPiece{syl, norm.NFC.String(trans), source})
+		}
+		return pieces
+	}
+	if globalManager != nil && globalManager.nlpManager != nil {
+		if syllables, err := globalManager.syllableTokenize(ctx, word); err == nil && len(syllables) > 0 {
+			pieces := []wordPiece{}
+			for _, syl := range syllables {
+				sylPieces, _ := comprehensivePieces(syl, onFailure, join)
+				pieces = append(pieces, sylPieces...)
+			}
+			return pieces
+		}
+	}
+	pieces, _ := comprehensivePieces(word, onFailure, join)
+	return pieces
+}
+
+// Divergence is a word two configurations romanize differently, with the
+// trail of each
+type Divergence struct {
+	Word string
+	A, B Explanation
+}
+
+// CompareEngines romanizes words under the configurations a and b, any
+// engine, lookup layers or rendering, and returns the words whose outputs
+// differ, in input order, with both trails, so that changes to the engines
+// can be reviewed word by word before they land
+func CompareEngines(words []string, a, b Options) []Divergence {
+	ensureDictionaryLoaded()
+	divergences := []Divergence{}
+	for _, word := range words {
+		if TransliterateWordWithOptions(word, a) == TransliterateWordWithOptions(word, b) {
+			continue
+		}
+		divergences = append(divergences, Divergence{word, Explain(word, a), Explain(word, b)})
+	}
+	return divergences
+}
@@ -0,0 +1,66 @@
+package paiboonizer
+
+// Final clusters (จักร, บุตร, จันทร์) are written with letters the syllable
+// rules, which only know single finals, can't place. Their silent letters
+// are skipped by comprehensiveTransliterate between syllables; the words
+// and syllables below can't be read that way.
+
+// finalClusterExceptions holds the words with a final cluster the rules
+// can't read. Most are read with an extra syllable: the first consonant of
+// the cluster is both a final and the initial of a cluster (จักรยาน
+// jàk-grà~yaan).
+var finalClusterExceptions = map[string]string{
+	"จักรยาน":   "jàk-grà~yaan",
+	"จักรพรรดิ": "jàk-grà~pát",
+	"มิตรภาพ":   "mít-dtrà~pâap",
+	"เกษตร":     "gà~sèet",
+	"ศัตรู":     "sàt-dtruu",
+	"อัคร":      "àk-krá",
+	"สมุทร":     "sà~mùt",
+}
+
+// silentRFinals are the syllables closed by a consonant and a silent ร
+// that silentFinalCluster doesn't find, inside words (บัตรประชาชน) or after
+// a long vowel (บาตร, สูตร). Inside a word the ร is too often the initial
+// of the next syllable (นักรบ, การรับรอง) to be silenced by rule.
+var silentRFinals = map[string]bool{
+	"จักร": true, "บัตร": true, "บาตร": true, "บุตร": true, "ปัตร": true,
+	"มิตร": true, "วัตร": true, "สมัคร": true, "สูตร": true,
+	"ฉัตร": true, "เนตร": true, "เพชร": true, "เมตร": true,
+}
+
+// silentRFinal romanizes runes[i:i+length] without its ร if it is a
+// syllable of silentRFinals whose ร doesn't carry a vowel
+func silentRFinal(runes []rune, i, length int, join JoinPolicy) (string, bool) {
+	end := i + length
+	if !silentRFinals[string(runes[i:end])] || end < len(runes) && !isConsonantRune(runes[end]) {
+		return "", false
+	}
+	trans, _ := comprehensiveTransliterate(string(runes[i:end-1]), FailureDrop, join)
+	return trans, trans != ""
+}
+
+// silentFinalCluster returns the number of silent letters of a final
+// cluster starting at runes[i], 0 if none starts there:
+//
+//   - a consonant and ร silenced by ์ after a final (จันทร์ jan, ศาสตร์
+//     sàat), where RemoveSilentConsonants would only drop the ร
+//   - a ร ending the word after a final closing a short vowel (จักร jàk,
+//     สมุทร sà~mùt)
+func silentFinalCluster(runes []rune, i int) int {
+	if i < 2 || !isConsonantRune(runes[i-1]) {
+		return 0
+	}
+	// Not the vowel อ of เ-อ (คอมพิวเตอร์)
+	if i+2 < len(runes) && isConsonantRune(runes[i]) && runes[i] != 'อ' && runes[i+1] == 'ร' && runes[i+2] == '์' {
+		return 3
+	}
+	if i == len(runes)-1 && runes[i] == 'ร' && shortVowelMarks[runes[i-2]] {
+		return 1
+	}
+	return 0
+}
+
+// shortVowelMarks are the vowel marks written above or below the initial
+// of a short syllable, which a final cluster may close
+var shortVowelMarks = map[rune]bool{'ั': true, 'ิ': true, 'ึ': true, 'ุ': true}
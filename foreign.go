@@ -0,0 +1,45 @@
+package paiboonizer
+
+import "strings"
+
+// ForeignPolicy selects what the text pipelines write for the words of
+// another script (English words, brand names) found in Thai text. Numbers,
+// punctuation and emoji around them are always kept.
+type ForeignPolicy int
+
+const (
+	ForeignPassThrough ForeignPolicy = iota // As is: chái iPhone
+	ForeignLowercase                        // In lower case: chái iphone
+	ForeignBracket                          // In brackets: chái [iPhone]
+	ForeignDrop                             // Nothing: chái
+)
+
+// foreignText returns what policy writes in place of word
+func foreignText(word string, policy ForeignPolicy) string {
+	switch policy {
+	case ForeignLowercase:
+		return strings.ToLower(word)
+	case ForeignBracket:
+		return "[" + word + "]"
+	case ForeignDrop:
+		return ""
+	}
+	return word
+}
+
+// rewriteForeign applies policy to the foreign words of a run of non-Thai
+// text
+func rewriteForeign(run string, policy ForeignPolicy) string {
+	if policy == ForeignPassThrough {
+		return run
+	}
+	var b strings.Builder
+	for _, tok := range classifyOther(run) {
+		if tok.Kind == KindForeign {
+			b.WriteString(foreignText(tok.Text, policy))
+		} else {
+			b.WriteString(tok.Text)
+		}
+	}
+	return b.String()
+}
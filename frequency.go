@@ -0,0 +1,96 @@
+package paiboonizer
+
+import (
+	"bufio"
+	_ "embed"
+	"io"
+	"maps"
+	"strings"
+	"sync"
+)
+
+// FrequencyBand tells learners how common a word is, so they can see which
+// words to learn first
+type FrequencyBand int
+
+const (
+	BandNone  FrequencyBand = iota // Not annotated: no list, or not a Thai word
+	BandTop1k                      // Among the 1000 most frequent words
+	BandTop5k                      // Among the 5000 most frequent words
+	BandRare                       // Ranked lower or not listed
+)
+
+// String returns the band name
+func (b FrequencyBand) String() string {
+	switch b {
+	case BandTop1k:
+		return "top-1k"
+	case BandTop5k:
+		return "top-5k"
+	case BandRare:
+		return "rare"
+	}
+	return ""
+}
+
+// FrequencyList maps Thai words to their frequency rank, 1 for the most
+// frequent
+type FrequencyList map[string]int
+
+// ReadFrequencyList reads a word frequency list, one word per line from
+// the most frequent down. Anything after the word on a line (a tab and a
+// count, as most corpus word lists have) is ignored, as are blank lines and
+// lines starting with #. A word listed twice keeps its first rank.
+func ReadFrequencyList(r io.Reader) (FrequencyList, error) {
+	list := FrequencyList{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		word := strings.Fields(line)[0]
+		if _, ok := list[word]; !ok {
+			list[word] = len(list) + 1
+		}
+	}
+	return list, scanner.Err()
+}
+
+// frequencyTSV is the list of DefaultFrequencyList, generated by go
+// generate (see gen_frequency.go)
+//
+//go:embed frequency.tsv
+var frequencyTSV string
+
+var (
+	defaultFrequency     FrequencyList
+	defaultFrequencyOnce sync.Once
+)
+
+// DefaultFrequencyList returns the embedded frequency list: the dictionary
+// words of the subtitle corpus seen more than once, ranked by their
+// number of occurrences. Its top 1000 are everyday words; the rest of
+// its 3000 or so are still common in speech. The list is a fresh copy
+// that callers may edit before setting it as Options.Frequency.
+func DefaultFrequencyList() FrequencyList {
+	defaultFrequencyOnce.Do(func() {
+		// Reading from a string can't fail
+		defaultFrequency, _ = ReadFrequencyList(strings.NewReader(frequencyTSV))
+	})
+	return maps.Clone(defaultFrequency)
+}
+
+// Band returns the frequency band of word, BandRare if it isn't listed
+func (f FrequencyList) Band(word string) FrequencyBand {
+	rank, ok := f[word]
+	switch {
+	case !ok:
+		return BandRare
+	case rank <= 1000:
+		return BandTop1k
+	case rank <= 5000:
+		return BandTop5k
+	}
+	return BandRare
+}
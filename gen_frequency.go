@@ -0,0 +1,76 @@
+//go:build ignore
+
+// gen_frequency writes frequency.tsv, the word frequency list returned by
+// DefaultFrequencyList: the dictionary words of the Thai subtitles in
+// cmd/testing_files, as TransliterateTokens splits them, counted and
+// ranked from the most frequent down. Words seen once are left out: they
+// tell nothing of how common they are. Run with go generate after
+// changing the subtitles or the vocab files.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tassa-yoniso-manasi-karoto/paiboonizer"
+)
+
+func main() {
+	files, err := filepath.Glob("cmd/testing_files/test*.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts := paiboonizer.DefaultOptions()
+	opts.Engine = paiboonizer.EngineComprehensive
+	counts := make(map[string]int)
+	for _, file := range files {
+		if strings.Contains(file, "_transliterated") {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, tok := range paiboonizer.TransliterateTokens(strings.TrimPrefix(string(data), "\ufeff"), opts) {
+			if tok.Kind != paiboonizer.KindWord && tok.Kind != paiboonizer.KindParticle {
+				continue
+			}
+			if _, ok := paiboonizer.LookupDictionary(tok.Text); ok {
+				counts[tok.Text]++
+			}
+		}
+	}
+	words := make([]string, 0, len(counts))
+	for word, count := range counts {
+		if count > 1 {
+			words = append(words, word)
+		}
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if counts[words[i]] != counts[words[j]] {
+			return counts[words[i]] > counts[words[j]]
+		}
+		return words[i] < words[j]
+	})
+
+	f, err := os.Create("frequency.tsv")
+	if err != nil {
+		log.Fatal(err)
+	}
+	bw := bufio.NewWriter(f)
+	bw.WriteString("# Paiboonizer word frequency list, generated by gen_frequency.go\n# Format: Word<TAB>Occurrences\n")
+	for _, word := range words {
+		fmt.Fprintf(bw, "%s\t%d\n", word, counts[word])
+	}
+	if err := bw.Flush(); err != nil {
+		log.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,25 @@
+//go:build ignore
+
+// gen_syllables writes syllables.tsv, the syllable dictionary embedded by
+// slim builds. Run with go generate after changing the vocab files.
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/tassa-yoniso-manasi-karoto/paiboonizer"
+)
+
+func main() {
+	f, err := os.Create("syllables.tsv")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := paiboonizer.WriteSyllableDictionary(f); err != nil {
+		log.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,122 @@
+package paiboonizer
+
+import (
+	"bufio"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// Glosser gives the English gloss of Thai words, for learner output that
+// pairs each word with its romanization and meaning
+type Glosser interface {
+	// Gloss returns the gloss of word, false if it has none
+	Gloss(word string) (string, bool)
+}
+
+// NamedGlosser is a Glosser with a stable identity, which
+// OptionsFingerprint hashes in place of its glosses: Name must differ
+// between glossers giving different glosses, so that caches keyed by
+// CacheKey don't serve stale ones.
+type NamedGlosser interface {
+	Glosser
+	Name() string
+}
+
+// GlossMap is a Glosser backed by a map, for glosses from elsewhere
+type GlossMap map[string]string
+
+// Gloss returns the gloss of word in the map
+func (g GlossMap) Gloss(word string) (string, bool) {
+	gloss, ok := g[word]
+	return gloss, ok
+}
+
+// vocabGlosser glosses with the English column of the vocab files
+type vocabGlosser struct{}
+
+// Gloss returns the English the vocab files give for word
+func (vocabGlosser) Gloss(word string) (string, bool) {
+	ensureDictionaryLoaded()
+	gloss, ok := vocabGlosses[word]
+	return gloss, ok
+}
+
+// Name identifies the vocab glosses, which DictionaryChecksum covers
+func (vocabGlosser) Name() string {
+	return "vocab"
+}
+
+// VocabGlosser returns a Glosser using the English column of the embedded
+// vocab files, which covers the official dictionary words. Senses are
+// separated by " | " (case (box) | box (e.g. cardboard)). Slim builds have
+// no vocab files and gloss nothing.
+func VocabGlosser() Glosser {
+	return vocabGlosser{}
+}
+
+// WriteAnkiNotes writes the Thai words and particles of tokens as Anki
+// notes, one Thai/Paiboon/gloss triple per line separated by tabs under
+// Anki's #separator and #html headers, for import as a vocabulary deck.
+// Each word is written once, in order of first appearance; the gloss is
+// left empty for tokens without one (see Options.Glosser).
+func WriteAnkiNotes(w io.Writer, tokens []Token) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("#separator:tab\n#html:false\n")
+	// Tabs and line breaks would start a new field or note
+	clean := strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+	for _, tok := range vocabularyTokens(tokens) {
+		bw.WriteString(tok.Text + "\t" + clean.Replace(tok.Roman) + "\t" + clean.Replace(tok.Gloss) + "\n")
+	}
+	return bw.Flush()
+}
+
+// WriteGlossHTML renders the Thai words and particles of tokens as a
+// standalone, printable HTML page: a table of Thai/Paiboon/gloss triples,
+// the vocabulary list of WriteAnkiNotes for reading rather than import.
+// Each word is written once, in order of first appearance, and the gloss
+// cell is left empty for tokens without one (see Options.Glosser).
+func WriteGlossHTML(w io.Writer, tokens []Token) error {
+	return glossTemplate.Execute(w, vocabularyTokens(tokens))
+}
+
+// vocabularyTokens returns the Thai words and particles of tokens, each
+// once in order of first appearance; repetition marks are left out
+func vocabularyTokens(tokens []Token) []Token {
+	var words []Token
+	seen := make(map[string]bool)
+	for _, tok := range tokens {
+		if (tok.Kind != KindWord && tok.Kind != KindParticle) || tok.Text == "ๆ" || seen[tok.Text] {
+			continue
+		}
+		seen[tok.Text] = true
+		words = append(words, tok)
+	}
+	return words
+}
+
+var glossTemplate = template.Must(template.New("gloss").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Vocabulary</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #999; padding: 0.2em 0.6em; text-align: left; }
+th { background: #eee; }
+.thai { font-size: 1.3em; }
+@media print { body { margin: 0; } tr { page-break-inside: avoid; } }
+</style>
+</head>
+<body>
+<h1>Vocabulary</h1>
+<table>
+<tr><th>Thai</th><th>Paiboon</th><th>Gloss</th></tr>
+{{- range .}}
+<tr><td class="thai" lang="th">{{.Text}}</td><td>{{.Roman}}</td><td>{{.Gloss}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,52 @@
+package paiboonizer
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// haasScheme is the "haas" scheme: the transcription of Mary Haas, used
+// with small changes by the AUA courses and many academic textbooks.
+// Tones are the diacritics of Paiboon; aspiration is written with h, the
+// glottal onset with ʔ, ʉ as ɯ and the glides of ai, ao and the like as y
+// and w.
+var haasScheme = Scheme{
+	Name:     "haas",
+	Letters:  map[rune]string{'ʉ': "ɯ"},
+	Syllable: haasSyllable,
+}
+
+// HaasRenderer renders syllables in the Haas transcription
+var HaasRenderer SyllableRenderer = SyllableFunc(haasSyllable)
+
+// haasInitials maps the Paiboon initials Haas writes differently
+var haasInitials = map[string]string{
+	"": "ʔ", "g": "k", "k": "kh", "bp": "p", "p": "ph", "dt": "t", "t": "th",
+	"j": "c", "ng": "ŋ",
+	"gr": "kr", "gl": "kl", "gw": "kw", "kr": "khr", "kl": "khl", "kw": "khw",
+	"bpr": "pr", "bpl": "pl", "pr": "phr", "pl": "phl", "dtr": "tr", "tr": "thr",
+}
+
+// haasSyllable renders a Paiboon syllable in the Haas transcription
+func haasSyllable(initial, vowel, final, tone string) string {
+	if in, ok := haasInitials[initial]; ok {
+		initial = in
+	}
+	// A trailing i, o or u after another vowel letter is the glide y or w
+	if n := len(vowel); n > 1 && strings.ContainsRune("iou", rune(vowel[n-1])) && vowel[n-2] != vowel[n-1] {
+		final = map[byte]string{'i': "y", 'o': "w", 'u': "w"}[vowel[n-1]]
+		vowel = vowel[:n-1]
+	}
+	if final == "ng" {
+		final = "ŋ"
+	}
+	// The diphthongs are written without length: iia → ia
+	if short, ok := map[string]string{"iia": "ia", "ʉʉa": "ʉa", "uua": "ua"}[vowel]; ok {
+		vowel = short
+	}
+	vowel = strings.ReplaceAll(vowel, "ʉ", "ɯ")
+	_, size := utf8.DecodeRuneInString(vowel)
+	return norm.NFC.String(initial + vowel[:size] + paiboonMarks[tone] + vowel[size:] + final)
+}
@@ -0,0 +1,85 @@
+package paiboonizer
+
+import "strings"
+
+// Pali and Sanskrit loans follow spelling rules of their own that native
+// words don't: letters silenced with ์ together with their vowel
+// (พันธุ์ pan, ประสบการณ์ bprà~sòp-gaan) and linker vowels. The
+// comprehensive engine applies them to the words IsIndicLoan picks out.
+
+// IsIndicLoan reports whether word looks like a Pali or Sanskrit loan: it
+// has a letter native words don't use (ฆ ณ ธ ภ ศ ษ ฬ ...), a doubled ร or a
+// letter silenced together with its vowel (พันธุ์). Such words go through
+// the loan rules of ComprehensiveTransliterate and fall in
+// CategoryIndicLoan. The check is heuristic: native words like โกรธ use
+// some of the letters too.
+func IsIndicLoan(word string) bool {
+	if strings.ContainsAny(word, indicLetters) || strings.Contains(word, "รร") {
+		return true
+	}
+	runes := []rune(word)
+	for i := 2; i < len(runes); i++ {
+		if runes[i] == '์' && isVowelRune(runes[i-1]) && isConsonantRune(runes[i-2]) {
+			return true
+		}
+	}
+	return false
+}
+
+// indicSilentLetters returns the number of letters silenced by ์ starting
+// at runes[i] after a consonant, 0 if none: a consonant, its vowel if any
+// and ์ (ลัพธ์ láp, พันธุ์ pan)
+func indicSilentLetters(runes []rune, i int) int {
+	if i < 1 || !isConsonantRune(runes[i-1]) || !isConsonantRune(runes[i]) {
+		return 0
+	}
+	if i+1 < len(runes) && runes[i+1] == '์' {
+		return 2
+	}
+	if i+2 < len(runes) && (runes[i+1] == 'ิ' || runes[i+1] == 'ุ') && runes[i+2] == '์' {
+		return 3
+	}
+	return 0
+}
+
+// linkingFinals are the final letters read a second time, as the initial
+// of a linker syllable, before the next syllable of a loan. Loans closed
+// by other letters link too (สุขภาพ sùk-kà~pâap) but not often enough for
+// a rule: กฎหมาย, เพศสัมพันธ์.
+const linkingFinals = "ฆฌฏฐฑฒธภ"
+
+// linkerSyllable returns the linker read after a syllable closed by final:
+// its initial sound and a short a, low tone after a high or mid class
+// letter and high tone after a low class one
+func linkerSyllable(final rune) string {
+	if lowClass[string(final)] {
+		return initialConsonants[string(final)] + "á"
+	}
+	return initialConsonants[string(final)] + "à"
+}
+
+// addLinkers appends the linker syllable to the pieces of a loan closed by
+// one of linkingFinals after a short or long a, i or u and followed by
+// another syllable (อัธยาศัย àt-tá~yaa-sǎi, อาราธนา aa-râat-tá~naa).
+// Syllables with a leading vowel (โกรธ) and pieces whose romanization
+// already has the linker are left alone.
+func addLinkers(pieces []wordPiece) {
+	for i := 0; i+1 < len(pieces); i++ {
+		p, next := []rune(pieces[i].thai), []rune(pieces[i+1].thai)
+		if len(p) < 2 || pieces[i].roman == "" || pieces[i].source == SourceSpecialCase {
+			continue
+		}
+		last := p[len(p)-1]
+		if !strings.ContainsRune(linkingFinals, last) || strings.ContainsAny(pieces[i].thai, "เแโใไ") {
+			continue
+		}
+		if !isConsonantRune(next[0]) && !isLeadingVowel(string(next[0])) {
+			continue
+		}
+		linker := linkerSyllable(last)
+		if isShortOpenSyllable(lastRomanSyllable(pieces[i].roman)) || strings.HasPrefix(pieces[i+1].roman, linker) {
+			continue
+		}
+		pieces[i].roman += "-" + linker
+	}
+}
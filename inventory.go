@@ -0,0 +1,180 @@
+package paiboonizer
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxInventoryExamples caps the Thai spellings listed per inventory entry
+const maxInventoryExamples = 5
+
+// InventoryEntry is one distinct syllable shape the rule engine produces
+type InventoryEntry struct {
+	Initial  string   // Paiboon initial, "" for a glottal onset (อ)
+	Vowel    string   // Paiboon vowel, including off-glides (ai, ao, iao)
+	Final    string   // Paiboon final stop or nasal, "" for open syllables
+	Tone     string   // mid, low, falling, high or rising
+	Count    int      // Number of corpus syllables with this shape
+	Examples []string // Thai spellings, shortest first
+}
+
+// paiboonInitials lists Paiboon onsets, longest first so clusters win
+var paiboonInitials = []string{
+	"bpr", "bpl", "dtr",
+	"bp", "dt", "ng", "ch", "br", "bl", "dr", "fr", "fl", "gr", "gl", "gw",
+	"kr", "kl", "kw", "pr", "pl", "tr",
+	"b", "d", "f", "g", "h", "j", "k", "l", "m", "n", "p", "r", "s", "t", "w", "y",
+}
+
+// paiboonFinals lists Paiboon finals that are not part of the vowel
+var paiboonFinals = []string{"ng", "m", "n", "p", "t", "k"}
+
+// toneByMark maps the combining tone diacritics of Paiboon to tone names
+var toneByMark = map[rune]string{
+	'\u0300': "low",     // grave
+	'\u0302': "falling", // circumflex
+	'\u0301': "high",    // acute
+	'\u030C': "rising",  // caron
+}
+
+// SyllableInventory enumerates every distinct (initial, vowel, final, tone)
+// combination the rule engine produces over the syllables of the embedded
+// dictionary, with example Thai spellings. Dictionary lookups are bypassed
+// so the inventory reflects the rules, not the data.
+func SyllableInventory() []InventoryEntry {
+	ensureDictionaryLoaded()
+	byShape := make(map[[4]string]*InventoryEntry)
+	for syl := range syllableDict {
+		// Skip fragments left over by the automatic syllable extraction
+		runes := []rune(syl)
+		if len(runes) == 0 || !canStartWord(runes, 0) || isLeadingVowel(string(runes[len(runes)-1])) {
+			continue
+		}
+		trans := improvedTransliterate(syl)
+		if trans == "" {
+			trans = buildPaiboonFromSyllable(parseThaiSyllable(syl))
+		}
+		initial, vowel, final, tone, ok := SplitPaiboonSyllable(trans)
+		if !ok {
+			continue
+		}
+		shape := [4]string{initial, vowel, final, tone}
+		e := byShape[shape]
+		if e == nil {
+			e = &InventoryEntry{Initial: initial, Vowel: vowel, Final: final, Tone: tone}
+			byShape[shape] = e
+		}
+		e.Count++
+		e.Examples = append(e.Examples, syl)
+	}
+
+	entries := make([]InventoryEntry, 0, len(byShape))
+	for _, e := range byShape {
+		sort.Slice(e.Examples, func(i, j int) bool {
+			li, lj := len([]rune(e.Examples[i])), len([]rune(e.Examples[j]))
+			if li != lj {
+				return li < lj
+			}
+			return e.Examples[i] < e.Examples[j]
+		})
+		if len(e.Examples) > maxInventoryExamples {
+			e.Examples = e.Examples[:maxInventoryExamples]
+		}
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Initial != b.Initial {
+			return a.Initial < b.Initial
+		}
+		if a.Vowel != b.Vowel {
+			return a.Vowel < b.Vowel
+		}
+		if a.Final != b.Final {
+			return a.Final < b.Final
+		}
+		return toneOrder(a.Tone) < toneOrder(b.Tone)
+	})
+	return entries
+}
+
+// WriteSyllableInventoryCSV writes SyllableInventory to w as CSV with a
+// header row: initial, vowel, final, tone, count, examples (space separated).
+func WriteSyllableInventoryCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"initial", "vowel", "final", "tone", "count", "examples"}); err != nil {
+		return err
+	}
+	for _, e := range SyllableInventory() {
+		record := []string{e.Initial, e.Vowel, e.Final, e.Tone, strconv.Itoa(e.Count), strings.Join(e.Examples, " ")}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// SplitPaiboonSyllable decomposes a single romanized syllable such as
+// "bplaa" or "nʉ̂ng" into its initial, vowel, final and tone. ok is false
+// when syllable isn't a single well-formed Paiboon syllable.
+func SplitPaiboonSyllable(syllable string) (initial, vowel, final, tone string, ok bool) {
+	tone = "mid"
+	var base strings.Builder
+	for _, r := range norm.NFD.String(syllable) {
+		if t, isTone := toneByMark[r]; isTone {
+			tone = t
+			continue
+		}
+		if unicode.Is(unicode.Mn, r) {
+			return "", "", "", "", false
+		}
+		base.WriteRune(r)
+	}
+	s := base.String()
+
+	for _, in := range paiboonInitials {
+		if strings.HasPrefix(s, in) {
+			initial = in
+			break
+		}
+	}
+	s = s[len(initial):]
+	for _, f := range paiboonFinals {
+		if strings.HasSuffix(s, f) {
+			final = f
+			break
+		}
+	}
+	vowel = s[:len(s)-len(final)]
+	if vowel == "" {
+		return "", "", "", "", false
+	}
+	for _, r := range vowel {
+		if !strings.ContainsRune("aeiouɛɔəʉ", r) {
+			return "", "", "", "", false
+		}
+	}
+	return initial, vowel, final, tone, true
+}
+
+// toneOrder sorts tones in the order of the Paiboon chart
+func toneOrder(tone string) int {
+	switch tone {
+	case "mid":
+		return 0
+	case "low":
+		return 1
+	case "falling":
+		return 2
+	case "high":
+		return 3
+	}
+	return 4
+}
@@ -0,0 +1,149 @@
+package paiboonizer
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ToneNotation selects how IPA writes tones (see ToIPA). In every
+// notation syllables are separated by ".", the IPA syllable break, in
+// place of the Paiboon "-" and "~"; words stay separated by a space.
+type ToneNotation int
+
+const (
+	ToneLetters    ToneNotation = iota // Chao tone letters after the syllable: saʔ˨˩.wat̚˨˩.diː˧. The default.
+	ToneDiacritics                     // IPA diacritics on the vowel: sàʔ.wàt̚.dīː
+	ToneNumbers                        // Chao pitch numbers after the syllable: saʔ21.wat̚21.diː33
+)
+
+// String returns the name of the notation
+func (n ToneNotation) String() string {
+	switch n {
+	case ToneLetters:
+		return "letters"
+	case ToneDiacritics:
+		return "diacritics"
+	case ToneNumbers:
+		return "numbers"
+	}
+	return "unknown"
+}
+
+// ipaTones maps the tone names to their Chao tone letters and pitch
+// numbers, and to the IPA diacritic written on the vowel
+var ipaTones = map[string]struct{ letters, numbers, diacritic string }{
+	"mid":     {"˧", "33", "̄"},
+	"low":     {"˨˩", "21", "̀"},
+	"falling": {"˥˩", "51", "̂"},
+	"high":    {"˦˥", "45", "́"},
+	"rising":  {"˩˦", "14", "̌"},
+}
+
+// ipaInitials maps the Paiboon initials to IPA; aspiration is written
+// with ʰ and the glottal stop of syllables written with อ is restored
+var ipaInitials = map[string]string{
+	"": "ʔ", "g": "k", "k": "kʰ", "bp": "p", "p": "pʰ", "dt": "t", "t": "tʰ",
+	"j": "tɕ", "ch": "tɕʰ", "ng": "ŋ", "y": "j",
+	"gr": "kr", "gl": "kl", "gw": "kw", "kr": "kʰr", "kl": "kʰl", "kw": "kʰw",
+	"bpr": "pr", "bpl": "pl", "pr": "pʰr", "pl": "pʰl", "dtr": "tr", "tr": "tʰr",
+}
+
+// ipaFinals maps the Paiboon finals to IPA; final stops are unreleased
+var ipaFinals = map[string]string{"k": "k̚", "t": "t̚", "p": "p̚", "ng": "ŋ"}
+
+// ipaVowels rewrites the Paiboon vowel letters IPA writes differently
+var ipaVowels = map[rune]string{'ʉ': "ɯ", 'ə': "ɤ"}
+
+// ipaScheme returns the IPA scheme writing tones in notation
+func ipaScheme(name string, notation ToneNotation) Scheme {
+	tones := map[string]string{}
+	for tone, t := range ipaTones {
+		switch notation {
+		case ToneLetters:
+			tones[tone] = t.letters
+		case ToneNumbers:
+			tones[tone] = t.numbers
+		}
+	}
+	return Scheme{
+		Name:     name,
+		Letters:  ipaVowels,
+		Tones:    tones,
+		Boundary: ".",
+		Syllable: func(initial, vowel, final, tone string) string {
+			return ipaSyllable(initial, vowel, final, tone, notation)
+		},
+	}
+}
+
+// The IPA schemes: "ipa" writes Chao tone letters, "ipa-diacritics" and
+// "ipa-numbers" the other notations
+var (
+	ipaLettersScheme    = ipaScheme("ipa", ToneLetters)
+	ipaDiacriticsScheme = ipaScheme("ipa-diacritics", ToneDiacritics)
+	ipaNumbersScheme    = ipaScheme("ipa-numbers", ToneNumbers)
+)
+
+// ToIPA renders a romanization in the IPA, for speech synthesis and
+// linguistics: aspiration, vowel length, glides, unreleased final stops
+// and the glottal stops Paiboon leaves implicit are written, and tones in
+// notation (kwaam-sùk → kʰwaːm˧.suk̚˨˩). Syllable separators become ".";
+// text that isn't a Paiboon syllable only has ʉ and ə rewritten. It is the
+// "ipa", "ipa-diacritics" and "ipa-numbers" Scheme.
+func ToIPA(roman string, notation ToneNotation) string {
+	switch notation {
+	case ToneDiacritics:
+		return ipaDiacriticsScheme.Render(roman)
+	case ToneNumbers:
+		return ipaNumbersScheme.Render(roman)
+	}
+	return ipaLettersScheme.Render(roman)
+}
+
+// ipaSyllable renders a Paiboon syllable in the IPA
+func ipaSyllable(initial, vowel, final, tone string, notation ToneNotation) string {
+	if in, ok := ipaInitials[initial]; ok {
+		initial = in
+	}
+	// A trailing i, o or u after another vowel letter is a glide: ai, aao, iu
+	glide := ""
+	if n := len(vowel); n > 1 && strings.ContainsRune("iou", rune(vowel[n-1])) && vowel[n-2] != vowel[n-1] {
+		glide = map[byte]string{'i': "j", 'o': "w", 'u': "w"}[vowel[n-1]]
+		vowel = vowel[:n-1]
+	}
+	// Doubled letters are long vowels; the long diphthongs iia, ʉʉa and uua
+	// are written ia, ɯa and ua as in most descriptions of Thai
+	var b strings.Builder
+	prev := rune(0)
+	for _, r := range vowel {
+		switch {
+		case r == prev:
+			b.WriteString("ː")
+		case ipaVowels[r] != "":
+			b.WriteString(ipaVowels[r])
+		default:
+			b.WriteRune(r)
+		}
+		prev = r
+	}
+	nucleus := b.String()
+	for _, long := range []string{"iːa", "ɯːa", "uːa"} {
+		nucleus = strings.Replace(nucleus, long, strings.Replace(long, "ː", "", 1), 1)
+	}
+	if f, ok := ipaFinals[final]; ok {
+		final = f
+	}
+	// Short vowels closing a syllable end in a glottal stop: sà → saʔ
+	if final == "" && glide == "" && !strings.Contains(nucleus, "ː") && len([]rune(nucleus)) == 1 {
+		final = "ʔ"
+	}
+	t := ipaTones[tone]
+	switch notation {
+	case ToneDiacritics:
+		_, size := utf8.DecodeRuneInString(nucleus)
+		return initial + nucleus[:size] + t.diacritic + nucleus[size:] + glide + final
+	case ToneNumbers:
+		return initial + nucleus + glide + final + t.numbers
+	}
+	return initial + nucleus + glide + final + t.letters
+}
@@ -0,0 +1,30 @@
+package paiboonizer
+
+import "testing"
+
+func TestToIPASyllableBoundaries(t *testing.T) {
+	tests := []struct {
+		roman    string
+		notation ToneNotation
+		want     string
+	}{
+		{"sà~wàt-dii", ToneLetters, "saʔ˨˩.wat̚˨˩.diː˧"},
+		{"sà~wàt-dii", ToneNumbers, "saʔ21.wat̚21.diː33"},
+		{"kwaam-sùk dii", ToneLetters, "kʰwaːm˧.suk̚˨˩ diː˧"},
+	}
+	for _, tt := range tests {
+		if got := ToIPA(tt.roman, tt.notation); got != tt.want {
+			t.Errorf("ToIPA(%q, %v) = %q, want %q", tt.roman, tt.notation, got, tt.want)
+		}
+	}
+
+	opts := DefaultOptions()
+	opts.Scheme = "ipa"
+	if got := opts.render("kwaam-sùk"); got != "kʰwaːm˧.suk̚˨˩" {
+		t.Errorf("render(kwaam-sùk) in IPA = %q", got)
+	}
+	opts.Separator = Separator{Mark: MarkSpace}
+	if got := opts.render("kwaam-sùk"); got != "kʰwaːm˧ suk̚˨˩" {
+		t.Errorf("render(kwaam-sùk) in IPA with spaces = %q", got)
+	}
+}
@@ -0,0 +1,145 @@
+package paiboonizer
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// iso11940Letters maps every Thai character to its ISO 11940
+// transliteration. Signs written over or under a letter become combining
+// marks on the transliteration of that letter.
+var iso11940Letters = map[rune]string{
+	// Consonants
+	'ก': "k", 'ข': "k̄h", 'ฃ': "ḳ̄h", 'ค': "kh", 'ฅ': "k̛h", 'ฆ': "ḳh",
+	'ง': "ng", 'จ': "c", 'ฉ': "c̄h", 'ช': "ch", 'ซ': "s", 'ฌ': "c̣h",
+	'ญ': "ỵ", 'ฎ': "ḍ", 'ฏ': "ṭ", 'ฐ': "ṭ̄h", 'ฑ': "ṯh", 'ฒ': "t̛h",
+	'ณ': "ṇ", 'ด': "d", 'ต': "t", 'ถ': "t̄h", 'ท': "th", 'ธ': "ṭh",
+	'น': "n", 'บ': "b", 'ป': "p", 'ผ': "p̄h", 'ฝ': "f̄", 'พ': "ph",
+	'ฟ': "f", 'ภ': "p̣h", 'ม': "m", 'ย': "y", 'ร': "r", 'ฤ': "v",
+	'ล': "l", 'ฦ': "ł", 'ว': "w", 'ศ': "ṣ̄", 'ษ': "s̛̄", 'ส': "s̄",
+	'ห': "h̄", 'ฬ': "ḷ", 'อ': "x", 'ฮ': "ḥ",
+	// Vowels
+	'ะ': "a", 'ั': "ạ", 'า': "ā", 'ำ': "å", 'ิ': "i", 'ี': "ī",
+	'ึ': "ụ", 'ื': "ụ̄", 'ุ': "u", 'ู': "ū", 'เ': "e", 'แ': "æ",
+	'โ': "o", 'ใ': "ı", 'ไ': "ị", 'ๅ': "ɨ",
+	// Signs
+	'ฺ': "̥", '็': "̆", '่': "̀", '้': "̂", '๊': "̃", '๋': "̈",
+	'์': "̒", 'ํ': "̊", '๎': "~", 'ฯ': "ǂ", 'ๆ': "«", '๏': "§",
+	'๚': "ǁ", '๛': "»",
+	// Digits
+	'๐': "0", '๑': "1", '๒': "2", '๓': "3", '๔': "4", '๕': "5", '๖': "6", '๗': "7", '๘': "8", '๙': "9",
+}
+
+// iso11940Thai is the reverse of iso11940Letters, keyed by NFD
+// transliteration, and iso11940Longest the byte length of its longest key
+var (
+	iso11940Thai    = make(map[string]rune)
+	iso11940Longest int
+)
+
+func init() {
+	for r, latin := range iso11940Letters {
+		key := norm.NFD.String(latin)
+		iso11940Thai[key] = r
+		iso11940Longest = max(iso11940Longest, len(key))
+	}
+}
+
+// ISO11940 transliterates Thai letter by letter following ISO 11940,
+// without any phonetic analysis: every consonant, vowel, tone mark and
+// sign has its own Latin form, silent letters and implicit vowels
+// included, so the original spelling can be recovered exactly with
+// FromISO11940 (ภาษาไทย → p̣hās̛̄āịthy). Meant for catalogues and corpora
+// that need lossless transliteration rather than pronunciation. Other
+// characters are copied as they are, so text mixing them with Thai only
+// round-trips when they aren't Latin letters or ASCII digits. The output
+// is in NFC.
+func ISO11940(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if latin, ok := iso11940Letters[r]; ok {
+			b.WriteString(latin)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return norm.NFC.String(b.String())
+}
+
+// FromISO11940 restores the Thai spelling of an ISO 11940
+// transliteration (see ISO11940). Text that isn't part of a
+// transliteration is copied as it is.
+func FromISO11940(latin string) string {
+	s := norm.NFD.String(latin)
+	// best[i] is the length of the key chosen at byte i on a parse of
+	// s[i:] into keys and copied runes using the fewest copied runes
+	type step struct{ length, copied int }
+	best := make([]step, len(s)+1)
+	for i := len(s) - 1; i >= 0; i-- {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		best[i] = step{size, best[min(i+size, len(s))].copied + 1}
+		for n := min(iso11940Longest, len(s)-i); n > 0; n-- {
+			if _, ok := iso11940Thai[s[i:i+n]]; ok && best[i+n].copied < best[i].copied {
+				best[i] = step{n, best[i+n].copied}
+			}
+		}
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i += best[i].length {
+		piece := s[i : i+best[i].length]
+		if r, ok := iso11940Thai[piece]; ok {
+			b.WriteRune(r)
+		} else {
+			b.WriteString(piece)
+		}
+	}
+	return norm.NFC.String(b.String())
+}
+
+// iso11940Part2Scheme is the "iso11940-2" scheme: ISO 11940-2, the broad
+// phonetic transcription of the standard, rendered from the syllables of
+// the romanization. It writes aspiration with h, neither vowel length nor
+// tones, and ʉ, ɛ, ɔ and ə as ue, ae, o and oe.
+var iso11940Part2Scheme = Scheme{
+	Name:     "iso11940-2",
+	Letters:  iso11940Part2Vowels,
+	Syllable: iso11940Part2Syllable,
+}
+
+// iso11940Part2Vowels rewrites the Paiboon vowel letters outside ASCII
+var iso11940Part2Vowels = map[rune]string{'ʉ': "ue", 'ɛ': "ae", 'ɔ': "o", 'ə': "oe"}
+
+// iso11940Part2Initials maps the Paiboon initials ISO 11940-2 writes
+// differently: unaspirated stops plainly, aspirated ones with h
+var iso11940Part2Initials = map[string]string{
+	"g": "k", "k": "kh", "bp": "p", "p": "ph", "dt": "t", "t": "th", "j": "ch",
+	"gr": "kr", "gl": "kl", "gw": "kw", "kr": "khr", "kl": "khl", "kw": "khw",
+	"bpr": "pr", "bpl": "pl", "pr": "phr", "pl": "phl", "dtr": "tr", "tr": "thr",
+}
+
+// iso11940Part2Syllable renders a Paiboon syllable in ISO 11940-2. Finals
+// are written as Paiboon writes them; the tone is left out.
+func iso11940Part2Syllable(initial, vowel, final, tone string) string {
+	if in, ok := iso11940Part2Initials[initial]; ok {
+		initial = in
+	}
+	// Length isn't written: aa → a, ʉʉa → uea
+	var b strings.Builder
+	prev := rune(0)
+	for _, r := range vowel {
+		if r != prev {
+			if letters, ok := iso11940Part2Vowels[r]; ok {
+				b.WriteString(letters)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+		prev = r
+	}
+	if vowel = b.String(); vowel == "iu" {
+		vowel = "io"
+	}
+	return initial + vowel + final
+}
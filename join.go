@@ -0,0 +1,87 @@
+package paiboonizer
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// JoinPolicy selects how the rules join the syllables of a word. Words
+// found in a dictionary keep the separators of their entry; Separator
+// rewrites both uniformly.
+type JoinPolicy int
+
+const (
+	// JoinDictionary separates syllables the way the dictionary does: "~"
+	// after a short open syllable (sà~màk), "-" otherwise (kwaam-sùk). The
+	// default.
+	JoinDictionary JoinPolicy = iota
+	// JoinHyphen separates every syllable with "-" (sà-màk)
+	JoinHyphen
+	// JoinNone runs the syllables together (kwaamsùk), as the rules used to
+	JoinNone
+)
+
+// String returns the name of the policy
+func (p JoinPolicy) String() string {
+	switch p {
+	case JoinDictionary:
+		return "dictionary"
+	case JoinHyphen:
+		return "hyphen"
+	case JoinNone:
+		return "none"
+	}
+	return "unknown"
+}
+
+// join joins the romanized parts of a word. A part may hold several
+// syllables already separated (a syllable dictionary entry like tá~yaan);
+// the separator after it depends on its last syllable.
+func (p JoinPolicy) join(parts []string) string {
+	if p == JoinNone || len(parts) < 2 {
+		return strings.Join(parts, "")
+	}
+	var sb strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			sb.WriteString(p.separator(parts[i-1]))
+		}
+		sb.WriteString(part)
+	}
+	return sb.String()
+}
+
+// separator returns the separator written after part
+func (p JoinPolicy) separator(part string) string {
+	if p == JoinDictionary && isShortOpenSyllable(lastRomanSyllable(part)) {
+		return "~"
+	}
+	return "-"
+}
+
+// lastRomanSyllable returns the syllable after the last separator of s
+func lastRomanSyllable(s string) string {
+	if i := strings.LastIndexAny(s, "-~"); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
+
+// isShortOpenSyllable reports whether a romanized syllable has a single
+// vowel letter and nothing after it (sà, grà, bprà), the unstressed
+// syllables the dictionary links to the next one with "~"
+func isShortOpenSyllable(syl string) bool {
+	vowels := 0
+	for _, r := range norm.NFD.String(syl) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case isRomanVowel(r):
+			vowels++
+		case vowels > 0 || !unicode.IsLetter(r):
+			return false
+		}
+	}
+	return vowels == 1
+}
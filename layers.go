@@ -0,0 +1,161 @@
+package paiboonizer
+
+import (
+	"golang.org/x/text/unicode/norm"
+)
+
+// LookupLayer is a table of whole-word romanizations consulted before the
+// rules (see Options.Layers). The layers only decide which tables whole
+// words are looked up in: leaving out LayerSpecialCases or LayerSyllables
+// doesn't disable them inside the rules, which still match special cases
+// and known syllables within the words they romanize.
+type LookupLayer int
+
+const (
+	LayerOverrides    LookupLayer = iota // The words of Options.Overrides, written as given
+	LayerRoyal                           // The royal and ecclesiastical vocabulary (LookupRoyal)
+	LayerSpecialCases                    // Irregular words and loanwords (LookupSpecialCase)
+	LayerOfficial                        // The official vocab files
+	LayerOpus                            // The Opus dictionary
+	LayerSyllables                       // The syllable dictionary (LookupSyllable)
+)
+
+// layerSpelling marks the words lookupWord reads without a table: tokens
+// already romanized and tokens spelled out (see spellOutWord)
+const layerSpelling LookupLayer = -1
+
+// String returns the name of the layer
+func (l LookupLayer) String() string {
+	switch l {
+	case LayerOverrides:
+		return "overrides"
+	case LayerRoyal:
+		return "royal"
+	case LayerSpecialCases:
+		return "special-cases"
+	case LayerOfficial:
+		return "official"
+	case LayerOpus:
+		return "opus"
+	case LayerSyllables:
+		return "syllables"
+	case layerSpelling:
+		return "spelling"
+	}
+	return "unknown"
+}
+
+// AllLayers returns every layer, in their default precedence: overrides,
+// royal vocabulary, special cases, official dictionary, Opus dictionary,
+// then syllable dictionary
+func AllLayers() []LookupLayer {
+	return []LookupLayer{LayerOverrides, LayerRoyal, LayerSpecialCases, LayerOfficial, LayerOpus, LayerSyllables}
+}
+
+// lookup returns the romanization of word in the layer, NFC
+func (l LookupLayer) lookup(word string, opts Options) (string, bool) {
+	ensureDictionaryLoaded()
+	var trans string
+	var ok bool
+	switch l {
+	case LayerOverrides:
+		return opts.Overrides.word(word)
+	case LayerRoyal:
+		return LookupRoyal(word)
+	case LayerSpecialCases:
+		trans, ok = specialCasesGlobal[word]
+	case LayerOfficial:
+		trans, ok = dictionary.roman(word)
+	case LayerOpus:
+		trans, ok = opusDictionary.roman(word)
+	case LayerSyllables:
+		trans, ok = syllableDict[word]
+	}
+	if !ok {
+		return "", false
+	}
+	return norm.NFC.String(trans), true
+}
+
+// The layers of the paths that take no Options, read through lookupWord
+// like Options.Layers: the Manager, unless WithLookupLayers, and
+// TransliterateWordRulesOnly look whole words up in the official
+// dictionary only; the syllables pythainlp splits for the dictionary test
+// are looked up in the syllable dictionary, then the special cases.
+var (
+	managerLayers           = []LookupLayer{LayerOfficial}
+	rulesOnlyLayers         = []LookupLayer{LayerOfficial}
+	pythainlpSyllableLayers = []LookupLayer{LayerSyllables, LayerSpecialCases}
+)
+
+// WithLookupLayers sets the whole-word tables ThaiToRoman consults before
+// the rules, in order, as Options.Layers does for the other pipelines. By
+// default the text and the words pythainlp splits are looked up in the
+// official dictionary alone, and the internal segmentation serving text
+// while pythainlp is unavailable uses the layers of DefaultOptions. It is
+// the WithLayers of the Manager, which can't share the name of the
+// Transliterator option.
+func WithLookupLayers(layers ...LookupLayer) ManagerOption {
+	return func(m *Manager) {
+		m.layers = layers
+	}
+}
+
+// lookupLayers returns the layers ThaiToRoman looks words up in
+func (m *Manager) lookupLayers() []LookupLayer {
+	if m.layers != nil {
+		return m.layers
+	}
+	return managerLayers
+}
+
+// layers returns the lookup layers of opts in order: Layers when set,
+// otherwise the overrides, the royal tier when enabled and the
+// dictionaries Dictionary selects
+func (opts Options) layers() []LookupLayer {
+	if opts.Layers != nil {
+		return opts.Layers
+	}
+	layers := []LookupLayer{LayerOverrides}
+	if opts.RoyalVocabulary {
+		layers = append(layers, LayerRoyal)
+	}
+	switch opts.Dictionary {
+	case DictionaryAll:
+		layers = append(layers, LayerOfficial, LayerOpus)
+	case DictionaryOfficial:
+		layers = append(layers, LayerOfficial)
+	}
+	return layers
+}
+
+// lookupWord finds word in the layers of opts, in order. Tokens already
+// romanized and tokens to spell out are read before the first layer other
+// than the overrides, and reported as layerSpelling. Returns false when
+// word is left to the rules.
+func lookupWord(word string, opts Options) (string, LookupLayer, bool) {
+	spelled := false
+	spell := func() (string, bool) {
+		spelled = true
+		if LooksRomanized(word) {
+			return word, true
+		}
+		return spellOutWord(word, opts)
+	}
+	for _, l := range opts.layers() {
+		if l != LayerOverrides && !spelled {
+			if trans, ok := spell(); ok {
+				return trans, layerSpelling, true
+			}
+		}
+		if trans, ok := l.lookup(word, opts); ok {
+			return trans, l, true
+		}
+	}
+	if !spelled {
+		if trans, ok := spell(); ok {
+			return trans, layerSpelling, true
+		}
+	}
+	return "", 0, false
+}
@@ -0,0 +1,128 @@
+package paiboonizer
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// kedmaneeLayout maps the characters of a US QWERTY keyboard to the Thai
+// characters of the same keys on the Kedmanee layout, shifted and not.
+// Text typed with the wrong layout selected comes out as the QWERTY side
+// (l;ylfu for สวัสดี).
+var kedmaneeLayout = map[rune]rune{
+	'`': '_', '1': 'ๅ', '2': '/', '3': '-', '4': 'ภ', '5': 'ถ', '6': 'ุ', '7': 'ึ', '8': 'ค', '9': 'ต', '0': 'จ', '-': 'ข', '=': 'ช',
+	'q': 'ๆ', 'w': 'ไ', 'e': 'ำ', 'r': 'พ', 't': 'ะ', 'y': 'ั', 'u': 'ี', 'i': 'ร', 'o': 'น', 'p': 'ย', '[': 'บ', ']': 'ล', '\\': 'ฃ',
+	'a': 'ฟ', 's': 'ห', 'd': 'ก', 'f': 'ด', 'g': 'เ', 'h': '้', 'j': '่', 'k': 'า', 'l': 'ส', ';': 'ว', '\'': 'ง',
+	'z': 'ผ', 'x': 'ป', 'c': 'แ', 'v': 'อ', 'b': 'ิ', 'n': 'ื', 'm': 'ท', ',': 'ม', '.': 'ใ', '/': 'ฝ',
+
+	'~': '%', '!': '+', '@': '๑', '#': '๒', '$': '๓', '%': '๔', '^': 'ู', '&': '฿', '*': '๕', '(': '๖', ')': '๗', '_': '๘', '+': '๙',
+	'Q': '๐', 'W': '"', 'E': 'ฎ', 'R': 'ฑ', 'T': 'ธ', 'Y': 'ํ', 'U': '๊', 'I': 'ณ', 'O': 'ฯ', 'P': 'ญ', '{': 'ฐ', '}': ',', '|': 'ฅ',
+	'A': 'ฤ', 'S': 'ฆ', 'D': 'ฏ', 'F': 'โ', 'G': 'ฌ', 'H': '็', 'J': '๋', 'K': 'ษ', 'L': 'ศ', ':': 'ซ', '"': '.',
+	'Z': '(', 'X': ')', 'C': 'ฉ', 'V': 'ฮ', 'B': 'ฺ', 'N': '์', 'M': '?', '<': 'ฒ', '>': 'ฬ', '?': 'ฦ',
+}
+
+// minLayoutTypoLen is the shortest run read as a layout typo. Shorter runs
+// are too often real Latin words that happen to map to a Thai word (me to
+// ทำ).
+const minLayoutTypoLen = 3
+
+// FixLayoutTypos replaces the Latin runs of text that were Thai typed with
+// the wrong keyboard layout (l;ylfu 8iy[ for สวัสดี ครับ) with the Thai
+// they were meant to be. A run of at least minLayoutTypoLen characters is
+// replaced when its Kedmanee reading splits entirely into dictionary
+// words; trailing punctuation (.,!?) is kept as typed if the run only
+// reads without it. Other text, Latin words included, is left as is, and
+// so is text that LooksRomanized. The result has as many runes as text,
+// so offsets carry over.
+func FixLayoutTypos(text string) string {
+	fixed, _ := fixLayoutTypos(text)
+	return fixed
+}
+
+// fixLayoutTypos is FixLayoutTypos, also returning a warning for each
+// replaced run
+func fixLayoutTypos(text string) (string, []Warning) {
+	ensureDictionaryLoaded()
+	if LooksRomanized(text) {
+		return text, nil
+	}
+	var b strings.Builder
+	var warnings []Warning
+	offset := 0
+	for _, run := range splitScripts(text) {
+		if run.kind == tokenOther {
+			if thai, ok := layoutTypo(run.text); ok {
+				warnings = append(warnings, Warning{WarnLayoutTypo, run.text, offset, "typed with the wrong keyboard layout, read as " + thai})
+				run.text = thai
+			}
+		}
+		b.WriteString(run.text)
+		offset += len([]rune(run.text))
+	}
+	return b.String(), warnings
+}
+
+// layoutTypo returns the Thai a Latin run reads as on the Kedmanee layout,
+// if that reading is made of dictionary words
+func layoutTypo(run string) (string, bool) {
+	for body := run; utf8.RuneCountInString(body) >= minLayoutTypoLen; body = body[:len(body)-1] {
+		if thai, ok := kedmaneeReading(body); ok {
+			return thai + run[len(body):], true
+		}
+		// Retry without a trailing punctuation mark, one at a time
+		if !strings.ContainsRune(".,!?", rune(body[len(body)-1])) {
+			break
+		}
+	}
+	return "", false
+}
+
+// kedmaneeReading maps s key for key to Kedmanee and reports whether the
+// result splits into dictionary words with nothing left over. A run that
+// could be a Latin word (see latinLike) must read as a single word: love
+// splits into สน อำ.
+func kedmaneeReading(s string) (string, bool) {
+	var b strings.Builder
+	for _, r := range s {
+		thai, ok := kedmaneeLayout[r]
+		if !ok {
+			return "", false
+		}
+		b.WriteRune(thai)
+	}
+	thai := b.String()
+	words := segmentThai(thai, Options{})
+	if latinLike(s) && len(words) > 1 {
+		return "", false
+	}
+	for _, w := range words {
+		if w.kind != tokenThai || (w.text != "ๆ" && !isKnownWord(w.text, Options{})) {
+			return "", false
+		}
+	}
+	return thai, startsWithThaiLetter(thai)
+}
+
+// startsWithThaiLetter reports whether s starts with a consonant or a
+// leading vowel, as a Thai word does
+func startsWithThaiLetter(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return isConsonantRune(r) || isLeadingVowel(string(r))
+}
+
+// latinLike reports whether s is spelled like a Latin word: letters only,
+// lower case after a possible capital, with a vowel
+func latinLike(s string) bool {
+	vowel := false
+	for i, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z':
+			vowel = vowel || strings.ContainsRune("aeiouy", r)
+		case r >= 'A' && r <= 'Z' && i == 0:
+			vowel = vowel || strings.ContainsRune("AEIOUY", r)
+		default:
+			return false
+		}
+	}
+	return vowel
+}
@@ -0,0 +1,89 @@
+package paiboonizer
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// letterNames maps each Thai consonant to its full letter name as used when
+// spelling words out loud (ก is read "gɔɔ gài", i.e. "ก as in ไก่").
+var letterNames = map[rune]string{
+	'ก': "gɔɔ gài", 'ข': "kɔ̌ɔ kài", 'ฃ': "kɔ̌ɔ kùuat", 'ค': "kɔɔ kwaai",
+	'ฅ': "kɔɔ kon", 'ฆ': "kɔɔ rá~kang", 'ง': "ngɔɔ nguu", 'จ': "jɔɔ jaan",
+	'ฉ': "chɔ̌ɔ chìng", 'ช': "chɔɔ cháang", 'ซ': "sɔɔ sôo", 'ฌ': "chɔɔ gà~chəə",
+	'ญ': "yɔɔ yǐng", 'ฎ': "dɔɔ chá~daa", 'ฏ': "dtɔɔ bpà~dtàk", 'ฐ': "tɔ̌ɔ tǎan",
+	'ฑ': "tɔɔ mon-too", 'ฒ': "tɔɔ pûu-tâo", 'ณ': "nɔɔ neen", 'ด': "dɔɔ dèk",
+	'ต': "dtɔɔ dtào", 'ถ': "tɔ̌ɔ tǔng", 'ท': "tɔɔ tá~hǎan", 'ธ': "tɔɔ tong",
+	'น': "nɔɔ nǔu", 'บ': "bɔɔ bai-máai", 'ป': "bpɔɔ bplaa", 'ผ': "pɔ̌ɔ pʉ̂ng",
+	'ฝ': "fɔ̌ɔ fǎa", 'พ': "pɔɔ paan", 'ฟ': "fɔɔ fan", 'ภ': "pɔɔ sǎm-pao",
+	'ม': "mɔɔ máa", 'ย': "yɔɔ yák", 'ร': "rɔɔ rʉʉa", 'ล': "lɔɔ ling",
+	'ว': "wɔɔ wɛ̌ɛn", 'ศ': "sɔ̌ɔ sǎa-laa", 'ษ': "sɔ̌ɔ rʉʉ-sǐi", 'ส': "sɔ̌ɔ sʉ̌ʉa",
+	'ห': "hɔ̌ɔ hìip", 'ฬ': "lɔɔ jù-laa", 'อ': "ɔɔ àang", 'ฮ': "hɔɔ nók-hûuk",
+}
+
+// markNames holds the names of tone marks and other signs that can show up
+// on their own in spelling contexts
+var markNames = map[rune]string{
+	'่': "mái èek", '้': "mái too", '๊': "mái dtrii", '๋': "mái jàt-dtà~waa",
+	'์': "gaa-ran", '็': "mái tài-kúu", 'ๆ': "mái yá~mók",
+}
+
+// LetterName returns the full spelling name of a Thai consonant or sign
+// (ก → "gɔɔ gài", ่ → "mái èek"). Returns ("", false) for other runes.
+func LetterName(r rune) (string, bool) {
+	if name, ok := letterNames[r]; ok {
+		return norm.NFC.String(name), true
+	}
+	if name, ok := markNames[r]; ok {
+		return norm.NFC.String(name), true
+	}
+	return "", false
+}
+
+// letterReading returns the short reading of a consonant used inside
+// acronyms: the letter name without its key word (ก → "gɔɔ").
+func letterReading(r rune) (string, bool) {
+	name, ok := letterNames[r]
+	if !ok {
+		return "", false
+	}
+	return norm.NFC.String(strings.Fields(name)[0]), true
+}
+
+// SpellOut reads every consonant of text by its short letter reading and
+// joins them with hyphens, the way acronyms are read aloud
+// (กทม → "gɔɔ-tɔɔ-mɔɔ"). Dots and other non-consonants are skipped.
+func SpellOut(text string) string {
+	readings := []string{}
+	for _, r := range text {
+		if reading, ok := letterReading(r); ok {
+			readings = append(readings, reading)
+		}
+	}
+	return strings.Join(readings, "-")
+}
+
+// isAcronymCandidate reports whether word follows the dotted abbreviation
+// pattern (ก.ท.ม., กทม., พ.ศ.): 1 to 5 consonants with no vowel or tone
+// marks, at least one of them followed by a dot. Undotted consonant strings
+// are ambiguous with real words written without vowels (คน, รถ, ถนน), so
+// they are only spelled out when Options.SpellOut is set.
+func isAcronymCandidate(word string) bool {
+	count := 0
+	dotted := false
+	for _, r := range word {
+		switch {
+		case r == '.':
+			if count == 0 {
+				return false
+			}
+			dotted = true
+		case isConsonantRune(r):
+			count++
+		default:
+			return false
+		}
+	}
+	return dotted && count <= 5
+}
@@ -0,0 +1,65 @@
+package paiboonizer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// BreakPoints returns where a line may break inside the romanized words of
+// roman: the byte offsets just after each "-" or "~" joining two
+// syllables (kwaam-|sùk, sà~|màk), in increasing order. Breaking at offset
+// i puts roman[:i], separator included, on the first line, so a syllable
+// is never split and its tone mark stays on it. Spaces are left to the
+// layout engine's own rules, and output joined with JoinNone has no break
+// points since its syllable boundaries aren't written.
+func BreakPoints(roman string) []int {
+	var points []int
+	for i, r := range roman {
+		if r != '-' && r != '~' || i == 0 {
+			continue
+		}
+		before, _ := utf8.DecodeLastRuneInString(roman[:i])
+		after, _ := utf8.DecodeRuneInString(roman[i+1:])
+		if (unicode.IsLetter(before) || unicode.IsMark(before)) && unicode.IsLetter(after) {
+			points = append(points, i+1)
+		}
+	}
+	return points
+}
+
+// TruncateRomanization shortens roman to at most max columns, cutting at
+// the last syllable boundary that fits (before a "-", "~" or space) so the
+// result never ends in half a syllable or a separator. A column is a
+// letter with its combining marks, so tone marks are never cut off their
+// vowel whether roman is NFC or NFD. If the first syllable alone is wider
+// than max it is cut after max letters. roman is returned as is when it
+// fits.
+func TruncateRomanization(roman string, max int) string {
+	if max <= 0 {
+		return ""
+	}
+	columns := 0
+	lastBoundary := -1 // End of the last whole syllable that fits
+	cut := len(roman)  // End of the first max columns
+	for i, r := range roman {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if (r == '-' || r == '~' || r == ' ') && i > 0 {
+			lastBoundary = i
+		}
+		if columns == max {
+			cut = i
+			break
+		}
+		columns++
+	}
+	if cut == len(roman) {
+		return roman
+	}
+	if lastBoundary > 0 {
+		return strings.TrimRight(roman[:lastBoundary], "-~ ")
+	}
+	return roman[:cut]
+}
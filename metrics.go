@@ -0,0 +1,43 @@
+package paiboonizer
+
+// Metrics receives operational events from a Manager so that long-running
+// services can monitor the pythainlp integration without scraping logs.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// IncCounter increments the named counter by one
+	IncCounter(name string)
+	// SetGauge sets the named gauge to value
+	SetGauge(name string, value float64)
+}
+
+// Metric names reported by Manager
+const (
+	// MetricPythainlpDeadline counts calls that exceeded FallbackAfter and
+	// were served by internal segmentation instead
+	MetricPythainlpDeadline = "paiboonizer_pythainlp_deadline_fallbacks_total"
+	// MetricBreakerState is a gauge holding the BreakerState value
+	MetricBreakerState = "paiboonizer_pythainlp_breaker_state"
+	// MetricBreakerOpened counts transitions of the breaker to open
+	MetricBreakerOpened = "paiboonizer_pythainlp_breaker_opened_total"
+	// MetricPoolHealthy is a gauge holding the number of pool members
+	// still in rotation
+	MetricPoolHealthy = "paiboonizer_pythainlp_pool_healthy"
+	// MetricDroppedSpans counts the pieces of Thai input the rules
+	// romanized to nothing (see DroppedSpan)
+	MetricDroppedSpans = "paiboonizer_dropped_spans_total"
+)
+
+// noopMetrics discards everything; used when no Metrics is configured
+type noopMetrics struct{}
+
+func (noopMetrics) IncCounter(string)        {}
+func (noopMetrics) SetGauge(string, float64) {}
+
+// WithMetrics sets the Metrics implementation the Manager reports to
+func WithMetrics(metrics Metrics) ManagerOption {
+	return func(m *Manager) {
+		if metrics != nil {
+			m.metrics = metrics
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package paiboonizer
+
+import (
+	"context"
+)
+
+// Options controls optional transliteration behaviour. The zero value turns
+// every optional behaviour off.
+type Options struct {
+	// SpellOut reads consonant-only tokens letter by letter: an isolated
+	// consonant gets its full name (ก → "gɔɔ gài") and longer tokens get
+	// short letter readings (กทม → "gɔɔ-tɔɔ-mɔɔ").
+	SpellOut bool
+	// DetectAcronyms reads tokens that look like dotted abbreviations (see
+	// isAcronymCandidate) using Acronyms even when SpellOut is off.
+	DetectAcronyms bool
+	// Acronyms selects whether known abbreviations (กทม., พ.ศ.) are spelled
+	// out or expanded to their full form.
+	Acronyms AcronymMode
+	// RoyalVocabulary enables the royal and ecclesiastical vocabulary tier
+	// (เสวย, ประชวร, อาพาธ), checked before the regular dictionaries.
+	RoyalVocabulary bool
+	// PreserveWhitespace makes TransliterateText copy the whitespace of the
+	// input verbatim (tabs, runs of spaces, leading and trailing space)
+	// instead of collapsing it, for aligned or tabular text. Thai words
+	// written without a space between them are still separated by one.
+	// TransliterateStream, TransliterateJSONL and TransliterateColumns
+	// romanize through TransliterateText and keep it too;
+	// TransliterateTokens ignores it, its whitespace tokens being the
+	// input as given.
+	PreserveWhitespace bool
+	// ConnectedSpeech enables the connected speech pass of TransliterateText
+	// when non-nil: a word found in the map is given its reduced reading
+	// when followed by a content word (ไม่ดี mai dii). DefaultConnectedSpeech
+	// returns the built-in table.
+	ConnectedSpeech map[string]string
+	// Particles selects how TransliterateText marks sentence-final
+	// particles (ครับ, นะ, สิ) so learners can see where utterances end.
+	Particles ParticleMode
+	// Punctuate makes TransliterateText end each line whose last word is
+	// Thai with "?" when it asks a question (see IsQuestion) and "."
+	// otherwise, since Thai rarely writes terminal punctuation but
+	// romanized learner text reads better with it.
+	Punctuate bool
+	// OnFailure selects what replaces the syllables the rules romanize to
+	// nothing. The default, FailurePassThrough, writes their Thai so that
+	// no input is ever lost. Either way they are reported by
+	// TransliterateTextDetailed and TransliterateTokens.
+	OnFailure FailurePolicy
+	// Join selects how the syllables of words left to the rules are
+	// separated. The default, JoinDictionary, matches the dictionary
+	// entries (kwaam-sùk, sà~màk).
+	Join JoinPolicy
+	// Separator rewrites the syllable and word boundaries of every
+	// romanized word uniformly, dictionary entries included; Join is then
+	// ignored. The zero value keeps the output as written.
+	Separator Separator
+	// ASCII renders every romanized word in ASCII only, the Paiboon vowels
+	// spelled out and tones as digits (see ToASCII), for terminals, file
+	// names and systems that can't show them. Overrides are written as
+	// given.
+	ASCII bool
+	// Toneless writes every romanized word without its tones, for
+	// learners working on vowels and for matching text typed without
+	// them. The syllables are rendered without a tone rather than
+	// stripped of their diacritics, so no tone digit, letter or suffix is
+	// written under ASCII, ToneStyle or a Scheme either. Overrides are
+	// written as given.
+	Toneless bool
+	// Scheme is the name of a registered Scheme rendering every romanized
+	// word with its tables, in place of ASCII (see RegisterScheme); an
+	// unknown name renders nothing differently. Overrides are written as
+	// given.
+	Scheme string
+	// ToneStyle selects how tones are written when neither Scheme nor
+	// ASCII renders them: diacritics, the default, or a number or Chao
+	// letters after each syllable. Overrides are written as given.
+	ToneStyle ToneStyle
+	// OutputForm selects the Unicode form of the tone marks, applied last.
+	// The zero value keeps the form each word was written in.
+	OutputForm OutputForm
+	// FixLayoutTypos makes TransliterateText and TransliterateTokens read
+	// Latin runs typed with the wrong keyboard layout as the Thai they were
+	// meant to be (l;ylfu สวัสดี), for chat-derived text. See
+	// FixLayoutTypos.
+	FixLayoutTypos bool
+	// Foreign selects what TransliterateText and TransliterateTokens write
+	// for words of another script embedded in Thai text. The default,
+	// ForeignPassThrough, copies them as they are.
+	Foreign ForeignPolicy
+	// Dictionary selects the word dictionaries consulted before the rules.
+	// The default, DictionaryAll, uses the official and Opus dictionaries.
+	Dictionary DictionaryUse
+	// Engine selects how words no dictionary knows are split into
+	// syllables. The default, EngineAuto, uses pythainlp when available.
+	Engine SyllableEngine
+	// NormalizeInput brings the input to NFC and removes invisible format
+	// characters (zero-width spaces, BOM, soft hyphens) before
+	// romanization. Offsets in results then refer to the normalized text.
+	NormalizeInput bool
+	// Frequency annotates the words of TransliterateTokens with their
+	// frequency band (Token.Band) when non-nil. DefaultFrequencyList
+	// returns the embedded list; see ReadFrequencyList for others.
+	Frequency FrequencyList
+	// Glosser gives the words of TransliterateTokens an English gloss
+	// (Token.Gloss) when non-nil. VocabGlosser uses the vocab files.
+	Glosser Glosser
+	// Sensitive lists the words TransliterateText and TransliterateTokens
+	// rewrite as SensitivePolicy says, and tag with their category
+	// (Token.Sensitive), when non-nil. See ReadSensitiveList.
+	Sensitive SensitiveList
+	// SensitivePolicy selects what the romanization of the words of
+	// Sensitive becomes. The default, SensitiveTag, keeps it.
+	SensitivePolicy SensitivePolicy
+	// Overrides fixes the segmentation and romanization of recurring words
+	// and lines of a project, checked before everything else. See
+	// ReadOverrides.
+	Overrides Overrides
+	// Layers sets which whole-word tables are consulted before the rules,
+	// and in which order (see AllLayers); TransliterateText splits Thai
+	// runs into the words of the same tables. Dictionary and
+	// RoyalVocabulary are then ignored. Nil consults the overrides, the
+	// royal tier when enabled and the dictionaries of Dictionary. Whatever
+	// the layers, the rules still match special cases and known syllables
+	// inside words.
+	Layers []LookupLayer
+}
+
+// DefaultOptions returns the options used by the package-level helpers.
+func DefaultOptions() Options {
+	return Options{
+		DetectAcronyms: true,
+	}
+}
+
+// TransliterateWordWithOptions transliterates a single Thai word, applying
+// the behaviour selected in opts. Words go through the lookup layers
+// (Options.Layers; by default the royal tier when enabled, then the
+// official and Opus dictionaries) before falling back to the rules.
+func TransliterateWordWithOptions(word string, opts Options) string {
+	trans, _ := TransliterateWordWithOptionsContext(context.Background(), word, opts)
+	return trans
+}
+
+// TransliterateWordWithOptionsContext is TransliterateWordWithOptions with
+// a context bounding the pythainlp call of EngineAuto. Returns the
+// context's error if it ends before the word is romanized.
+func TransliterateWordWithOptionsContext(ctx context.Context, word string, opts Options) (string, error) {
+	if opts.NormalizeInput {
+		word = normalizeInput(word)
+	}
+	trans, _, _ := transliterateWordDetailed(ctx, word, opts)
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return trans, nil
+}
+
+// transliterateWordDetailed is TransliterateWordWithOptions, also returning
+// the syllables romanized to nothing and whether word was found in a
+// lookup table rather than left to the rules
+func transliterateWordDetailed(ctx context.Context, word string, opts Options) (string, []DroppedSpan, bool) {
+	trans, layer, ok := lookupWord(word, opts)
+	switch {
+	case ok && layer == LayerOverrides:
+		return opts.OutputForm.forced(trans), nil, true
+	case ok:
+		return opts.render(trans), nil, true
+	}
+	trans, dropped := opts.Engine.transliterate(ctx, word, opts.OnFailure, opts.Separator.joinPolicy(opts.Join))
+	return opts.render(trans), dropped, false
+}
+
+// render applies the output options, Scheme, ASCII or ToneStyle, each
+// under Toneless, then Separator and OutputForm, to the romanization of a
+// word. Schemes see the syllables as written, before Separator joins them,
+// and write their Boundary at the separators Separator leaves.
+func (opts Options) render(roman string) string {
+	scheme, ok := LookupScheme(opts.Scheme)
+	if !ok && opts.ASCII {
+		scheme, ok = asciiScheme, true
+	}
+	if ok {
+		scheme.Toneless = opts.Toneless
+		roman = scheme.render(roman)
+	} else {
+		roman = opts.ToneStyle.apply(roman, opts.Toneless)
+	}
+	roman = opts.Separator.Apply(roman)
+	roman = scheme.boundaries(roman)
+	return opts.OutputForm.Apply(roman)
+}
+
+// spellOutWord handles the spelling modes. Returns ("", false) when word
+// should go through regular transliteration.
+func spellOutWord(word string, opts Options) (string, bool) {
+	runes := []rune(word)
+	if opts.SpellOut && len(runes) == 1 {
+		return LetterName(runes[0])
+	}
+	if _, ok := LookupAcronym(word); ok && (opts.SpellOut || opts.DetectAcronyms) {
+		return readAcronym(word, opts.Acronyms), true
+	}
+	if opts.SpellOut && isConsonantOnly(word) {
+		return SpellOut(word), true
+	}
+	if opts.DetectAcronyms && isAcronymCandidate(word) {
+		return readAcronym(word, opts.Acronyms), true
+	}
+	return "", false
+}
+
+// isConsonantOnly reports whether word contains at least one consonant and
+// nothing but consonants and dots
+func isConsonantOnly(word string) bool {
+	hasConsonant := false
+	for _, r := range word {
+		if isConsonantRune(r) {
+			hasConsonant = true
+		} else if r != '.' {
+			return false
+		}
+	}
+	return hasConsonant
+}
@@ -0,0 +1,83 @@
+package paiboonizer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// RomanizationOracle proposes the romanization of a word the dictionary
+// doesn't know, for a human to review: an LLM, a human annotation service
+// or anything else behind a call. Proposals are suggestions and are never
+// used for transliteration directly.
+type RomanizationOracle interface {
+	Propose(ctx context.Context, word string) (string, error)
+}
+
+// OracleFunc adapts a function to the RomanizationOracle interface
+type OracleFunc func(ctx context.Context, word string) (string, error)
+
+// Propose calls f
+func (f OracleFunc) Propose(ctx context.Context, word string) (string, error) {
+	return f(ctx, word)
+}
+
+// RulesOracle proposes the rule engine's reading, an offline baseline
+var RulesOracle RomanizationOracle = OracleFunc(func(_ context.Context, word string) (string, error) {
+	return ComprehensiveTransliterate(word), nil
+})
+
+// CommandOracle runs an external program for each word, e.g. a script
+// calling an LLM: the word is written to its standard input followed by a
+// newline and the first non-empty line of its standard output is the
+// proposal.
+type CommandOracle struct {
+	Name string
+	Args []string
+}
+
+// Propose runs the command for word
+func (o CommandOracle) Propose(ctx context.Context, word string) (string, error) {
+	cmd := exec.CommandContext(ctx, o.Name, o.Args...)
+	cmd.Stdin = strings.NewReader(word + "\n")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("oracle %s: %w: %s", o.Name, err, strings.TrimSpace(stderr.String()))
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			return norm.NFC.String(line), nil
+		}
+	}
+	return "", fmt.Errorf("oracle %s: no proposal for %q", o.Name, word)
+}
+
+// ProposeRomanizations asks oracle about each word in turn and returns the
+// proposals by word. Words the oracle fails on are left out and their
+// errors joined into the returned error; a cancelled ctx stops early.
+func ProposeRomanizations(ctx context.Context, oracle RomanizationOracle, words []string) (map[string]string, error) {
+	proposals := make(map[string]string)
+	var errs []error
+	for _, word := range words {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		proposal, err := oracle.Propose(ctx, word)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		proposals[word] = proposal
+	}
+	return proposals, errors.Join(errs...)
+}
@@ -0,0 +1,66 @@
+package paiboonizer
+
+import (
+	"golang.org/x/text/unicode/norm"
+)
+
+// OutputForm is the Unicode form of the tone marks in the output. Paiboon
+// writes some toned vowels precomposed (à, ǎ) and others with combining
+// marks (ɛ̂, ʉ̌), and the dictionaries and rules don't agree on which, so
+// consumers comparing or indexing output should pick a form.
+type OutputForm int
+
+const (
+	// OutputAsWritten keeps the form of the dictionary entry or rule that
+	// wrote each word, mixed. The default.
+	OutputAsWritten OutputForm = iota
+	OutputNFC                  // Romanized words in NFC: precomposed wherever possible
+	OutputNFD                  // Romanized words in NFD: every tone mark combining
+	// OutputForceCombining is OutputNFD applied to everything written,
+	// overrides and text passed through (foreign words, punctuation)
+	// included
+	OutputForceCombining
+	// OutputForcePrecomposed is OutputNFC applied to everything written,
+	// overrides and text passed through included
+	OutputForcePrecomposed
+)
+
+// String returns the name of the form
+func (f OutputForm) String() string {
+	switch f {
+	case OutputAsWritten:
+		return "as-written"
+	case OutputNFC:
+		return "nfc"
+	case OutputNFD:
+		return "nfd"
+	case OutputForceCombining:
+		return "force-combining"
+	case OutputForcePrecomposed:
+		return "force-precomposed"
+	}
+	return "unknown"
+}
+
+// Apply converts s to the form f. It converts the output of functions
+// that take no Options, such as TransliterateWord and
+// ComprehensiveTransliterate; the others apply Options.OutputForm
+// themselves.
+func (f OutputForm) Apply(s string) string {
+	switch f {
+	case OutputNFC, OutputForcePrecomposed:
+		return norm.NFC.String(s)
+	case OutputNFD, OutputForceCombining:
+		return norm.NFD.String(s)
+	}
+	return s
+}
+
+// forced converts text written as is, such as overrides and non-Thai
+// runs, under the Force forms only
+func (f OutputForm) forced(s string) string {
+	if f == OutputForceCombining || f == OutputForcePrecomposed {
+		return f.Apply(s)
+	}
+	return s
+}
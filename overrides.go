@@ -0,0 +1,224 @@
+package paiboonizer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Overrides fixes the segmentation and romanization of text a project
+// keeps seeing (character names, catchphrases), keyed by exact text. The
+// zero value overrides nothing. See ReadOverrides for the file format.
+type Overrides struct {
+	lines map[string]string
+	words map[string]wordOverride
+	// longest is the rune length of the longest word key, the lookahead
+	// of the segmenter
+	longest int
+}
+
+// wordOverride is the fixed reading of a word key: its words and their
+// romanizations, empty where the pipeline romanizes them
+type wordOverride struct {
+	parts  []string
+	romans []string
+}
+
+// ReadOverrides reads an overrides file: UTF-8 text, one entry per line,
+// the Thai and its romanization separated by a tab. Blank lines and lines
+// starting with # are ignored.
+//
+//	สมชาย	sǒm-chaai
+//	เจ้าแม่|ตะเคียน	jâo-mɛ̂ɛ dtà~kiian
+//	ตะเคียนทอง|คลินิก
+//	เอาล่ะ ไปกันเลย	ao-là bpai gan ləəi
+//
+// A key without whitespace is a word, found wherever it occurs in a Thai
+// run. | splits it into several words, romanized by the space-separated
+// romanizations of the second column in order, or by the pipeline when
+// the column is left out, so that an entry may fix only the segmentation.
+// A key with whitespace is a line: it applies when the whole text given
+// to TransliterateText is that line, surrounding whitespace aside, and its
+// romanization is required. Keys and romanizations are brought to NFC.
+func ReadOverrides(r io.Reader) (Overrides, error) {
+	o := Overrides{lines: make(map[string]string), words: make(map[string]wordOverride)}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRightFunc(norm.NFC.String(scanner.Text()), unicode.IsSpace)
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, roman, _ := strings.Cut(line, "\t")
+		key, roman = strings.TrimSpace(key), strings.TrimSpace(roman)
+		if !containsThai(key) {
+			return Overrides{}, fmt.Errorf("line %d: no Thai in %q", n, key)
+		}
+		if strings.ContainsFunc(key, unicode.IsSpace) {
+			if roman == "" {
+				return Overrides{}, fmt.Errorf("line %d: line %q has no romanization", n, key)
+			}
+			o.lines[key] = roman
+			continue
+		}
+		w := wordOverride{parts: strings.Split(key, "|")}
+		if roman != "" {
+			w.romans = strings.Fields(roman)
+			if len(w.romans) != len(w.parts) {
+				return Overrides{}, fmt.Errorf("line %d: %d words but %d romanizations", n, len(w.parts), len(w.romans))
+			}
+		}
+		for _, part := range w.parts {
+			if part == "" {
+				return Overrides{}, fmt.Errorf("line %d: empty word in %q", n, key)
+			}
+		}
+		word := strings.Join(w.parts, "")
+		o.words[word] = w
+		o.longest = max(o.longest, utf8.RuneCountInString(word))
+	}
+	return o, scanner.Err()
+}
+
+// LoadOverrides reads the overrides file at path (see ReadOverrides)
+func LoadOverrides(path string) (Overrides, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Overrides{}, err
+	}
+	defer f.Close()
+	o, err := ReadOverrides(f)
+	if err != nil {
+		return Overrides{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return o, nil
+}
+
+// Set returns a copy of o with the entry of key, written as in an
+// overrides file (see ReadOverrides), set to roman
+func (o Overrides) Set(key, roman string) (Overrides, error) {
+	if strings.ContainsAny(key, "\n\t") || strings.Contains(roman, "\n") {
+		return o, fmt.Errorf("entry %q: not a single line", key)
+	}
+	entry, err := ReadOverrides(strings.NewReader(key + "\t" + roman))
+	if err != nil {
+		return o, err
+	}
+	if entry.Len() == 0 {
+		return o, fmt.Errorf("entry %q: empty", key)
+	}
+	c := o.clone()
+	for k, v := range entry.lines {
+		c.lines[k] = v
+	}
+	for k, v := range entry.words {
+		c.words[k] = v
+		c.longest = max(c.longest, entry.longest)
+	}
+	return c, nil
+}
+
+// Remove returns a copy of o without the entry of key, written as in an
+// overrides file, and false if o has no such entry
+func (o Overrides) Remove(key string) (Overrides, bool) {
+	key = strings.TrimSpace(norm.NFC.String(key))
+	if _, ok := o.lines[key]; ok {
+		c := o.clone()
+		delete(c.lines, key)
+		return c, true
+	}
+	word := strings.ReplaceAll(key, "|", "")
+	if _, ok := o.words[word]; !ok {
+		return o, false
+	}
+	c := o.clone()
+	delete(c.words, word)
+	c.longest = 0
+	for w := range c.words {
+		c.longest = max(c.longest, utf8.RuneCountInString(w))
+	}
+	return c, true
+}
+
+// clone returns a copy of o that can be changed without changing o
+func (o Overrides) clone() Overrides {
+	c := Overrides{lines: make(map[string]string, len(o.lines)), words: make(map[string]wordOverride, len(o.words)), longest: o.longest}
+	for k, v := range o.lines {
+		c.lines[k] = v
+	}
+	for k, v := range o.words {
+		c.words[k] = v
+	}
+	return c
+}
+
+// WriteOverrides writes o to w in the format of ReadOverrides, one entry
+// per line sorted by key, words before lines. Comments of the file o was
+// read from aren't kept.
+func WriteOverrides(w io.Writer, o Overrides) error {
+	var entries []string
+	for _, e := range o.words {
+		entry := strings.Join(e.parts, "|")
+		if len(e.romans) > 0 {
+			entry += "\t" + strings.Join(e.romans, " ")
+		}
+		entries = append(entries, entry)
+	}
+	sort.Strings(entries)
+	lines := make([]string, 0, len(o.lines))
+	for k, v := range o.lines {
+		lines = append(lines, k+"\t"+v)
+	}
+	sort.Strings(lines)
+	bw := bufio.NewWriter(w)
+	for _, entry := range append(entries, lines...) {
+		bw.WriteString(entry + "\n")
+	}
+	return bw.Flush()
+}
+
+// Len returns the number of entries, lines and words
+func (o Overrides) Len() int {
+	return len(o.lines) + len(o.words)
+}
+
+// line returns the romanization of a line entry matching text
+func (o Overrides) line(text string) (string, bool) {
+	roman, ok := o.lines[strings.TrimSpace(text)]
+	return roman, ok
+}
+
+// word returns the romanization of a single-word entry
+func (o Overrides) word(word string) (string, bool) {
+	w, ok := o.words[word]
+	if !ok || len(w.parts) != 1 || len(w.romans) == 0 {
+		return "", false
+	}
+	return w.romans[0], true
+}
+
+// tokensAt returns the tokens of the longest word entry starting at
+// runes[start] and its rune length, 0 if none starts there
+func (o Overrides) tokensAt(runes []rune, start int) ([]textToken, int) {
+	for length := min(len(runes)-start, o.longest); length > 0; length-- {
+		w, ok := o.words[string(runes[start:start+length])]
+		if !ok {
+			continue
+		}
+		tokens := make([]textToken, len(w.parts))
+		for i, part := range w.parts {
+			tokens[i] = textToken{text: part, kind: tokenThai}
+			if len(w.romans) > 0 {
+				tokens[i].roman = w.romans[i]
+			}
+		}
+		return tokens, length
+	}
+	return nil, 0
+}
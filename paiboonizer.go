@@ -2,7 +2,7 @@ package paiboonizer
 
 import (
 	"context"
-	"embed"
+	"errors"
 	//"flag"
 	"fmt"
 	"html"
@@ -11,9 +11,12 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/gookit/color"
+	"github.com/tassa-yoniso-manasi-karoto/paiboonizer/thai"
 	//"github.com/k0kubun/pp"
 	"github.com/tassa-yoniso-manasi-karoto/go-pythainlp"
 	"golang.org/x/text/runes"
@@ -22,27 +25,65 @@ import (
 	"github.com/rivo/uniseg"
 )
 
-//go:embed csv/*.txt
-var vocabFS embed.FS
-
-//go:embed opus_dictionary.tsv
-var opusDictFS embed.FS
-
 // Global dictionary built from manual vocab
-var dictionary = make(map[string]string)
+var dictionary = make(dictTable)
 var syllableDict = make(map[string]string)
 
+// Part of speech column of the vocab files (n, vt, adj, part, ...)
+var partOfSpeech = make(map[string]string)
+
+// English column of the vocab files, first one given for each word
+var vocabGlosses = make(map[string]string)
+
 // Opus dictionary - LLM-generated, lower priority than official dictionary
-var opusDictionary = make(map[string]string)
+var opusDictionary = make(dictTable)
+
+// Lazy initialization - dictionary is only loaded when first needed.
+// The tables above are only written inside dictionaryOnce and only read
+// after it, so lookups are safe from any number of goroutines.
+var (
+	dictionaryOnce sync.Once
+	dictionaryErr  error
+)
 
-// Lazy initialization - dictionary is only loaded when first needed
-var dictionaryOnce sync.Once
+// Load builds the dictionaries from the embedded vocab files. Every
+// function loads them on first use, so calling Load is optional: it moves
+// the cost (a few hundred milliseconds) to startup and reports a broken
+// build instead of panicking later. Safe to call concurrently and more than
+// once; the tables are built once and later calls return the first result.
+// Loading writes nothing; see DictionaryStats for the table sizes.
+func Load() error {
+	dictionaryOnce.Do(func() {
+		dictionaryErr = loadDictionary()
+	})
+	return dictionaryErr
+}
+
+// MustLoad is Load, panicking if the embedded dictionaries can't be read
+func MustLoad() {
+	if err := Load(); err != nil {
+		panic(err)
+	}
+}
 
 // ensureDictionaryLoaded loads the dictionary on first call (lazy initialization).
 // This prevents the dictionary from being loaded when paiboonizer is imported
 // but not actually used (e.g., when using translitkit for other languages).
 func ensureDictionaryLoaded() {
-	dictionaryOnce.Do(loadDictionary)
+	MustLoad()
+}
+
+// DictionaryStats holds the sizes of the lookup tables
+type DictionaryStats struct {
+	Entries     int // Official dictionary entries
+	Syllables   int // Syllable dictionary entries
+	OpusEntries int // Opus dictionary entries
+}
+
+// Stats returns the sizes of the lookup tables, loading them if needed
+func Stats() DictionaryStats {
+	ensureDictionaryLoaded()
+	return DictionaryStats{len(dictionary), len(syllableDict), len(opusDictionary)}
 }
 
 // specialCasesGlobal contains special transliterations for irregular words
@@ -549,44 +590,36 @@ var specialCasesGlobal = map[string]string{
 	"กะเหรี่ยง": "gà~rìiang", "เหรี่ยง": "rìiang",
 }
 
-// Consonant mappings
-var initialConsonants = map[string]string{
-	"ก": "g", "ข": "k", "ฃ": "k", "ค": "k", "ฅ": "k", "ฆ": "k", "ง": "ng",
-	"จ": "j", "ฉ": "ch", "ช": "ch", "ซ": "s", "ฌ": "ch", "ญ": "y", "ฎ": "d",
-	"ฏ": "dt", "ฐ": "t", "ฑ": "t", "ฒ": "t", "ณ": "n", "ด": "d", "ต": "dt",
-	"ถ": "t", "ท": "t", "ธ": "t", "น": "n", "บ": "b", "ป": "bp", "ผ": "p",
-	"ฝ": "f", "พ": "p", "ฟ": "f", "ภ": "p", "ม": "m", "ย": "y", "ร": "r",
-	"ฤ": "rʉ", "ล": "l", "ฦ": "lʉ", "ว": "w", "ศ": "s", "ษ": "s", "ส": "s",
-	"ห": "h", "ฬ": "l", "อ": "", "ฮ": "h",
-}
+// Consonant mappings, keyed by letter (see thai.InitialSounds and
+// thai.FinalSounds)
+var initialConsonants = byLetter(thai.InitialSounds())
 
-var finalConsonants = map[string]string{
-	"ก": "k", "ข": "k", "ฃ": "k", "ค": "k", "ฅ": "k", "ฆ": "k", "ง": "ng",
-	"จ": "t", "ฉ": "t", "ช": "t", "ซ": "t", "ฌ": "t", "ญ": "n", "ฎ": "t",
-	"ฏ": "t", "ฐ": "t", "ฑ": "t", "ฒ": "t", "ณ": "n", "ด": "t", "ต": "t",
-	"ถ": "t", "ท": "t", "ธ": "t", "น": "n", "บ": "p", "ป": "p", "ผ": "p",
-	"ฝ": "p", "พ": "p", "ฟ": "p", "ภ": "p", "ม": "m", "ย": "i", "ร": "n",
-	"ล": "n", "ว": "o", "ศ": "t", "ษ": "t", "ส": "t", "ห": "", "ฬ": "n",
-	"อ": "", "ฮ": "",
-}
+var finalConsonants = byLetter(thai.FinalSounds())
 
-// Tone classes
+// Tone classes (see thai.ClassOf)
 var (
-	highClass = map[string]bool{
-		"ข": true, "ฃ": true, "ฉ": true, "ฐ": true, "ถ": true,
-		"ผ": true, "ฝ": true, "ศ": true, "ษ": true, "ส": true, "ห": true,
-	}
-	midClass = map[string]bool{
-		"ก": true, "จ": true, "ฎ": true, "ฏ": true,
-		"ด": true, "ต": true, "บ": true, "ป": true, "อ": true,
+	highClass = classSet(thai.ClassHigh)
+	midClass  = classSet(thai.ClassMid)
+	lowClass  = classSet(thai.ClassLow)
+)
+
+// byLetter rekeys a table of the thai package by string
+func byLetter(m map[rune]string) map[string]string {
+	table := make(map[string]string, len(m))
+	for r, s := range m {
+		table[string(r)] = s
 	}
-	lowClass = map[string]bool{
-		"ค": true, "ฅ": true, "ฆ": true, "ง": true, "ช": true, "ซ": true,
-		"ฌ": true, "ญ": true, "ฑ": true, "ฒ": true, "ณ": true, "ท": true,
-		"ธ": true, "น": true, "พ": true, "ฟ": true, "ภ": true, "ม": true,
-		"ย": true, "ร": true, "ล": true, "ว": true, "ฬ": true, "ฮ": true,
+	return table
+}
+
+// classSet returns the consonants of class as a set
+func classSet(class thai.Class) map[string]bool {
+	set := make(map[string]bool)
+	for _, r := range thai.ConsonantsOfClass(class) {
+		set[string(r)] = true
 	}
-)
+	return set
+}
 
 // Common clusters
 var clusters = map[string]string{
@@ -615,33 +648,100 @@ var clusters = map[string]string{
 	"บร": "br", "บล": "bl",
 }
 
-// clusterToneClass maps clusters to their effective tone class for tone calculation
-// ห-leading clusters use high class for tone rules
-var clusterToneClass = map[string]string{
-	"หร": "high", "หล": "high", "หม": "high", "หน": "high", "หว": "high", "หย": "high", "หง": "high",
-}
-
 // Manager handles PyThaiNLP integration for paiboonizer
 type Manager struct {
-	nlpManager *pythainlp.PyThaiNLPManager
+	nlpManager    *pythainlp.PyThaiNLPManager
+	fallbackAfter time.Duration
+	metrics       Metrics
+	breaker       *breaker
+	pool          *pool
+	poolSize      int
+	onFailure     FailurePolicy
+	join          JoinPolicy
+	sep           Separator
+	layers        []LookupLayer
+}
+
+// ManagerOption configures a Manager
+type ManagerOption func(*Manager)
+
+// FallbackAfter bounds every pythainlp call made by the Manager to d. When
+// pythainlp doesn't answer in time the call is served by internal
+// segmentation (see TransliterateText) and MetricPythainlpDeadline is
+// incremented, instead of blocking the pipeline. Zero disables the deadline.
+func FallbackAfter(d time.Duration) ManagerOption {
+	return func(m *Manager) {
+		m.fallbackAfter = d
+	}
+}
+
+// WithOnFailure sets what ThaiToRoman writes in place of Thai the rules
+// can't romanize; the default is FailurePassThrough
+func WithOnFailure(policy FailurePolicy) ManagerOption {
+	return func(m *Manager) {
+		m.onFailure = policy
+	}
+}
+
+// WithJoinPolicy sets how ThaiToRoman joins the syllables of words left to
+// the rules; the default is JoinDictionary
+func WithJoinPolicy(policy JoinPolicy) ManagerOption {
+	return func(m *Manager) {
+		m.join = policy
+	}
+}
+
+// WithSeparatorPolicy sets how ThaiToRoman separates syllables and words,
+// dictionary entries included (see Separator); Join is then ignored
+func WithSeparatorPolicy(sep Separator) ManagerOption {
+	return func(m *Manager) {
+		m.sep = sep
+	}
 }
 
 var dictionaryLoaded = false
 var globalManager *Manager
 
 // NewManager creates a new paiboonizer manager
-func NewManager(ctx context.Context) (*Manager, error) {
-	return NewManagerWithRecreate(ctx, false)
+func NewManager(ctx context.Context, opts ...ManagerOption) (*Manager, error) {
+	return NewManagerWithRecreate(ctx, false, opts...)
 }
 
 // NewManagerWithRecreate creates a new paiboonizer manager.
 // If recreate is true, tears down existing container before creating a new one.
 // This is needed because each NewManager() allocates a new random port, but if
 // an existing container wasn't properly removed, it has a stale port mapping.
-func NewManagerWithRecreate(ctx context.Context, recreate bool) (*Manager, error) {
-	m := &Manager{}
-	var err error
-	m.nlpManager, err = pythainlp.NewManager(ctx)
+func NewManagerWithRecreate(ctx context.Context, recreate bool, opts ...ManagerOption) (*Manager, error) {
+	m := &Manager{metrics: noopMetrics{}, poolSize: 1}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.breaker != nil {
+		m.breaker.metrics = m.metrics
+		m.breaker.setState(BreakerClosed)
+	}
+	m.pool = &pool{metrics: m.metrics}
+	for i := 0; i < m.poolSize; i++ {
+		var nlpOpts []pythainlp.ManagerOption
+		if m.poolSize > 1 {
+			nlpOpts = append(nlpOpts, pythainlp.WithProjectName(poolProjectName(i)))
+		}
+		nlp, err := startPythainlp(ctx, recreate, nlpOpts...)
+		if err != nil {
+			m.pool.close()
+			return nil, err
+		}
+		m.pool.members = append(m.pool.members, &poolMember{nlp: nlp})
+	}
+	m.nlpManager = m.pool.members[0].nlp
+	m.metrics.SetGauge(MetricPoolHealthy, float64(m.poolSize))
+
+	return m, nil
+}
+
+// startPythainlp creates and initializes a single pythainlp manager
+func startPythainlp(ctx context.Context, recreate bool, opts ...pythainlp.ManagerOption) (*pythainlp.PyThaiNLPManager, error) {
+	nlp, err := pythainlp.NewManager(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize pythainlp: %w", err)
 	}
@@ -649,20 +749,23 @@ func NewManagerWithRecreate(ctx context.Context, recreate bool) (*Manager, error
 	// Initialize the service
 	if recreate {
 		// Recreate container to ensure port mapping matches
-		if err := m.nlpManager.InitRecreate(ctx, false); err != nil {
+		if err := nlp.InitRecreate(ctx, false); err != nil {
 			return nil, fmt.Errorf("failed to start pythainlp service: %w", err)
 		}
 	} else {
-		if err := m.nlpManager.Init(ctx); err != nil {
+		if err := nlp.Init(ctx); err != nil {
 			return nil, fmt.Errorf("failed to start pythainlp service: %w", err)
 		}
 	}
 
-	return m, nil
+	return nlp, nil
 }
 
 // Close releases resources
 func (m *Manager) Close() error {
+	if m.pool != nil {
+		return m.pool.close()
+	}
 	if m.nlpManager != nil {
 		return m.nlpManager.Close()
 	}
@@ -671,9 +774,26 @@ func (m *Manager) Close() error {
 
 // ThaiToRoman is the main transliteration function using go-pythainlp
 func (m *Manager) ThaiToRoman(ctx context.Context, text string) (string, error) {
-	// First, try direct dictionary lookup for the whole text
-	if trans, ok := dictionary[text]; ok {
-		return trans, nil
+	res, err := m.thaiToRoman(ctx, text)
+	return res.Roman, err
+}
+
+// thaiToRoman is ThaiToRoman, also returning the Thai romanized to nothing
+// and warnings (see TextResult). Each dropped span is counted in
+// MetricDroppedSpans.
+func (m *Manager) thaiToRoman(ctx context.Context, text string) (res TextResult, err error) {
+	defer func() {
+		for range res.Dropped {
+			m.metrics.IncCounter(MetricDroppedSpans)
+		}
+	}()
+	// First, try the lookup layers for the whole text, which also passes
+	// text romanized by an earlier pass of the pipeline through as is
+	if trans, layer, ok := lookupWord(text, Options{Layers: m.lookupLayers()}); ok {
+		if layer == layerSpelling {
+			return TextResult{Roman: trans}, nil
+		}
+		return TextResult{Roman: m.sep.Apply(trans)}, nil
 	}
 	
 	// Tokenize using pythainlp
@@ -683,62 +803,158 @@ func (m *Manager) ThaiToRoman(ctx context.Context, text string) (string, error)
 		SyllableEngine: "han_solo",
 	}
 	
-	result, err := m.nlpManager.AnalyzeWithOptions(ctx, text, opts)
-	if err != nil {
-		return "", fmt.Errorf("tokenization failed: %w", err)
+	// Serve rules while the circuit is open
+	if !m.breaker.allow() {
+		return m.fallbackTransliteration(text, "circuit breaker open"), nil
+	}
+	// Long texts are sent in pieces, within the pythainlp request limits
+	var tokens, syllables []string
+	for _, chunk := range pythainlpChunks(text) {
+		member, err := m.acquire()
+		if err != nil {
+			return TextResult{}, fmt.Errorf("tokenization failed: %w", err)
+		}
+		callCtx, cancel := m.callContext(ctx)
+		result, err := member.nlp.AnalyzeWithOptions(callCtx, chunk, opts)
+		cancel()
+		m.release(ctx, member, err)
+		if err != nil {
+			if m.missedDeadline(ctx, err) || (m.breaker != nil && ctx.Err() == nil) {
+				return m.fallbackTransliteration(text, err.Error()), nil
+			}
+			return TextResult{}, fmt.Errorf("tokenization failed: %w", err)
+		}
+		tokens = append(tokens, result.RawTokens...)
+		syllables = append(syllables, result.Syllables...)
 	}
 	
 	// Process word by word
+	res.Warnings = suspiciousInput(text)
 	results := []string{}
-	for _, word := range result.RawTokens {
+	offset := 0
+	for _, word := range tokens {
+		start := offset
+		offset += utf8.RuneCountInString(word)
 		// Skip empty tokens and spaces
 		if word == "" || word == " " {
 			continue
 		}
 		
-		// Try dictionary lookup first
-		if trans, ok := dictionary[word]; ok {
-			results = append(results, trans)
+		// Try the lookup layers first
+		if trans, _, ok := lookupWord(word, Options{Layers: m.lookupLayers()}); ok {
+			results = append(results, m.sep.Apply(trans))
 			continue
 		}
 		
 		// Fall back to syllable-by-syllable transliteration
-		wordResult := TransliterateWordWithSyllables(word, result.Syllables)
+		wordResult, wordDropped := transliterateWordWithSyllables(word, syllables, m.onFailure, m.sep.joinPolicy(m.join))
+		wordResult = m.sep.Apply(wordResult)
+		res.Dropped = append(res.Dropped, shiftDroppedSpans(wordDropped, start)...)
+		res.Warnings = append(res.Warnings, Warning{WarnUnknownWord, word, start, "romanized by the rules"})
 		if wordResult != "" {
 			results = append(results, wordResult)
 		}
 	}
 	
+	res.Warnings = append(res.Warnings, droppedWarnings(res.Dropped)...)
+	
 	// Join with hyphen for compound words, but merge syllables within words
-	if len(results) > 1 {
-		// Check if the original text has spaces (multi-word phrase)
-		if strings.Contains(text, " ") {
-			return strings.Join(results, " "), nil
-		}
+	switch {
+	case len(results) > 1 && strings.Contains(text, " "):
+		// Multi-word phrase
+		res.Roman = strings.Join(results, m.sep.between(" "))
+	case len(results) > 1:
 		// Otherwise it's a compound word, join with hyphens
-		return strings.Join(results, "-"), nil
+		res.Roman = strings.Join(results, m.compoundSeparator())
+	default:
+		res.Roman = strings.Join(results, "")
 	}
-	
-	return strings.Join(results, ""), nil
+	return res, nil
 }
 
-// fallbackTransliteration when pythainlp is not available
-func fallbackTransliteration(text string) string {
-	// First, try direct dictionary lookup
-	if trans, ok := dictionary[text]; ok {
-		return trans
+// compoundSeparator returns what ThaiToRoman writes between the words of a
+// compound: a hyphen, the mark of the Separator otherwise
+func (m *Manager) compoundSeparator() string {
+	if m.sep.Scope == ScopeWord {
+		return m.sep.Mark.text("-")
 	}
-	
-	// Fall back to simple transliteration
-	return TransliterateWord(text)
+	return m.sep.Apply("-")
+}
+
+// callContext derives the context for a single pythainlp call, bounded by
+// the FallbackAfter deadline when one is set
+func (m *Manager) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if m.fallbackAfter <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, m.fallbackAfter)
+}
+
+// missedDeadline reports whether err comes from the FallbackAfter deadline
+// rather than from the caller's own context, and records the event
+func (m *Manager) missedDeadline(parent context.Context, err error) bool {
+	if m.fallbackAfter <= 0 || parent.Err() != nil || !errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	m.metrics.IncCounter(MetricPythainlpDeadline)
+	return true
+}
+
+// syllableTokenize splits word into syllables with pythainlp, honoring the
+// FallbackAfter deadline and the circuit breaker. Callers fall back to rules
+// on error.
+func (m *Manager) syllableTokenize(ctx context.Context, word string) ([]string, error) {
+	if !m.breaker.allow() {
+		return nil, errCircuitOpen
+	}
+	member, err := m.acquire()
+	if err != nil {
+		return nil, err
+	}
+	callCtx, cancel := m.callContext(ctx)
+	defer cancel()
+	result, err := member.nlp.SyllableTokenize(callCtx, word)
+	m.release(ctx, member, err)
+	if err != nil {
+		m.missedDeadline(ctx, err)
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result.Syllables, nil
+}
+
+// fallbackTransliteration when pythainlp is not available, for the given
+// reason reported as a WarnPythainlpFallback warning. The whole text was
+// looked up already by thaiToRoman.
+func (m *Manager) fallbackTransliteration(text string, reason string) TextResult {
+	fallback := Warning{Kind: WarnPythainlpFallback, Message: reason}
+	// Fall back to internal segmentation
+	opts := DefaultOptions()
+	opts.OnFailure = m.onFailure
+	opts.Join = m.join
+	opts.Separator = m.sep
+	opts.Layers = m.layers
+	res := TransliterateTextDetailed(text, opts)
+	res.Warnings = append([]Warning{fallback}, res.Warnings...)
+	return res
 }
 
 // TransliterateWordWithSyllables handles a word with known syllables from pythainlp
 func TransliterateWordWithSyllables(word string, allSyllables []string) string {
+	trans, _ := transliterateWordWithSyllables(word, allSyllables, FailureDrop, JoinDictionary)
+	return trans
+}
+
+// transliterateWordWithSyllables is TransliterateWordWithSyllables, also
+// returning the syllables romanized to nothing, replaced as onFailure says,
+// and joining the syllables as join says
+func transliterateWordWithSyllables(word string, allSyllables []string, onFailure FailurePolicy, join JoinPolicy) (string, []DroppedSpan) {
 	ensureDictionaryLoaded()
 	// Try dictionary first
-	if trans, ok := dictionary[word]; ok {
-		return trans
+	if trans, ok := dictionary.roman(word); ok {
+		return trans, nil
 	}
 	
 	// Find syllables that belong to this word
@@ -772,26 +988,7 @@ func TransliterateWordWithSyllables(word string, allSyllables []string) string {
 		wordSyllables = ExtractSyllables(word)
 	}
 	
-	// Transliterate each syllable
-	results := []string{}
-	for _, syl := range wordSyllables {
-		// Try syllable dictionary
-		if trans, ok := syllableDict[syl]; ok {
-			results = append(results, trans)
-			continue
-		}
-		
-		// Fall back to rule-based transliteration
-		trans := transliterateSyllable(syl)
-		if trans != "" {
-			results = append(results, trans)
-		}
-	}
-	
-	if len(results) == 0 {
-		return ""
-	}
-	return strings.Join(results, "")
+	return romanizeSyllables(wordSyllables, join, onFailure, lookupOrRuleSyllable)
 }
 
 // TransliterateWord handles a single Thai word without known syllables
@@ -803,16 +1000,25 @@ func TransliterateWordWithSyllables(word string, allSyllables []string) string {
 func LookupDictionary(word string) (string, bool) {
 	ensureDictionaryLoaded()
 	// Check official dictionary first (highest authority)
-	if trans, ok := dictionary[word]; ok {
+	if trans, ok := dictionary.roman(word); ok {
 		return trans, true
 	}
 	// Fall back to Opus dictionary (LLM-generated, lower authority)
-	if trans, ok := opusDictionary[word]; ok {
+	if trans, ok := opusDictionary.roman(word); ok {
 		return trans, true
 	}
 	return "", false
 }
 
+// PartOfSpeech returns the part of speech the vocab files give for word
+// (n, v, vt, vi, adj, adv, part, conj, prep, pron, numb, sent, ...).
+// Returns ("", false) for words without one.
+func PartOfSpeech(word string) (string, bool) {
+	ensureDictionaryLoaded()
+	pos, ok := partOfSpeech[word]
+	return pos, ok
+}
+
 // LookupSyllable checks if a syllable exists in the syllable dictionary.
 // Returns (transliteration, true) if found, ("", false) otherwise.
 func LookupSyllable(syllable string) (string, bool) {
@@ -833,72 +1039,82 @@ func LookupSpecialCase(text string) (string, bool) {
 // TransliterateWord transliterates a single Thai word to Paiboon romanization
 func TransliterateWord(word string) string {
 	ensureDictionaryLoaded()
+	if LooksRomanized(word) {
+		return word
+	}
 	// Try dictionary first
-	if trans, ok := dictionary[word]; ok {
+	if trans, ok := dictionary.roman(word); ok {
 		return trans
 	}
 	
 	// Get syllables using simple extraction
-	syllables := ExtractSyllables(word)
-	
-	results := []string{}
-	for _, syl := range syllables {
-		// Try syllable dictionary
-		if trans, ok := syllableDict[syl]; ok {
-			results = append(results, trans)
-			continue
-		}
-		
-		// Fall back to rule-based transliteration
-		trans := transliterateSyllable(syl)
-		if trans != "" {
-			results = append(results, trans)
-		}
-	}
-	
-	if len(results) == 0 {
-		return ""
-	}
-	return strings.Join(results, "")
+	trans, _ := romanizeSyllables(ExtractSyllables(word), JoinDictionary, FailureDrop, lookupOrRuleSyllable)
+	return trans
 }
 
 // TransliterateWordRulesOnly transliterates Thai words using dictionary lookup
 // followed by rule-based transliteration with syllable tokenization support.
 // This is the main public API for transliteration.
 func TransliterateWordRulesOnly(word string) string {
-	ensureDictionaryLoaded()
-	// Try dictionary lookup first
-	if trans, ok := dictionary[word]; ok {
-		return norm.NFC.String(trans)
+	trans, _ := transliterateWordRulesOnly(context.Background(), word, FailureDrop, JoinDictionary)
+	return trans
+}
+
+// TransliterateWordRulesOnlyContext is TransliterateWordRulesOnly with a
+// context bounding the pythainlp call. Returns the context's error if it
+// ends before the word is romanized.
+func TransliterateWordRulesOnlyContext(ctx context.Context, word string) (string, error) {
+	trans, _ := transliterateWordRulesOnly(ctx, word, FailureDrop, JoinDictionary)
+	if err := ctx.Err(); err != nil {
+		return "", err
 	}
-	
+	return trans, nil
+}
+
+// transliterateWordRulesOnly is TransliterateWordRulesOnly, also returning
+// the syllables romanized to nothing (see comprehensiveTransliterate)
+func transliterateWordRulesOnly(ctx context.Context, word string, onFailure FailurePolicy, join JoinPolicy) (string, []DroppedSpan) {
+	// Text romanized already is passed through by the lookup
+	if trans, _, ok := lookupWord(word, Options{Layers: rulesOnlyLayers}); ok {
+		return trans, nil
+	}
+	return autoEngineTransliterate(ctx, word, onFailure, join)
+}
+
+// autoEngineTransliterate romanizes word with pythainlp syllables when
+// the default Manager has pythainlp running, with the comprehensive rules
+// otherwise (see EngineAuto). A pythainlp call cut short by ctx falls back
+// to the rules too; callers check ctx afterwards.
+func autoEngineTransliterate(ctx context.Context, word string, onFailure FailurePolicy, join JoinPolicy) (string, []DroppedSpan) {
 	// Try syllable tokenization if pythainlp is available
-	if globalManager != nil && globalManager.nlpManager != nil {
-		ctx := context.Background()
-		result, err := globalManager.nlpManager.SyllableTokenize(ctx, word)
-		if err == nil && result != nil && len(result.Syllables) > 0 {
+	if globalManager != nil && globalManager.nlpManager != nil && ctx.Err() == nil {
+		syllables, err := globalManager.syllableTokenize(ctx, word)
+		if err == nil && len(syllables) > 0 {
 			// Multi-syllable word - transliterate each syllable
-			results := []string{}
-			for _, syllable := range result.Syllables {
-				trans := ComprehensiveTransliterate(syllable)
-				if trans != "" {
-					results = append(results, trans)
-				}
-			}
-			if len(results) > 0 {
-				return strings.Join(results, "-")
+			trans, dropped := romanizeSyllables(syllables, join, onFailure, func(syl string) (string, []DroppedSpan) {
+				return comprehensiveTransliterate(syl, onFailure, join)
+			})
+			if trans != "" {
+				return trans, dropped
 			}
 		}
 	}
 	
 	// Fall back to comprehensive transliteration
-	return ComprehensiveTransliterate(word)
+	return comprehensiveTransliterate(word, onFailure, join)
 }
 
 // ExtractSyllables breaks a Thai word into individual syllables using
 // rule-based syllable boundary detection. It handles leading vowels,
-// consonant clusters, and complex vowel patterns.
+// consonant clusters, and complex vowel patterns. A word cut into pieces
+// that can't be syllables is segmented again (see repairSyllables).
 func ExtractSyllables(word string) []string {
+	return repairSyllables(word, extractSyllablesRaw(word))
+}
+
+// extractSyllablesRaw is ExtractSyllables without the repair, whose
+// boundaries the syllable dictionary was built with
+func extractSyllablesRaw(word string) []string {
 	syllables := []string{}
 	runes := []rune(word)
 	i := 0
@@ -961,7 +1177,7 @@ func findSyllableEndImproved(runes []rune, start int) int {
 	// 3. Get vowels and tone marks
 	for i < len(runes) {
 		r := string(runes[i])
-		if isVowel(r) {
+		if isVowel(r) && !isLeadingVowel(r) {
 			hasVowel = true
 			i++
 		} else if isToneMark(r) || r == "็" || r == "์" || r == "ํ" || r == "ๆ" {
@@ -971,7 +1187,18 @@ func findSyllableEndImproved(runes []rune, start int) int {
 		}
 	}
 	
-	// 4. Check for final consonant
+	// 4. Check for final consonant. ไ- and ใ- syllables have none but the
+	// silent ย of ไ-ย (ไชยชนะ), and a syllable ending in ะ has none.
+	if hasLeadingVowel && (runes[start] == 'ไ' || runes[start] == 'ใ') {
+		if i < len(runes) && runes[i] == 'ย' && i > consonantStart &&
+			(i+1 == len(runes) || isConsonantRune(runes[i+1]) || isLeadingVowel(string(runes[i+1]))) {
+			i++
+		}
+		return i
+	}
+	if i > start && runes[i-1] == 'ะ' {
+		return i
+	}
 	if i < len(runes) && isConsonant(string(runes[i])) {
 		// Take final consonant if:
 		// - We have a vowel
@@ -1260,6 +1487,11 @@ func parseSyllableComponents(syllable string) SyllableComponents {
 		}
 	}
 	
+	// ไ-ย: the ย is silent (ไทย, ไชย)
+	if (leadingVowel == "ไ" || leadingVowel == "ใ") && finalCons == "ย" {
+		finalCons = ""
+	}
+
 	// Determine vowel sound
 	comp.Vowel = determineVowelSound(leadingVowel, vowelMarks, finalCons)
 	
@@ -1393,97 +1625,18 @@ func determineVowelSound(leading, marks, final string) string {
 // applyTone applies tone marks to the transliteration
 func applyTone(text string, comp SyllableComponents) string {
 	// Determine tone class
-	toneClass := "mid"
-	if highClass[comp.InitialThai] {
-		toneClass = "high"
-	} else if lowClass[comp.InitialThai] {
-		toneClass = "low"
+	toneClass := ToneClassOf(comp.InitialThai)
+	if toneClass == "" {
+		toneClass = "mid"
 	}
 	
 	// Determine if syllable is live or dead
-	// Live: ends in sonorant (m, n, ng, y, w) or long vowel
-	// Dead: ends in stop (p, t, k) or short vowel
-	isLive := false
-	
-	// Check final consonant
-	if comp.Final == "" || comp.Final == "n" || comp.Final == "m" || comp.Final == "ng" || comp.Final == "i" || comp.Final == "o" {
-		isLive = true
-	} else if comp.Final == "p" || comp.Final == "t" || comp.Final == "k" {
-		isLive = false
-	}
-	
-	// Check vowel length (long vowels make syllable live)
-	longVowels := []string{"aa", "ii", "ʉʉ", "uu", "ee", "ɛɛ", "oo", "ɔɔ", "əə", "iia", "ʉʉa", "uua"}
-	for _, lv := range longVowels {
-		if strings.Contains(comp.Vowel, lv) {
-			isLive = true
-			break
-		}
-	}
-	
-	// Diphthongs are also live
-	if strings.Contains(comp.Vowel, "ai") || strings.Contains(comp.Vowel, "ao") {
-		isLive = true
-	}
+	// Live: ends in sonorant (m, n, ng, y, w) or open with a long vowel
+	// Dead: ends in stop (p, t, k) or open with a short vowel
+	isLive := IsLiveSyllable(comp.Vowel, comp.Final)
 	
 	// Get tone number based on Thai tone rules
-	toneNum := 0 // mid tone by default
-	
-	if comp.ToneMark == "" {
-		// No tone mark - use inherent tone rules
-		switch toneClass {
-		case "low":
-			if isLive {
-				toneNum = 0 // mid tone
-			} else {
-				toneNum = 2 // high tone (short dead syllable)
-			}
-		case "mid":
-			if isLive {
-				toneNum = 0 // mid tone
-			} else {
-				toneNum = 1 // low tone
-			}
-		case "high":
-			if isLive {
-				toneNum = 4 // rising tone
-			} else {
-				toneNum = 1 // low tone
-			}
-		}
-	} else {
-		// Apply tone mark rules
-		switch comp.ToneMark {
-		case "่": // mai ek
-			switch toneClass {
-			case "low":
-				toneNum = 3 // falling
-			case "mid":
-				toneNum = 1 // low
-			case "high":
-				toneNum = 1 // low
-			}
-		case "้": // mai tho  
-			switch toneClass {
-			case "low":
-				toneNum = 2 // high
-			case "mid":
-				toneNum = 3 // falling
-			case "high":
-				toneNum = 3 // falling
-			}
-		case "๊": // mai tri
-			if toneClass == "mid" {
-				toneNum = 2 // high
-			}
-			// Ignored on high/low class
-		case "๋": // mai jattawa
-			if toneClass == "mid" {
-				toneNum = 4 // rising
-			}
-			// Ignored on high/low class
-		}
-	}
+	toneNum := calculateToneNum(toneClass, isLive, comp.ToneMark, IsLongVowel(comp.Vowel))
 	
 	// Add tone mark to the romanization using proper grapheme handling
 	if toneNum == 0 {
@@ -1562,11 +1715,11 @@ func RemoveSilentConsonants(text string) string {
 }
 
 func isConsonantRune(r rune) bool {
-	return strings.ContainsRune("กขฃคฅฆงจฉชซฌญฎฏฐฑฒณดตถทธนบปผฝพฟภมยรฤลฦวศษสหฬอฮ", r)
+	return thai.IsConsonant(r) || thai.IsVowelLetter(r)
 }
 
 func isVowelRune(r rune) bool {
-	return strings.ContainsRune("ะัาิีึืุูเแโใไๅำ", r)
+	return thai.IsVowel(r)
 }
 
 func isConsonant(s string) bool {
@@ -1613,15 +1766,19 @@ var m = make(map[string]string)
 var re = regexp.MustCompile(`(.*),(.*\p{Thai}.*)`)
 
 // loadDictionary loads the dictionary from embedded files.
-// Called lazily via ensureDictionaryLoaded() on first use.
-func loadDictionary() {
-	// Use embedded filesystem for vocab files
+// Called once by Load, explicitly or lazily on first use.
+func loadDictionary() error {
+	// Use embedded filesystem for vocab files, absent from slim builds
 	entries, err := fs.ReadDir(vocabFS, "csv")
-	check(err)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("reading vocab files: %w", err)
+	}
 
 	for _, e := range entries {
 		dat, err := fs.ReadFile(vocabFS, "csv/"+e.Name())
-		check(err)
+		if err != nil {
+			return fmt.Errorf("reading vocab files: %w", err)
+		}
 		arr := strings.Split(string(dat), "\n")
 
 		for _, str := range arr {
@@ -1629,16 +1786,28 @@ func loadDictionary() {
 			if len(raw) == 0 {
 				continue
 			}
-			row := strings.Split(raw[2], ",")[:2]
+			fields := strings.Split(raw[2], ",")
+			row := fields[:2]
 			th := html.UnescapeString(row[0])
-			translit := html.UnescapeString(row[1])
+			translit := canonicalEntry(th, html.UnescapeString(row[1]), "csv/"+e.Name())
+			if len(fields) > 3 && fields[3] != "" {
+				partOfSpeech[th] = fields[3]
+			}
+			if _, ok := vocabGlosses[th]; !ok && raw[1] != "" {
+				vocabGlosses[th] = html.UnescapeString(raw[1])
+			}
 
 			// Add to test data
 			words = append(words, th)
 			m[th] = translit
 
 			// Build dictionary
-			dictionary[th] = translit
+			value := DictValue{Roman: translit}
+			if len(fields) > 3 && fields[3] != "" {
+				value.Tags = []string{"pos:" + fields[3]}
+			}
+			dictionary[th] = value
+			officialFiles[th] = "csv/" + e.Name()
 
 			// Try to extract single syllables for syllable dictionary
 			// Add short words and very common syllables
@@ -1653,23 +1822,26 @@ func loadDictionary() {
 		}
 	}
 
+	// Slim builds get their syllables pre-extracted
+	loadSyllableDictionary()
+
 	// Extract syllables from multi-syllable dictionary entries
 	extractSyllablesFromDictionary()
 
 	// Load Opus dictionary (LLM-generated, optional)
 	loadOpusDictionary()
 
-	fmt.Printf("Dictionary built: %d entries, %d syllables\n", len(dictionary), len(syllableDict))
-	if len(opusDictionary) > 0 {
-		fmt.Printf("Opus dictionary: %d entries\n", len(opusDictionary))
-	}
+	// Tag entries by orthographic features for per-category metrics
+	tagDictionaryEntries()
+	return nil
 }
 
 // loadOpusDictionary loads the LLM-generated dictionary from TSV file.
-// Format: thai\troman (tab-separated)
+// Format: thai\troman (tab-separated), optionally followed by variants,
+// tags and notes columns (see parseDictValue)
 // This dictionary has lower priority than the official dictionary.
 func loadOpusDictionary() {
-	data, err := opusDictFS.ReadFile("opus_dictionary.tsv")
+	data, err := opusDictFS.ReadFile(opusDictionaryFile)
 	if err != nil {
 		// File doesn't exist or is empty - that's fine, it's optional
 		return
@@ -1681,14 +1853,15 @@ func loadOpusDictionary() {
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
-		parts := strings.SplitN(line, "\t", 2)
-		if len(parts) != 2 {
+		parts := strings.Split(line, "\t")
+		if len(parts) < 2 {
 			continue
 		}
 		thai := strings.TrimSpace(parts[0])
-		roman := strings.TrimSpace(parts[1])
-		if thai != "" && roman != "" {
-			opusDictionary[thai] = roman
+		value := parseDictValue(parts[1:])
+		if thai != "" && value.Roman != "" {
+			value.Roman = canonicalEntry(thai, value.Roman, opusDictionaryFile)
+			opusDictionary[thai] = value
 		}
 	}
 }
@@ -1706,10 +1879,10 @@ func extractSyllablesFromDictionary() {
 
 	// Process entries with hyphens (multi-syllable words)
 	for _, th := range sortedKeys {
-		translit := dictionary[th]
+		translit := dictionary[th].Roman
 		if strings.Contains(translit, "-") {
 			// Split Thai text into syllables using rule-based extraction
-			thaiSyllables := ExtractSyllables(th)
+			thaiSyllables := extractSyllablesRaw(th)
 			// Split romanization by hyphens
 			romanSyllables := strings.Split(translit, "-")
 
@@ -1738,12 +1911,6 @@ func extractSyllablesFromDictionary() {
 	}
 }
 
-func check(e error) {
-	if e != nil {
-		panic(e)
-	}
-}
-
 /*
 func main() {
 	// Define command line flags
@@ -61,8 +61,8 @@ func parseThaiSyllable(syl string) ComprehensiveSyllable {
 				if _, ok := clusters[cluster]; ok {
 					cs.Initial2 = string(runes[i])
 					i++
-				} else if cs.Initial1 == "ห" && (string(runes[i]) == "น" || string(runes[i]) == "ม" || string(runes[i]) == "ล" || string(runes[i]) == "ว" || string(runes[i]) == "ย") {
-					// ห leading consonant clusters
+				} else if leadConsonants[cs.Initial1][string(runes[i])] {
+					// Silent leading consonant (หน, หม, อย, ...)
 					cs.Initial2 = string(runes[i])
 					i++
 				} else if i+1 < len(runes) && !isVowel(string(runes[i+1])) && !isToneMark(string(runes[i+1])) {
@@ -130,8 +130,8 @@ func buildPaiboonFromSyllable(cs ComprehensiveSyllable) string {
 					vowelSound = "skip" // Skip vowel processing
 				}
 			}
-		} else if cs.Initial1 == "ห" {
-			// ห is silent in these clusters
+		} else if leadConsonants[cs.Initial1] != nil {
+			// The lead is silent
 			if trans, ok := initialConsonants[cs.Initial2]; ok {
 				initialSound = trans
 			}
@@ -164,6 +164,9 @@ func buildPaiboonFromSyllable(cs ComprehensiveSyllable) string {
 		} else if cs.Vowel1 == "" && cs.Final1 == "ย" {
 			vowelSound = "əəi"
 			cs.Final1 = "" // ย is part of vowel
+		} else if cs.Vowel1 == "า" && cs.Vowel2 == "ะ" {
+			// เ-าะ, the short form of -ɔɔ (เกาะ), not เ-า
+			vowelSound = "ɔ"
 		} else if cs.Vowel1 == "า" {
 			vowelSound = "ao"
 		} else if cs.Vowel1 == "ิ" {
@@ -177,8 +180,6 @@ func buildPaiboonFromSyllable(cs ComprehensiveSyllable) string {
 			}
 		} else if cs.Vowel1 == "็" {
 			vowelSound = "e"
-		} else if cs.Vowel1 == "า" && cs.Vowel2 == "ะ" {
-			vowelSound = "ɔ"
 		} else if cs.Vowel1 == "ี" && cs.Vowel2 == "ย" && cs.Final1 == "ว" {
 			vowelSound = "iao"
 			cs.Final1 = ""
@@ -204,6 +205,11 @@ func buildPaiboonFromSyllable(cs ComprehensiveSyllable) string {
 		}
 	} else if cs.LeadingVowel == "ไ" || cs.LeadingVowel == "ใ" {
 		vowelSound = "ai"
+		if cs.Final1 == "ย" {
+			// ไ-ย: the ย is silent (ไทย, ไชย)
+			cs.Final1 = cs.Final2
+			cs.Final2 = ""
+		}
 	} else {
 		// No leading vowel - check complex patterns first
 		if cs.Vowel1 == "ั" && cs.Vowel2 == "ว" {
@@ -275,14 +281,10 @@ func buildPaiboonFromSyllable(cs ComprehensiveSyllable) string {
 	result = initialSound + vowelSound + finalSound
 	
 	// Apply tone
-	toneClass := "mid"
-	if cs.Initial1 == "ห" && cs.Initial2 != "" {
-		// ห affects tone class
-		toneClass = "high"
-	} else if highClass[cs.Initial1] {
-		toneClass = "high"
-	} else if lowClass[cs.Initial1] {
-		toneClass = "low"
+	// A silent lead gives its class to the consonant it leads
+	toneClass := ToneClassOf(cs.Initial1 + cs.Initial2)
+	if toneClass == "" {
+		toneClass = "mid"
 	}
 	
 	// Determine if live or dead syllable
@@ -419,27 +421,105 @@ func findSyllableEndComprehensive(runes []rune, start int) int {
 	return findSyllableEndImproved(runes, start)
 }
 
+// cutsSyllable reports whether a syllable starting at runes[start] goes on
+// past runes[end]: the ะ of เ-าะ and the silent ย of ไ-ย (ไชยชนะ) end
+// their syllable, they can't start the next one
+func cutsSyllable(runes []rune, start, end int) bool {
+	if end >= len(runes) {
+		return false
+	}
+	if runes[end] == 'ะ' {
+		return true
+	}
+	return (runes[start] == 'ไ' || runes[start] == 'ใ') && end-start >= 2 && runes[end] == 'ย' &&
+		(end+1 == len(runes) || isConsonantRune(runes[end+1]) || isLeadingVowel(string(runes[end+1])))
+}
+
 // ComprehensiveTransliterate performs advanced Thai-to-Paiboon transliteration
 // using comprehensive syllable parsing, pattern recognition, and tone rules.
 // It handles complex vowel patterns, consonant clusters, and special cases.
 func ComprehensiveTransliterate(word string) string {
+	trans, _ := comprehensiveTransliterate(word, FailureDrop, JoinDictionary)
+	return trans
+}
+
+// comprehensiveTransliterate is ComprehensiveTransliterate, also returning
+// the syllables the rules romanized to nothing, which are replaced as
+// onFailure says. The syllables are joined as join says.
+func comprehensiveTransliterate(word string, onFailure FailurePolicy, join JoinPolicy) (string, []DroppedSpan) {
+	if LooksRomanized(word) {
+		return word, nil
+	}
+	pieces, dropped := comprehensivePieces(word, onFailure, join)
+	results := []string{}
+	for _, p := range pieces {
+		if p.roman != "" {
+			results = append(results, p.roman)
+		}
+	}
+	if len(results) == 0 {
+		return "", dropped
+	}
+	// Normalize to NFC to match dictionary expectations (precomposed characters)
+	return norm.NFC.String(join.join(results)), dropped
+}
+
+// wordPiece is a part of a word the comprehensive engine romanized in one
+// go: a syllable, or several when a special case or syllable dictionary
+// entry covers them
+type wordPiece struct {
+	thai   string
+	roman  string // NFC; empty or the failure text for dropped syllables
+	source RomanSource
+}
+
+// comprehensivePieces splits word as comprehensiveTransliterate does and
+// returns the romanized pieces in order, silent letters of a final cluster
+// included in the piece before them
+func comprehensivePieces(word string, onFailure FailurePolicy, join JoinPolicy) ([]wordPiece, []DroppedSpan) {
 	ensureDictionaryLoaded()
 	// Try special cases first (irregular words, loanwords)
 	if trans, ok := specialCasesGlobal[word]; ok {
-		return norm.NFC.String(trans)
+		return []wordPiece{{word, norm.NFC.String(trans), SourceSpecialCase}}, nil
 	}
 
 	// Try syllable dictionary for known syllables
 	if trans, ok := syllableDict[word]; ok {
-		return norm.NFC.String(trans)
+		return []wordPiece{{word, norm.NFC.String(trans), SourceSyllableDictionary}}, nil
 	}
 
 	// Try to find longest matching syllables from dictionary and special cases
-	results := []string{}
+	pieces := []wordPiece{}
+	var dropped []DroppedSpan
 	runes := []rune(word)
+	indic := IsIndicLoan(word)
 	i := 0
+	// emit appends the romanization of runes[start:end] by the rules
+	emit := func(trans string, start, end int) {
+		if trans == "" {
+			dropped = append(dropped, DroppedSpan{Text: string(runes[start:end]), Offset: start})
+			trans = failureText(string(runes[start:end]), onFailure)
+		}
+		pieces = append(pieces, wordPiece{string(runes[start:end]), norm.NFC.String(trans), SourceRules})
+	}
 
 	for i < len(runes) {
+		// Silent letters of a final cluster after the previous syllable
+		if n := silentFinalCluster(runes, i); n > 0 {
+			if len(pieces) > 0 {
+				pieces[len(pieces)-1].thai += string(runes[i : i+n])
+			}
+			i += n
+			continue
+		}
+		if n := indicSilentLetters(runes, i); indic && n > 0 {
+			if len(pieces) > 0 {
+				pieces[len(pieces)-1].thai += string(runes[i : i+n])
+			}
+			i += n
+			continue
+		}
+
 		found := false
 		// Try longest possible match first (maximal matching)
 		// Limit search to reasonable syllable lengths (max 8 runes for a syllable)
@@ -456,23 +536,39 @@ func ComprehensiveTransliterate(word string) string {
 				// (a single consonant without a vowel at the end)
 				if i+length < len(runes) {
 					remaining := runes[i+length:]
-					if len(remaining) == 1 && isConsonant(string(remaining[0])) {
+					if len(remaining) == 1 && isConsonant(string(remaining[0])) && silentFinalCluster(runes, i+length) == 0 {
 						// Would leave orphan consonant - skip this match
 						// unless it's at the start of a new syllable pattern
 						continue
 					}
+					// Nor cut a syllable short: เลา of เลาะ, ไช of ไชย
+					if cutsSyllable(runes, i, i+length) {
+						continue
+					}
 				}
 
-				// Check special cases first
+				// Check final cluster exceptions and special cases first
+				if trans, ok := finalClusterExceptions[substr]; ok {
+					pieces = append(pieces, wordPiece{substr, norm.NFC.String(trans), SourceSpecialCase})
+					i += length
+					found = true
+					break
+				}
 				if trans, ok := specialCasesGlobal[substr]; ok {
-					results = append(results, norm.NFC.String(trans))
+					pieces = append(pieces, wordPiece{substr, norm.NFC.String(trans), SourceSpecialCase})
 					i += length
 					found = true
 					break
 				}
 				// Then check syllable dictionary
 				if trans, ok := syllableDict[substr]; ok {
-					results = append(results, norm.NFC.String(trans))
+					pieces = append(pieces, wordPiece{substr, norm.NFC.String(trans), SourceSyllableDictionary})
+					i += length
+					found = true
+					break
+				}
+				if trans, ok := silentRFinal(runes, i, length, join); ok {
+					pieces = append(pieces, wordPiece{substr, norm.NFC.String(trans), SourceRules})
 					i += length
 					found = true
 					break
@@ -485,32 +581,21 @@ func ComprehensiveTransliterate(word string) string {
 			end := findSyllableEndComprehensive(runes, i)
 			if end > i {
 				syl := string(runes[i:end])
-				// Try pattern matching on single syllable
-				trans := improvedTransliterate(syl)
-				if trans == "" {
-					// Fall back to comprehensive parsing
-					parsed := parseThaiSyllable(syl)
-					trans = buildPaiboonFromSyllable(parsed)
-				}
-				if trans != "" {
-					results = append(results, trans)
-				}
+				// Pattern matching first, then the parsers for outputs
+				// that are empty or phonotactically impossible
+				emit(ruleTransliterateSyllable(syl), i, end)
 				i = end
 			} else {
 				// Single character
 				parsed := parseThaiSyllable(string(runes[i]))
-				trans := buildPaiboonFromSyllable(parsed)
-				if trans != "" {
-					results = append(results, trans)
-				}
+				emit(buildPaiboonFromSyllable(parsed), i, i+1)
 				i++
 			}
 		}
 	}
-
-	if len(results) == 0 {
-		return ""
+	if indic {
+		addLinkers(pieces)
 	}
-	// Normalize to NFC to match dictionary expectations (precomposed characters)
-	return norm.NFC.String(strings.Join(results, ""))
+
+	return pieces, dropped
 }
\ No newline at end of file
@@ -183,22 +183,28 @@ var thaiVowelPatterns = []VowelPattern{
 	{pattern: "C", paiboon: "ɔɔ", hasFinal: false, priority: -101}, // Open syllable inherent
 }
 
-// sortedVowelPatterns holds patterns sorted by length then priority
-var sortedVowelPatterns []VowelPattern
+// builtinVowelPatterns holds thaiVowelPatterns sorted by length then priority
+var builtinVowelPatterns []VowelPattern
 
 func init() {
 	// Sort patterns: longer patterns first, then by priority within same length
-	sortedVowelPatterns = make([]VowelPattern, len(thaiVowelPatterns))
-	copy(sortedVowelPatterns, thaiVowelPatterns)
-
-	sort.Slice(sortedVowelPatterns, func(i, j int) bool {
-		lenI := len([]rune(sortedVowelPatterns[i].pattern))
-		lenJ := len([]rune(sortedVowelPatterns[j].pattern))
-		if lenI != lenJ {
-			return lenI > lenJ // Longer first
-		}
-		return sortedVowelPatterns[i].priority > sortedVowelPatterns[j].priority
+	builtinVowelPatterns = make([]VowelPattern, len(thaiVowelPatterns))
+	copy(builtinVowelPatterns, thaiVowelPatterns)
+
+	sort.Slice(builtinVowelPatterns, func(i, j int) bool {
+		return vowelPatternLess(builtinVowelPatterns[i], builtinVowelPatterns[j])
 	})
+	sortedVowelPatterns.Store(&builtinVowelPatterns)
+}
+
+// vowelPatternLess orders patterns longer first, then by priority
+func vowelPatternLess(a, b VowelPattern) bool {
+	lenA := len([]rune(a.pattern))
+	lenB := len([]rune(b.pattern))
+	if lenA != lenB {
+		return lenA > lenB // Longer first
+	}
+	return a.priority > b.priority
 }
 
 // improvedTransliterate uses pattern matching for better accuracy
@@ -211,7 +217,7 @@ func improvedTransliterate(word string) string {
 	word = RemoveSilentConsonants(word)
 
 	// Try each pattern from sorted list (longest first)
-	for _, vp := range sortedVowelPatterns {
+	for _, vp := range *sortedVowelPatterns.Load() {
 		if match, result := matchPatternImproved(word, vp.pattern, vp.paiboon); match {
 			return result
 		}
@@ -336,31 +342,26 @@ func matchPatternImproved(word, pattern, paiboon string) (bool, string) {
 
 // applyToneToResult applies tone marking to the romanized result
 func applyToneToResult(result, initialCons, cluster, toneMark, vowel, finalCons string) string {
-	// Determine tone class
-	toneClass := "mid"
-	toneKey := initialCons
+	// Determine tone class (ห-clusters are high, true clusters follow
+	// their first consonant)
+	initial := initialCons
 	if cluster != "" {
-		// Check if cluster has special tone class (ห-clusters)
-		if tc, ok := clusterToneClass[cluster]; ok {
-			toneClass = tc
-		} else {
-			toneKey = string([]rune(cluster)[0])
-		}
+		initial = cluster
 	}
-
-	if toneClass == "mid" {
-		if highClass[toneKey] {
-			toneClass = "high"
-		} else if lowClass[toneKey] {
-			toneClass = "low"
-		}
+	toneClass := ToneClassOf(initial)
+	if toneClass == "" {
+		toneClass = "mid"
 	}
 
 	// Determine live/dead syllable
-	isLive := isLiveSyllable(vowel, finalCons)
+	finalSound := ""
+	if finalCons != "" {
+		finalSound = finalConsonants[finalCons]
+	}
+	isLive := IsLiveSyllable(vowel, finalSound)
 
 	// Determine vowel length (for dead-short vs dead-long tone distinction)
-	longVowel := isLongVowel(vowel)
+	longVowel := IsLongVowel(vowel)
 
 	// Calculate tone number
 	toneNum := calculateToneNum(toneClass, isLive, toneMark, longVowel)
@@ -373,54 +374,6 @@ func applyToneToResult(result, initialCons, cluster, toneMark, vowel, finalCons
 	return addToneDiacritic(result, toneNum)
 }
 
-// isLiveSyllable determines if a syllable is live or dead
-func isLiveSyllable(vowel, finalCons string) bool {
-	// Dead endings
-	deadFinals := map[string]bool{"p": true, "t": true, "k": true}
-	if finalCons != "" {
-		if trans, ok := finalConsonants[finalCons]; ok {
-			if deadFinals[trans] {
-				return false
-			}
-		}
-	}
-
-	// Long vowels and sonorant endings make syllable live
-	longVowels := []string{"aa", "ii", "ʉʉ", "uu", "ee", "ɛɛ", "oo", "ɔɔ", "əə", "iia", "ʉʉa", "uua", "ai", "ao", "aai", "aao"}
-	for _, lv := range longVowels {
-		if strings.Contains(vowel, lv) {
-			return true
-		}
-	}
-
-	// Short vowels with no final or sonorant final are live
-	if finalCons == "" {
-		return true
-	}
-
-	// Check for sonorant finals (m, n, ng, y, w)
-	if trans, ok := finalConsonants[finalCons]; ok {
-		sonorants := map[string]bool{"m": true, "n": true, "ng": true, "i": true, "o": true}
-		return sonorants[trans]
-	}
-
-	return false
-}
-
-// isLongVowel checks if a romanized vowel is long
-// Long vowels have doubled letters (aa, ii, uu, etc.) or specific patterns
-// Note: "ai" and "ao" are SHORT diphthongs; "aai" and "aao" are long
-func isLongVowel(vowel string) bool {
-	// Check long diphthongs first (before short ones would match via Contains)
-	longPatterns := []string{"aai", "aao", "aa", "ii", "ʉʉ", "uu", "ee", "ɛɛ", "oo", "ɔɔ", "əə", "iia", "ʉʉa", "uua"}
-	for _, lp := range longPatterns {
-		if strings.Contains(vowel, lp) {
-			return true
-		}
-	}
-	return false
-}
-
 // calculateToneNum calculates the tone number based on Thai tone rules
 // isLongVowelParam is used to distinguish dead-short vs dead-long for low-class consonants
 func calculateToneNum(toneClass string, isLive bool, toneMark string, isLongVowelParam bool) int {
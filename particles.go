@@ -0,0 +1,114 @@
+package paiboonizer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ParticleMode selects how TransliterateText marks sentence-final particles
+type ParticleMode int
+
+const (
+	ParticlePlain  ParticleMode = iota // No marking: pǒm bpai ná kráp
+	ParticleParens                     // In parentheses: pǒm bpai (ná) (kráp)
+	ParticleHTML                       // In a span: pǒm bpai <span class="particle">ná</span> ...
+)
+
+// sentenceFinalParticles are the particles that close an utterance,
+// marking politeness, mood or the speaker's attitude
+var sentenceFinalParticles = map[string]bool{
+	// Politeness
+	"ครับ": true, "ค่ะ": true, "คะ": true, "ขา": true, "จ้ะ": true, "จ้า": true, "จ๊ะ": true,
+	// Mood and attitude
+	"นะ": true, "น่ะ": true, "สิ": true, "ซิ": true, "เถอะ": true, "เถิด": true,
+	"ล่ะ": true, "หรอก": true, "เลย": true, "เหรอ": true, "หรือ": true, "ไหม": true,
+	"มั้ย": true, "เนอะ": true, "แหละ": true, "ละ": true,
+}
+
+// IsSentenceFinalParticle reports whether word is a sentence-final particle
+// (ครับ, ค่ะ, นะ, สิ, เถอะ, ...) or a run of them written together (นะครับ)
+func IsSentenceFinalParticle(word string) bool {
+	if word == "" {
+		return false
+	}
+	if sentenceFinalParticles[word] {
+		return true
+	}
+	runes := []rune(word)
+	for n := len(runes) - 1; n > 0; n-- {
+		if sentenceFinalParticles[string(runes[:n])] && IsSentenceFinalParticle(string(runes[n:])) {
+			return true
+		}
+	}
+	return false
+}
+
+// questionEndings are the particles and question words a question ends
+// with, before any politeness particle
+var questionEndings = []string{
+	// Particles
+	"ไหม", "มั้ย", "มั๊ย", "ไม๊", "หรือ", "หรือเปล่า", "รึเปล่า", "เปล่า", "เหรอ", "หรอ", "หรือยัง", "รึยัง", "ป่ะ",
+	// Question words
+	"อะไร", "ใคร", "ไหน", "เมื่อไร", "เมื่อไหร่", "ทำไม", "ยังไง", "อย่างไร", "เท่าไร", "เท่าไหร่",
+}
+
+// politenessParticles may follow the question particle (ไปไหมครับ)
+var politenessParticles = []string{"ครับ", "ค่ะ", "คะ", "ขา", "จ้ะ", "จ๊ะ", "จ้า", "นะ"}
+
+// IsQuestion reports whether Thai text ends as a question: in a question
+// particle (ไหม, หรือเปล่า, เหรอ) or a question word (อะไร, ที่ไหน),
+// optionally followed by politeness particles (ไปไหมครับ)
+func IsQuestion(text string) bool {
+	text = strings.TrimRightFunc(text, unicode.IsSpace)
+	for trimmed := true; trimmed; {
+		trimmed = false
+		for _, p := range politenessParticles {
+			if rest, ok := strings.CutSuffix(text, p); ok && rest != "" {
+				text, trimmed = strings.TrimRightFunc(rest, unicode.IsSpace), true
+			}
+		}
+	}
+	for _, q := range questionEndings {
+		if strings.HasSuffix(text, q) {
+			return true
+		}
+	}
+	return false
+}
+
+// terminalMark returns the punctuation Options.Punctuate ends a line with,
+// given the Thai the line ends with
+func terminalMark(thai string) string {
+	if IsQuestion(thai) {
+		return "?"
+	}
+	return "."
+}
+
+// isSentenceFinal reports whether tokens[i] is a sentence-final particle:
+// a particle followed by whitespace, punctuation, the end of the text or
+// another particle. Thai separates sentences with a space, so a particle
+// before a space ends its sentence.
+func isSentenceFinal(tokens []textToken, i int) bool {
+	if !IsSentenceFinalParticle(tokens[i].text) {
+		return false
+	}
+	for _, tok := range tokens[i+1:] {
+		if tok.kind == tokenMarkup {
+			continue
+		}
+		return tok.kind != tokenThai || IsSentenceFinalParticle(tok.text)
+	}
+	return true
+}
+
+// markParticle applies the ParticleMode of opts to a romanized particle
+func markParticle(roman string, opts Options) string {
+	switch opts.Particles {
+	case ParticleParens:
+		return "(" + roman + ")"
+	case ParticleHTML:
+		return `<span class="particle">` + roman + "</span>"
+	}
+	return roman
+}
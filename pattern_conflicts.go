@@ -0,0 +1,220 @@
+package paiboonizer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kinds of PatternConflict
+const (
+	// ConflictShadowed: every spelling the pattern matches is matched by a
+	// pattern tried before it, so it never fires
+	ConflictShadowed = "shadowed"
+	// ConflictAmbiguous: two patterns of the same length and priority
+	// match some spelling in common with different romanizations, so which
+	// one fires depends on the sort order alone
+	ConflictAmbiguous = "ambiguous"
+	// ConflictSamePriority: two patterns of the same length share their
+	// priority without overlapping. Harmless as is, but their order is left
+	// to the sort and an edit making them overlap would go unnoticed.
+	ConflictSamePriority = "same priority"
+)
+
+// PatternConflict is an anomaly of the vowel pattern table
+type PatternConflict struct {
+	Kind    string
+	Pattern VowelPattern
+	// Other holds the patterns shadowing Pattern, or the one sharing its
+	// priority
+	Other []VowelPattern
+}
+
+// String returns the conflict on one line
+func (c PatternConflict) String() string {
+	others := make([]string, len(c.Other))
+	for i, o := range c.Other {
+		others[i] = fmt.Sprintf("%s (%s, %d)", o.pattern, o.paiboon, o.priority)
+	}
+	verb := "with"
+	if c.Kind == ConflictShadowed {
+		verb = "by"
+	}
+	return fmt.Sprintf("%s (%s, %d) %s %s %s", c.Pattern.pattern, c.Pattern.paiboon, c.Pattern.priority, c.Kind, verb, strings.Join(others, ", "))
+}
+
+// VowelPatternConflicts checks the effective vowel pattern table (see
+// VowelPatterns) for patterns that can never fire and for same-length
+// patterns sharing their priority, overlapping with different
+// romanizations or not overlapping at all. Patterns are compared
+// symbolically: C matches any consonant, K any initial cluster and T an
+// optional tone mark, as improvedTransliterate reads them.
+func VowelPatternConflicts() []PatternConflict {
+	ensureDictionaryLoaded()
+	table := VowelPatterns()
+	shapes := make([][]patternShape, len(table))
+	for i, p := range table {
+		shapes[i] = patternShapes(p.pattern)
+	}
+
+	var conflicts []PatternConflict
+	for j, p := range table {
+		// Shadowed: each shape of p is covered by a pattern tried earlier
+		var by []VowelPattern
+		seen := make(map[int]bool)
+		shadowed := true
+		for _, s := range shapes[j] {
+			covered := false
+			for i := 0; i < j && !covered; i++ {
+				for _, t := range shapes[i] {
+					if t.generalizes(s) {
+						covered = true
+						if !seen[i] {
+							seen[i] = true
+							by = append(by, table[i])
+						}
+						break
+					}
+				}
+			}
+			if !covered {
+				shadowed = false
+				break
+			}
+		}
+		if shadowed {
+			conflicts = append(conflicts, PatternConflict{ConflictShadowed, p, by})
+			continue
+		}
+
+		for i := 0; i < j; i++ {
+			q := table[i]
+			if q.priority != p.priority || len([]rune(q.pattern)) != len([]rune(p.pattern)) {
+				continue
+			}
+			switch {
+			case !shapesOverlap(shapes[i], shapes[j]):
+				conflicts = append(conflicts, PatternConflict{ConflictSamePriority, p, []VowelPattern{q}})
+			case q.paiboon != p.paiboon:
+				conflicts = append(conflicts, PatternConflict{ConflictAmbiguous, p, []VowelPattern{q}})
+			}
+		}
+	}
+	return conflicts
+}
+
+// patternSlot is one character position of a spelling matched by a
+// pattern: any consonant (C), the first or second letter of a cluster (K,
+// k), a tone mark (T) or a literal character
+type patternSlot struct {
+	kind    rune
+	literal rune
+}
+
+// patternShape is one sequence of slots a pattern matches
+type patternShape []patternSlot
+
+// patternShapes expands a pattern into its shapes: each T gives a shape
+// with a tone mark and one without
+func patternShapes(pattern string) []patternShape {
+	shapes := []patternShape{{}}
+	for _, r := range pattern {
+		var next []patternShape
+		for _, s := range shapes {
+			switch r {
+			case 'C':
+				next = append(next, append(s[:len(s):len(s)], patternSlot{kind: 'C'}))
+			case 'K':
+				next = append(next, append(s[:len(s):len(s)], patternSlot{kind: 'K'}, patternSlot{kind: 'k'}))
+			case 'T':
+				next = append(next, s, append(s[:len(s):len(s)], patternSlot{kind: 'T'}))
+			default:
+				next = append(next, append(s[:len(s):len(s)], patternSlot{kind: 'L', literal: r}))
+			}
+		}
+		shapes = next
+	}
+	return shapes
+}
+
+// isConsonantSlot reports whether the slot only holds consonants
+func (s patternSlot) isConsonantSlot() bool {
+	return s.kind == 'C' || s.kind == 'K' || s.kind == 'k' || (s.kind == 'L' && isConsonantRune(s.literal))
+}
+
+// generalizes reports whether every spelling of shape t is one of s
+func (s patternShape) generalizes(t patternShape) bool {
+	if len(s) != len(t) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch s[i].kind {
+		case 'C':
+			if !t[i].isConsonantSlot() {
+				return false
+			}
+		case 'K':
+			// A cluster slot covers another cluster or a literal cluster
+			switch {
+			case t[i].kind == 'K':
+			case t[i].kind == 'L' && t[i+1].kind == 'L':
+				if _, ok := clusters[string([]rune{t[i].literal, t[i+1].literal})]; !ok {
+					return false
+				}
+			default:
+				return false
+			}
+			i++
+		case 'T':
+			if t[i].kind != 'T' && !(t[i].kind == 'L' && isToneMark(string(t[i].literal))) {
+				return false
+			}
+		default:
+			if t[i].kind != 'L' || t[i].literal != s[i].literal {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// shapesOverlap reports whether some spelling matches a shape of a and a
+// shape of b. Cluster slots are taken as any consonant, which may report
+// overlaps no valid cluster produces.
+func shapesOverlap(a, b []patternShape) bool {
+	for _, s := range a {
+		for _, t := range b {
+			if s.overlaps(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// overlaps reports whether some spelling matches both s and t
+func (s patternShape) overlaps(t patternShape) bool {
+	if len(s) != len(t) {
+		return false
+	}
+	for i := range s {
+		a, b := s[i], t[i]
+		switch {
+		case a.kind == 'L' && b.kind == 'L':
+			if a.literal != b.literal {
+				return false
+			}
+		case a.isConsonantSlot() || b.isConsonantSlot():
+			if !a.isConsonantSlot() || !b.isConsonantSlot() {
+				return false
+			}
+		default:
+			// Tone slots against tone slots or literal tone marks
+			for _, x := range []patternSlot{a, b} {
+				if x.kind == 'L' && !isToneMark(string(x.literal)) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
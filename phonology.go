@@ -0,0 +1,129 @@
+package paiboonizer
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// IsLongVowel reports whether a Paiboon vowel is long: doubled letters (aa,
+// ii, ʉʉ, ...), the long diphthongs (iia, ʉʉa, uua) and the long vowels
+// followed by a glide (aai, aao). The short diphthongs ai and ao, spelled
+// ไ-, ใ- and เ-า, are short.
+func IsLongVowel(vowel string) bool {
+	// Long diphthongs are checked first so that ai/ao can't shadow them
+	longPatterns := []string{"aai", "aao", "aa", "ii", "ʉʉ", "uu", "ee", "ɛɛ", "oo", "ɔɔ", "əə", "iia", "ʉʉa", "uua"}
+	for _, lp := range longPatterns {
+		if strings.Contains(vowel, lp) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLiveSyllable reports whether a syllable is live (คำเป็น) from its
+// Paiboon vowel and final sound ("", m, n, ng, p, t or k). Stop finals make
+// a syllable dead whatever the vowel length; open syllables are live when
+// the vowel is long or ends in a glide or nasal (ai, ao, am, iu), dead
+// when it is a short monophthong (จะ, ติ).
+func IsLiveSyllable(vowel, final string) bool {
+	switch final {
+	case "p", "t", "k":
+		return false
+	case "":
+	default:
+		return true
+	}
+	if IsLongVowel(vowel) {
+		return true
+	}
+	runes := []rune(vowel)
+	if len(runes) < 2 {
+		return false
+	}
+	switch runes[len(runes)-1] {
+	case 'i', 'o', 'u', 'm':
+		return true
+	}
+	return false
+}
+
+// ToneClassOf returns the tone class ("high", "mid" or "low") of a Thai
+// initial, which may be a single consonant or a cluster. A true cluster
+// takes the class of its first consonant (กร mid, ขว high, คล low), and
+// so does a silent leading consonant followed by the consonant it leads: ห
+// before a sonorant makes it high (หม, หล, หว) and อ before ย makes it mid
+// (อย), see LeadConsonants. Returns "" if initial doesn't start with a
+// consonant.
+func ToneClassOf(initial string) string {
+	runes := []rune(initial)
+	if len(runes) == 0 {
+		return ""
+	}
+	return toneClassName(string(runes[0]))
+}
+
+// SyllableInfo is the tone analysis of a Thai syllable: the inputs of the
+// tone rules and the tone they give
+type SyllableInfo struct {
+	Syllable string // The syllable, in NFC
+	Roman    string // Its romanization by the syllable rules
+	// Initial is the Thai consonant or cluster the tone class is taken
+	// from, a silent leading ห or อ included (หม, อย)
+	Initial   string
+	ToneClass string // high, mid or low
+	ToneMark  string // The Thai tone mark, empty if none
+	Vowel     string // Paiboon vowel, without tone (aa, ʉʉa)
+	Final     string // Paiboon final sound: "", m, n, ng, p, t or k
+	Live      bool   // Live (คำเป็น) or dead (คำตาย) syllable
+	LongVowel bool
+	// Tone is the tone the spelling rules give the syllable: mid, low,
+	// falling, high or rising. Irregular words (ก็, เขา) read with another
+	// tone, which Roman then shows.
+	Tone string
+	// ToneNumber is Tone in the order of the Paiboon chart, as in
+	// SyllableResult: 0 mid, 1 low, 2 falling, 3 high, 4 rising
+	ToneNumber int
+}
+
+// toneNames maps the tone numbers of calculateToneNum to tone names
+var toneNames = []string{"mid", "low", "high", "falling", "rising"}
+
+// AnalyzeSyllable returns the tone analysis of a single Thai syllable: the
+// class of its initial, its tone mark, vowel length, whether it is live
+// or dead and the tone these give, for learner tools and linguists who
+// need the reasoning rather than the romanization alone. ok is false when
+// syl isn't a syllable the rules can analyze, such as a word of several
+// syllables.
+func AnalyzeSyllable(syl string) (info SyllableInfo, ok bool) {
+	ensureDictionaryLoaded()
+	syl = norm.NFC.String(syl)
+	roman, _ := lookupOrRuleSyllable(syl)
+	roman = norm.NFC.String(roman)
+	if roman == "" || romanSyllableSep.MatchString(roman) {
+		return SyllableInfo{}, false
+	}
+	_, vowel, final, _, ok := SplitPaiboonSyllable(roman)
+	if !ok {
+		return SyllableInfo{}, false
+	}
+	cs := parseThaiSyllable(syl)
+	class := ToneClassOf(cs.Initial1 + cs.Initial2)
+	if class == "" {
+		return SyllableInfo{}, false
+	}
+	info = SyllableInfo{
+		Syllable:  syl,
+		Roman:     roman,
+		Initial:   cs.Initial1 + cs.Initial2,
+		ToneClass: class,
+		ToneMark:  cs.Tone,
+		Vowel:     vowel,
+		Final:     final,
+		Live:      IsLiveSyllable(vowel, final),
+		LongVowel: IsLongVowel(vowel),
+	}
+	info.Tone = toneNames[calculateToneNum(class, info.Live, cs.Tone, info.LongVowel)]
+	info.ToneNumber = toneOrder(info.Tone)
+	return info, true
+}
@@ -0,0 +1,131 @@
+package paiboonizer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// PhonotacticIssue is a romanized syllable that can't be a Thai syllable
+type PhonotacticIssue struct {
+	Syllable string
+	Problem  string
+}
+
+// String returns the issue as "syllable: problem"
+func (i PhonotacticIssue) String() string {
+	return i.Syllable + ": " + i.Problem
+}
+
+// paiboonVowelLetters are the letters that make up Paiboon vowels
+const paiboonVowelLetters = "aeiouɛɔəʉ"
+
+// CheckPhonotactics returns the syllables of a romanized word that break
+// Thai phonotactics, nil if there are none. A Thai syllable has a known
+// onset, one vowel nucleus, at most one final among m, n, ng, p, t and k
+// (r, l, s and the like are respelled by Thai, never pronounced finally),
+// and a tone mark only when it has more than a bare ə.
+func CheckPhonotactics(roman string) []PhonotacticIssue {
+	var issues []PhonotacticIssue
+	for _, syllable := range romanSyllableSep.Split(strings.TrimSpace(roman), -1) {
+		if syllable == "" {
+			continue
+		}
+		if problem := syllableProblem(syllable); problem != "" {
+			issues = append(issues, PhonotacticIssue{Syllable: syllable, Problem: problem})
+		}
+	}
+	return issues
+}
+
+// syllableProblem describes the first phonotactic rule syllable breaks, ""
+// if it is a possible Thai syllable
+func syllableProblem(syllable string) string {
+	toned := false
+	var base strings.Builder
+	for _, r := range norm.NFD.String(syllable) {
+		if _, ok := toneByMark[r]; ok {
+			toned = true
+			continue
+		}
+		if unicode.Is(unicode.Mn, r) {
+			return fmt.Sprintf("unexpected diacritic %U", r)
+		}
+		base.WriteRune(r)
+	}
+	s := norm.NFC.String(base.String())
+
+	// Split into onset, nucleus and coda
+	runes := []rune(s)
+	i := 0
+	for i < len(runes) && !strings.ContainsRune(paiboonVowelLetters, runes[i]) {
+		i++
+	}
+	onset := string(runes[:i])
+	j := i
+	for j < len(runes) && strings.ContainsRune(paiboonVowelLetters, runes[j]) {
+		j++
+	}
+	nucleus, coda := string(runes[i:j]), string(runes[j:])
+
+	switch {
+	case nucleus == "":
+		return "no vowel"
+	case strings.ContainsAny(coda, paiboonVowelLetters):
+		return "more than one vowel"
+	case onset != "" && !isPaiboonInitial(onset):
+		return fmt.Sprintf("impossible initial %q", onset)
+	case coda != "" && !isPaiboonFinal(coda):
+		if len([]rune(coda)) > 1 {
+			return fmt.Sprintf("consonant cluster in final %q", coda)
+		}
+		return fmt.Sprintf("impossible final %q", coda)
+	case toned && onset == "" && s == "ə":
+		return "tone mark on a bare ə"
+	}
+	return ""
+}
+
+// isPaiboonInitial reports whether onset is a Paiboon initial or cluster
+func isPaiboonInitial(onset string) bool {
+	for _, in := range paiboonInitials {
+		if in == onset {
+			return true
+		}
+	}
+	return false
+}
+
+// isPaiboonFinal reports whether coda is one of the Paiboon finals
+func isPaiboonFinal(coda string) bool {
+	for _, f := range paiboonFinals {
+		if f == coda {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleTransliterateSyllable romanizes a syllable with the vowel patterns,
+// or the comprehensive parser when no pattern matches. An output breaking
+// Thai phonotactics is routed to the other engines (comprehensive parser,
+// then syllable parser) and the first valid output is used instead; if
+// there is none the original output is kept.
+func ruleTransliterateSyllable(syl string) string {
+	comprehensive := func(s string) string { return buildPaiboonFromSyllable(parseThaiSyllable(s)) }
+	trans := improvedTransliterate(syl)
+	if trans == "" {
+		trans = comprehensive(syl)
+	}
+	if trans == "" || CheckPhonotactics(trans) == nil {
+		return trans
+	}
+	for _, fallback := range []func(string) string{comprehensive, transliterateSyllable} {
+		if alt := fallback(syl); alt != "" && CheckPhonotactics(alt) == nil {
+			return alt
+		}
+	}
+	return trans
+}
@@ -0,0 +1,212 @@
+package paiboonizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tassa-yoniso-manasi-karoto/go-pythainlp"
+)
+
+const (
+	// maxMemberFailures is the number of consecutive failures after which a
+	// pool member gets a health check
+	maxMemberFailures = 3
+	// healthCheckTimeout bounds the health check of a failing member
+	healthCheckTimeout = 5 * time.Second
+)
+
+// errNoHealthyMember is returned when every pool member has been evicted
+var errNoHealthyMember = errors.New("no healthy pythainlp instance left in the pool")
+
+// poolMember is one pythainlp container of a Manager
+type poolMember struct {
+	nlp      *pythainlp.PyThaiNLPManager
+	failures int
+	evicted  bool
+}
+
+// pool dispatches pythainlp calls round-robin over its members and evicts
+// members that keep failing their health check. The last healthy member is
+// never evicted so that a single-container Manager behaves as before.
+type pool struct {
+	mu      sync.Mutex
+	members []*poolMember
+	next    int
+	metrics Metrics
+}
+
+// WithPoolSize runs n pythainlp containers and dispatches calls round-robin
+// between them. Each container gets its own compose project (see
+// pythainlp.WithProjectName). Values below 1 are treated as 1.
+func WithPoolSize(n int) ManagerOption {
+	return func(m *Manager) {
+		if n < 1 {
+			n = 1
+		}
+		m.poolSize = n
+	}
+}
+
+// poolProjectName returns the compose project name of the i-th pool member.
+// The first member keeps the default project so that an existing container
+// is reused.
+func poolProjectName(i int) string {
+	if i == 0 {
+		return "pythainlp"
+	}
+	return fmt.Sprintf("pythainlp-%d", i+1)
+}
+
+// acquire picks the pythainlp instance for the next call
+func (m *Manager) acquire() (*poolMember, error) {
+	if m.pool == nil {
+		return &poolMember{nlp: m.nlpManager}, nil
+	}
+	return m.pool.acquire()
+}
+
+// release records the outcome of a call on member with the breaker and the
+// pool
+func (m *Manager) release(parent context.Context, member *poolMember, err error) {
+	m.breaker.record(parent, err)
+	if m.pool != nil {
+		m.pool.release(parent, member, err)
+	}
+}
+
+// acquire returns the next healthy member, round-robin
+func (p *pool) acquire() (*poolMember, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for range p.members {
+		member := p.members[p.next%len(p.members)]
+		p.next++
+		if !member.evicted {
+			return member, nil
+		}
+	}
+	return nil, errNoHealthyMember
+}
+
+// release records the outcome of a call made under parent on member.
+// Failures caused by the caller's own context are ignored.
+func (p *pool) release(parent context.Context, member *poolMember, err error) {
+	if err != nil && parent.Err() != nil {
+		return
+	}
+	p.mu.Lock()
+	if err == nil {
+		member.failures = 0
+		p.mu.Unlock()
+		return
+	}
+	member.failures++
+	check := member.failures >= maxMemberFailures && p.healthyLocked() > 1
+	p.mu.Unlock()
+	if check {
+		p.checkHealth(member)
+	}
+}
+
+// checkHealth evicts member if its service doesn't answer a health check
+func (p *pool) checkHealth(member *poolMember) {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+	healthy := false
+	if client := member.nlp.GetClient(); client != nil {
+		_, err := client.Health(ctx)
+		healthy = err == nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if healthy {
+		member.failures = 0
+		return
+	}
+	if member.evicted || p.healthyLocked() <= 1 {
+		return
+	}
+	member.evicted = true
+	p.metrics.SetGauge(MetricPoolHealthy, float64(p.healthyLocked()))
+	go member.nlp.Close()
+}
+
+// healthyLocked returns the number of members not evicted; p.mu must be held
+func (p *pool) healthyLocked() int {
+	n := 0
+	for _, member := range p.members {
+		if !member.evicted {
+			n++
+		}
+	}
+	return n
+}
+
+// close releases every member and returns the first error
+func (p *pool) close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var firstErr error
+	for _, member := range p.members {
+		if member.evicted {
+			continue
+		}
+		if err := member.nlp.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// ThaiToRomanBatch transliterates texts concurrently, with one worker per
+// pool member. Results are returned in input order; on error the first
+// error is returned along with whatever results were produced.
+func (m *Manager) ThaiToRomanBatch(ctx context.Context, texts []string) ([]string, error) {
+	detailed, err := m.ThaiToRomanBatchDetailed(ctx, texts)
+	results := make([]string, len(detailed))
+	for i, r := range detailed {
+		results[i] = r.Roman
+	}
+	return results, err
+}
+
+// BatchResult is the romanization of one text of ThaiToRomanBatchDetailed
+type BatchResult = TextResult
+
+// ThaiToRomanBatchDetailed is ThaiToRomanBatch, also reporting for each
+// text the pieces of Thai dropped from its romanization and warnings
+func (m *Manager) ThaiToRomanBatchDetailed(ctx context.Context, texts []string) ([]BatchResult, error) {
+	results := make([]BatchResult, len(texts))
+	workers := m.poolSize
+	if workers < 1 {
+		workers = 1
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				res, err := m.thaiToRoman(ctx, texts[i])
+				if err != nil {
+					once.Do(func() { firstErr = err })
+					continue
+				}
+				results[i] = res
+			}
+		}()
+	}
+	for i := range texts {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results, firstErr
+}
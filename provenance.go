@@ -0,0 +1,81 @@
+package paiboonizer
+
+import (
+	"path"
+	"sort"
+)
+
+// License tags of the embedded dictionary files. A redistributable build
+// has to drop the entries whose license its distribution terms don't allow.
+const (
+	// LicensePaiboonVocab covers the vocabulary lists of the official
+	// Paiboon dictionaries (csv/), redistributed under their publisher's terms
+	LicensePaiboonVocab = "paiboon-vocab"
+	// LicenseGPL3 covers data made for this project (opus_dictionary.tsv),
+	// released under the GPL like the code
+	LicenseGPL3 = "GPL-3.0"
+)
+
+// opusDictionaryFile is the embedded file of the Opus dictionary
+const opusDictionaryFile = "opus_dictionary.tsv"
+
+// EntryProvenance tells where a dictionary entry comes from
+type EntryProvenance struct {
+	File    string // Embedded file the entry was read from (csv/a1.txt, opus_dictionary.tsv)
+	Source  string // "official" or "opus", as in Entry
+	License string // License tag of the file (LicensePaiboonVocab, ...)
+}
+
+// SourceFile is an embedded dictionary file with the number of entries
+// taken from it
+type SourceFile struct {
+	EntryProvenance
+	Entries int
+}
+
+// officialFiles maps official dictionary entries to the vocab file they
+// were read from; a word listed in several files keeps the last one, whose
+// romanization is the one in the dictionary
+var officialFiles = make(map[string]string)
+
+// licenseOf returns the license tag of an embedded dictionary file
+func licenseOf(file string) string {
+	if path.Dir(file) == "csv" {
+		return LicensePaiboonVocab
+	}
+	return LicenseGPL3
+}
+
+// EntrySource returns the provenance of the dictionary entry LookupDictionary
+// uses for thai: the official entry if any, else the Opus one. Returns
+// false for words in neither dictionary.
+func EntrySource(thai string) (EntryProvenance, bool) {
+	ensureDictionaryLoaded()
+	if file, ok := officialFiles[thai]; ok {
+		return EntryProvenance{File: file, Source: "official", License: licenseOf(file)}, true
+	}
+	if _, ok := opusDictionary[thai]; ok {
+		return EntryProvenance{File: opusDictionaryFile, Source: "opus", License: licenseOf(opusDictionaryFile)}, true
+	}
+	return EntryProvenance{}, false
+}
+
+// DictionarySources lists the embedded dictionary files with their license
+// and entry count, sorted by file, so that a build can be audited for the
+// licenses it ships
+func DictionarySources() []SourceFile {
+	ensureDictionaryLoaded()
+	counts := make(map[string]int)
+	for _, file := range officialFiles {
+		counts[file]++
+	}
+	sources := make([]SourceFile, 0, len(counts)+1)
+	for file, n := range counts {
+		sources = append(sources, SourceFile{EntryProvenance{file, "official", licenseOf(file)}, n})
+	}
+	if len(opusDictionary) > 0 {
+		sources = append(sources, SourceFile{EntryProvenance{opusDictionaryFile, "opus", licenseOf(opusDictionaryFile)}, len(opusDictionary)})
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].File < sources[j].File })
+	return sources
+}
@@ -0,0 +1,302 @@
+package paiboonizer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/tassa-yoniso-manasi-karoto/paiboonizer/corpustest"
+)
+
+// LintIssue is an official dictionary entry whose romanization looks wrong
+type LintIssue struct {
+	Thai    string
+	Roman   string
+	Problem string
+}
+
+// LintDictionary checks every official dictionary entry for romanizations
+// that can't be right: empty ones, ones containing Thai letters and
+// syllables breaking Thai phonotactics (see CheckPhonotactics). Template
+// entries (<sth>) and affixes are skipped. Issues are sorted by Thai.
+func LintDictionary() []LintIssue {
+	ensureDictionaryLoaded()
+	issues := []LintIssue{}
+	for thai, value := range dictionary {
+		roman := value.Roman
+		if specialMarkerRegex.MatchString(thai) || strings.HasSuffix(thai, "-") {
+			continue
+		}
+		roman = norm.NFC.String(strings.TrimSpace(roman))
+		switch {
+		case roman == "":
+			issues = append(issues, LintIssue{thai, roman, "empty romanization"})
+		case containsThai(roman):
+			issues = append(issues, LintIssue{thai, roman, "Thai letters in romanization"})
+		default:
+			for _, w := range strings.Fields(roman) {
+				for _, p := range CheckPhonotactics(strings.Trim(w, ".,!?()")) {
+					issues = append(issues, LintIssue{thai, roman, p.String()})
+				}
+			}
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Thai != issues[j].Thai {
+			return issues[i].Thai < issues[j].Thai
+		}
+		return issues[i].Problem < issues[j].Problem
+	})
+	return issues
+}
+
+// QualityConfig sets what QualityGate runs and the thresholds it enforces
+type QualityConfig struct {
+	// MaxLintIssues is the number of LintDictionary issues tolerated,
+	// negative to skip the lint
+	MaxLintIssues int
+	// DictionarySample is the number of dictionary entries tested with
+	// pure rules, spread evenly over the dictionary; 0 tests them all
+	DictionarySample int
+	// MinDictionaryAccuracy is the required dictionary test accuracy in percent
+	MinDictionaryAccuracy float64
+	// MinCategoryAccuracy maps orthographic categories (CategoryHoLead,
+	// ...) to their required accuracy in percent, measured on all the
+	// dictionary entries of the category (see RunCategoryTest)
+	MinCategoryAccuracy map[string]float64
+	// CorpusPath is a parallel corpus TSV file (see WriteCorpusTSV); the
+	// corpus test is skipped if empty or if the file doesn't exist
+	CorpusPath string
+	// MinCorpusAccuracy is the required share of romanized corpus words
+	// reproduced by TransliterateText, in percent
+	MinCorpusAccuracy float64
+	// ExpectationsPath is an expectations file listing the dictionary
+	// entries and corpus lines known to fail (see ReadExpectations). When
+	// set, any failure it doesn't list fails the gate whatever the
+	// thresholds; a missing file lists nothing.
+	ExpectationsPath string
+}
+
+// DefaultQualityConfig returns thresholds a little below the current
+// figures, so that the gate catches regressions without failing on noise.
+// The category figures are measured on every entry of their category and
+// don't vary between runs, so their minimums sit about a point below the
+// figures and must be raised along with any change improving them. The
+// dictionary figure, 82.1% on a 1000-entry sample, moves as entries are
+// added and the sample shifts: its minimum is two standard errors of
+// such a sample (sqrt(0.82*0.18/1000), 1.2 points) below it.
+func DefaultQualityConfig() QualityConfig {
+	return QualityConfig{
+		MaxLintIssues:         10,
+		DictionarySample:      1000,
+		MinDictionaryAccuracy: 79.7,
+		MinCategoryAccuracy: map[string]float64{
+			CategoryHoLead:    82, // 83.37
+			CategoryOLead:     85, // 86.96
+			CategoryCluster:   76, // 77.70
+			CategoryUea:       74, // 75.98
+			CategoryIa:        71, // 72.20
+			CategorySilent:    43, // 44.83
+			CategoryRoHan:     56, // 57.41
+			CategoryRue:       65, // 66.67
+			CategoryIndicLoan: 67, // 68.36
+		},
+		MinCorpusAccuracy: 60,
+	}
+}
+
+// CorpusAccuracy is the result of the corpus test of QualityGate
+type CorpusAccuracy struct {
+	Lines    int
+	Words    int
+	Correct  int
+	Accuracy float64 // Percentage of words reproduced
+}
+
+// QualityReport details what QualityGate measured
+type QualityReport struct {
+	Lint       []LintIssue        // nil if skipped
+	Dictionary DictTestResults    // Pure rules on the sample
+	Categories []CategoryAccuracy // nil if no category minimum is set
+	Corpus     *CorpusAccuracy    // nil if skipped
+	Failures   []string           // Thresholds missed and errors, empty on success
+	// Expectations compares the failures with the expectations file, nil
+	// without one
+	Expectations *ExpectationCheck
+}
+
+// String returns a summary of the report, one line per check
+func (r QualityReport) String() string {
+	var sb strings.Builder
+	if r.Lint != nil {
+		fmt.Fprintf(&sb, "Dictionary lint: %d issues\n", len(r.Lint))
+	}
+	fmt.Fprintf(&sb, "Dictionary test: %.2f%% (%d/%d)\n", r.Dictionary.Accuracy, r.Dictionary.Passed, r.Dictionary.Total)
+	for _, c := range r.Categories {
+		fmt.Fprintf(&sb, "  %s: %.2f%% (%d/%d)\n", c.Category, c.Accuracy, c.Passed, c.Total)
+	}
+	if r.Corpus != nil {
+		fmt.Fprintf(&sb, "Corpus test: %.2f%% (%d/%d words, %d lines)\n", r.Corpus.Accuracy, r.Corpus.Correct, r.Corpus.Words, r.Corpus.Lines)
+	}
+	if c := r.Expectations; c != nil {
+		fmt.Fprintf(&sb, "Expectations: %d known failures, %d new, %d fixed\n", c.Known, len(c.New), len(c.Fixed))
+		for _, f := range c.Fixed {
+			fmt.Fprintf(&sb, "  fixed: %s\n", f)
+		}
+	}
+	for _, f := range r.Failures {
+		fmt.Fprintf(&sb, "FAIL: %s\n", f)
+	}
+	return sb.String()
+}
+
+// QualityGate runs the dictionary lint, the pure rules dictionary test on
+// a sample and per orthographic category, and the corpus test when a
+// corpus is present, and reports whether all of them meet the thresholds
+// of cfg. It needs neither pythainlp nor network access, so CI and
+// release scripts can call it directly.
+func QualityGate(cfg QualityConfig) (bool, QualityReport) {
+	report := QualityReport{}
+	if cfg.MaxLintIssues >= 0 {
+		report.Lint = LintDictionary()
+		if len(report.Lint) > cfg.MaxLintIssues {
+			report.Failures = append(report.Failures, fmt.Sprintf("dictionary lint: %d issues, at most %d allowed", len(report.Lint), cfg.MaxLintIssues))
+		}
+	}
+
+	for _, problem := range CheckToneClasses() {
+		report.Failures = append(report.Failures, "tone classes: "+problem)
+	}
+
+	var expectations Expectations
+	if cfg.ExpectationsPath != "" {
+		var err error
+		if expectations, err = loadExpectations(cfg.ExpectationsPath); err != nil {
+			report.Failures = append(report.Failures, fmt.Sprintf("expectations: %v", err))
+		}
+		report.Expectations = newExpectationCheck(expectations)
+	}
+
+	dictOpts := DictTestOptions{Mode: TestModePureRules, Sample: cfg.DictionarySample}
+	if report.Expectations != nil {
+		// Every failure has to be checked against the expectations
+		dictOpts.MaxFailures = -1
+	}
+	report.Dictionary = RunDictionaryTest(dictOpts)
+	if report.Expectations != nil {
+		failed := make(map[string]bool, len(report.Dictionary.Failures))
+		for _, f := range report.Dictionary.Failures {
+			failed[f.Thai] = true
+		}
+		for _, thai := range dictionaryTestKeys(dictOpts) {
+			report.Expectations.check("dictionary", thai, failed[thai], expectations.Dictionary, report.Expectations.Current.Dictionary)
+		}
+	}
+	if report.Dictionary.Accuracy < cfg.MinDictionaryAccuracy {
+		report.Failures = append(report.Failures, fmt.Sprintf("dictionary test: %.2f%% accuracy, %.2f%% required", report.Dictionary.Accuracy, cfg.MinDictionaryAccuracy))
+	}
+
+	if len(cfg.MinCategoryAccuracy) > 0 {
+		report.Categories = RunCategoryTest(TestModePureRules)
+		measured := make(map[string]CategoryAccuracy)
+		for _, c := range report.Categories {
+			measured[c.Category] = c
+		}
+		categories := make([]string, 0, len(cfg.MinCategoryAccuracy))
+		for c := range cfg.MinCategoryAccuracy {
+			categories = append(categories, c)
+		}
+		sort.Strings(categories)
+		for _, c := range categories {
+			acc, ok := measured[c]
+			switch {
+			case !ok:
+				report.Failures = append(report.Failures, fmt.Sprintf("category %s: no dictionary entries", c))
+			case acc.Accuracy < cfg.MinCategoryAccuracy[c]:
+				report.Failures = append(report.Failures, fmt.Sprintf("category %s: %.2f%% accuracy, %.2f%% required", c, acc.Accuracy, cfg.MinCategoryAccuracy[c]))
+			}
+		}
+	}
+
+	if cfg.CorpusPath != "" {
+		corpus, lines, err := corpusAccuracy(cfg.CorpusPath)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			report.Failures = append(report.Failures, fmt.Sprintf("corpus test: %v", err))
+		default:
+			report.Corpus = &corpus
+			if report.Expectations != nil {
+				for _, l := range lines {
+					report.Expectations.check("corpus", l.key, l.failed, expectations.Corpus, report.Expectations.Current.Corpus)
+				}
+			}
+			if corpus.Accuracy < cfg.MinCorpusAccuracy {
+				report.Failures = append(report.Failures, fmt.Sprintf("corpus test: %.2f%% accuracy, %.2f%% required", corpus.Accuracy, cfg.MinCorpusAccuracy))
+			}
+		}
+	}
+	if c := report.Expectations; c != nil && len(c.New) > 0 {
+		report.Failures = append(report.Failures, fmt.Sprintf("%d failures not in %s: %s", len(c.New), cfg.ExpectationsPath, strings.Join(c.New[:min(len(c.New), 10)], ", ")))
+	}
+	return len(report.Failures) == 0, report
+}
+
+// loadExpectations reads the expectations file at path, empty if it
+// doesn't exist
+func loadExpectations(path string) (Expectations, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Expectations{}, nil
+	}
+	if err != nil {
+		return Expectations{}, err
+	}
+	defer f.Close()
+	e, err := ReadExpectations(f)
+	if err != nil {
+		return Expectations{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return e, nil
+}
+
+// corpusLine is the outcome of a corpus line in the corpus test
+type corpusLine struct {
+	key    string // See CorpusKey
+	failed bool   // Not every romanized word was reproduced
+}
+
+// corpusAccuracy romanizes the Thai side of the corpus TSV file at path and
+// counts the romanized words reproduced in order, scored as corpustest
+// does. The outcome of each line is returned in file order.
+func corpusAccuracy(path string) (CorpusAccuracy, []corpusLine, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return CorpusAccuracy{}, nil, err
+	}
+	defer f.Close()
+	pairs, err := ReadCorpusTSV(f)
+	if err != nil {
+		return CorpusAccuracy{}, nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	result := CorpusAccuracy{Lines: len(pairs)}
+	lines := make([]corpusLine, 0, len(pairs))
+	for _, p := range pairs {
+		expected := corpustest.SplitWords(corpustest.Normalize(p.Roman))
+		got := corpustest.SplitWords(corpustest.Normalize(TransliterateText(p.Thai, DefaultOptions())))
+		correct := corpustest.CountMatchingWords(expected, got)
+		result.Words += len(expected)
+		result.Correct += correct
+		lines = append(lines, corpusLine{CorpusKey(p.Source, p.Line), correct < len(expected)})
+	}
+	if result.Words > 0 {
+		result.Accuracy = float64(result.Correct) * 100 / float64(result.Words)
+	}
+	return result, lines, nil
+}
@@ -0,0 +1,59 @@
+package paiboonizer
+
+import (
+	"golang.org/x/text/unicode/norm"
+)
+
+// SyllableReading is a romanization of a Thai syllable with the sources
+// that give it
+type SyllableReading struct {
+	Roman string
+	// Sources are, in priority order: "official dictionary", "opus
+	// dictionary", "special case", "final cluster exception", "syllable
+	// dictionary", "vowel patterns", "comprehensive parser" and "syllable
+	// parser"
+	Sources []string
+}
+
+// SyllableReadings returns every reading the engine knows for a Thai
+// syllable: its entries in the lookup tables and the output of each rule
+// engine, so that lexicographers can check a new dictionary entry against
+// what the engine believes. Readings are in NFC, each listed once with all
+// its sources, ordered by their first source. Sources without a reading
+// are left out.
+func SyllableReadings(thaiSyllable string) []SyllableReading {
+	ensureDictionaryLoaded()
+	syl := norm.NFC.String(thaiSyllable)
+	var readings []SyllableReading
+	add := func(roman, source string) {
+		if roman == "" {
+			return
+		}
+		roman = norm.NFC.String(roman)
+		for i := range readings {
+			if readings[i].Roman == roman {
+				readings[i].Sources = append(readings[i].Sources, source)
+				return
+			}
+		}
+		readings = append(readings, SyllableReading{roman, []string{source}})
+	}
+	lookup := func(table map[string]string, source string) {
+		if roman, ok := table[syl]; ok {
+			add(roman, source)
+		}
+	}
+	if roman, ok := dictionary.roman(syl); ok {
+		add(roman, "official dictionary")
+	}
+	if roman, ok := opusDictionary.roman(syl); ok {
+		add(roman, "opus dictionary")
+	}
+	lookup(specialCasesGlobal, "special case")
+	lookup(finalClusterExceptions, "final cluster exception")
+	lookup(syllableDict, "syllable dictionary")
+	add(improvedTransliterate(syl), "vowel patterns")
+	add(buildPaiboonFromSyllable(parseThaiSyllable(syl)), "comprehensive parser")
+	add(transliterateSyllable(syl), "syllable parser")
+	return readings
+}
@@ -0,0 +1,185 @@
+package paiboonizer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// overridesFile is the overrides file a Transliterator reads its
+// Overrides from, as last loaded or saved. path is empty for overrides
+// edited without a file.
+type overridesFile struct {
+	path      string
+	overrides Overrides
+	modTime   time.Time
+	size      int64
+	// fingerprint is the OptionsFingerprint of the options with overrides
+	fingerprint string
+}
+
+// store swaps in the overrides o, read from or saved to path as info
+// describes; info is nil for overrides edited without a file. The caller
+// holds edits.
+func (t *Transliterator) store(path string, o Overrides, info os.FileInfo) {
+	f := &overridesFile{path: path, overrides: o}
+	if info != nil {
+		f.modTime, f.size = info.ModTime(), info.Size()
+	}
+	opts := t.opts
+	opts.Overrides = o
+	f.fingerprint = OptionsFingerprint(opts)
+	t.overrides.Store(f)
+}
+
+// LoadOverridesFile reads the overrides file at path (see ReadOverrides)
+// and uses it in place of Options.Overrides from then on. Reload and Watch
+// read it again after it changes, so a user dictionary can be edited
+// without restarting the process. On error the overrides in use are
+// kept.
+func (t *Transliterator) LoadOverridesFile(path string) error {
+	t.edits.Lock()
+	defer t.edits.Unlock()
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	o, err := LoadOverrides(path)
+	if err != nil {
+		return err
+	}
+	t.store(path, o, info)
+	return nil
+}
+
+// Reload reads the overrides file of LoadOverridesFile again and swaps it
+// in atomically: calls in progress finish with the entries they started
+// with. Does nothing if no file was loaded. On error, such as a file
+// saved half-written, the overrides in use are kept.
+func (t *Transliterator) Reload() error {
+	f := t.overrides.Load()
+	if f == nil || f.path == "" {
+		return nil
+	}
+	return t.LoadOverridesFile(f.path)
+}
+
+// SetOverride sets the override entry of key, written as in an overrides
+// file (see Overrides.Set), so that a running service can be corrected
+// live. With a file loaded by LoadOverridesFile the entries are saved to
+// it first, without its comments; on error nothing changes.
+func (t *Transliterator) SetOverride(key, roman string) error {
+	t.edits.Lock()
+	defer t.edits.Unlock()
+	o, err := t.Options().Overrides.Set(key, roman)
+	if err != nil {
+		return err
+	}
+	return t.storeOverrides(o)
+}
+
+// RemoveOverride removes the override entry of key as SetOverride sets
+// one. Returns false if there is no such entry.
+func (t *Transliterator) RemoveOverride(key string) (bool, error) {
+	t.edits.Lock()
+	defer t.edits.Unlock()
+	o, ok := t.Options().Overrides.Remove(key)
+	if !ok {
+		return false, nil
+	}
+	return true, t.storeOverrides(o)
+}
+
+// storeOverrides saves o to the overrides file, if any, and swaps it in.
+// The caller holds edits.
+func (t *Transliterator) storeOverrides(o Overrides) error {
+	f := t.overrides.Load()
+	if f == nil || f.path == "" {
+		t.store("", o, nil)
+		return nil
+	}
+	info, err := saveOverrides(f.path, o)
+	if err != nil {
+		return err
+	}
+	t.store(f.path, o, info)
+	return nil
+}
+
+// saveOverrides writes o to path through a temporary file renamed over
+// it, so that readers never see it half-written. The file keeps its
+// permissions, 0644 when it is new, rather than the 0600 of CreateTemp.
+func saveOverrides(path string, o Overrides) (os.FileInfo, error) {
+	perm := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".overrides-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := WriteOverrides(tmp, o); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, err
+	}
+	return os.Stat(path)
+}
+
+// Watch checks the overrides file of LoadOverridesFile every interval and
+// reloads it when its modification time or size changed, until ctx ends.
+// onReload, when non-nil, is called after every reload with its error; a
+// file that fails to load is tried again once it changes again. A file
+// that can't be stat'ed is reported once, until the error changes or the
+// file is back. It blocks; run it in its own goroutine.
+func (t *Transliterator) Watch(ctx context.Context, interval time.Duration, onReload func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var seen *overridesFile
+	// statErr is the error of the last os.Stat, reported already
+	var statErr string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		f := t.overrides.Load()
+		if f == nil || f.path == "" {
+			continue
+		}
+		if seen == nil || seen.path != f.path {
+			seen, statErr = f, ""
+		}
+		info, err := os.Stat(f.path)
+		switch {
+		case err != nil && err.Error() == statErr:
+			continue
+		case err != nil:
+			// The file is reloaded whatever its state once it is back
+			seen = &overridesFile{path: f.path}
+			statErr = err.Error()
+		case info.ModTime().Equal(seen.modTime) && info.Size() == seen.size:
+			statErr = ""
+			continue
+		default:
+			seen = &overridesFile{path: f.path, modTime: info.ModTime(), size: info.Size()}
+			statErr = ""
+			err = t.LoadOverridesFile(f.path)
+		}
+		if onReload != nil {
+			onReload(err)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package paiboonizer
+
+import (
+	"strings"
+
+	"github.com/tassa-yoniso-manasi-karoto/paiboonizer/thai"
+)
+
+// respellingScheme is the "thai" scheme: the Thai reading respelling
+// (คำอ่าน) of the romanization, as ThaiRespelling writes it without
+// rewriting the separators
+var respellingScheme = Scheme{
+	Name:     "thai",
+	Syllable: respellSyllable,
+}
+
+// ThaiRespelling renders a romanization as the Thai reading respelling
+// dictionaries print next to a headword (สามารถ sǎa-mâat → สา-มาด): each
+// syllable spelled the way it sounds, with the plainest consonant and
+// tone mark giving its tone, and a hyphen between syllables. Text that
+// isn't a Paiboon syllable, such as syllables run together without a
+// separator (sàwàtdii), is left as is. It is the "thai" Scheme with
+// hyphens as Separator.
+func ThaiRespelling(roman string) string {
+	return respellingScheme.Render(Separator{Mark: MarkHyphen}.Apply(roman))
+}
+
+// respellMid are the mid class consonants by Paiboon initial
+var respellMid = map[string]string{"": "อ", "g": "ก", "j": "จ", "d": "ด", "dt": "ต", "b": "บ", "bp": "ป"}
+
+// respellPaired are the initials written with a high class consonant or
+// its low class pair, as {high, low}
+var respellPaired = map[string][2]string{
+	"k": {"ข", "ค"}, "ch": {"ฉ", "ช"}, "t": {"ถ", "ท"}, "p": {"ผ", "พ"},
+	"f": {"ฝ", "ฟ"}, "s": {"ส", "ซ"}, "h": {"ห", "ฮ"},
+}
+
+// respellSonorants are the low class consonants without a high class
+// pair, led by ห to take the tones of the high class
+var respellSonorants = map[string]string{"ng": "ง", "n": "น", "m": "ม", "y": "ย", "r": "ร", "l": "ล", "w": "ว"}
+
+// respellClusterSecond spells the second consonant of a cluster (gr, bpl,
+// kw)
+var respellClusterSecond = map[byte]string{'r': "ร", 'l': "ล", 'w': "ว"}
+
+// respellFinals spells the finals with their most common consonant
+var respellFinals = map[string]string{"k": "ก", "t": "ด", "p": "บ", "m": "ม", "n": "น", "ng": "ง"}
+
+// respellVowel is the spelling of a vowel around its initial: the vowel
+// sign written before it, the sign written over or under it, which the
+// tone mark follows, and the signs written after it
+type respellVowel struct{ before, over, after string }
+
+// respellOpen spells the vowels of open syllables
+var respellOpen = map[string]respellVowel{
+	"a": {"", "", "ะ"}, "aa": {"", "", "า"}, "i": {"", "ิ", ""}, "ii": {"", "ี", ""},
+	"ʉ": {"", "ึ", ""}, "ʉʉ": {"", "ื", "อ"}, "u": {"", "ุ", ""}, "uu": {"", "ู", ""},
+	"e": {"เ", "", "ะ"}, "ee": {"เ", "", ""}, "ɛ": {"แ", "", "ะ"}, "ɛɛ": {"แ", "", ""},
+	"o": {"โ", "", "ะ"}, "oo": {"โ", "", ""}, "ɔ": {"เ", "", "าะ"}, "ɔɔ": {"", "", "อ"},
+	"ə": {"เ", "", "อะ"}, "əə": {"เ", "", "อ"}, "ia": {"เ", "ี", "ยะ"}, "iia": {"เ", "ี", "ย"},
+	"ʉa": {"เ", "ื", "อะ"}, "ʉʉa": {"เ", "ื", "อ"}, "ua": {"", "ั", "วะ"}, "uua": {"", "ั", "ว"},
+}
+
+// respellClosed spells the vowels of syllables closed by a final or a
+// glide. Mai taikhu (็) marks the short vowels written without their own
+// sign and is dropped under a tone mark.
+var respellClosed = map[string]respellVowel{
+	"a": {"", "ั", ""}, "aa": {"", "", "า"}, "i": {"", "ิ", ""}, "ii": {"", "ี", ""},
+	"ʉ": {"", "ึ", ""}, "ʉʉ": {"", "ื", ""}, "u": {"", "ุ", ""}, "uu": {"", "ู", ""},
+	"e": {"เ", "็", ""}, "ee": {"เ", "", ""}, "ɛ": {"แ", "็", ""}, "ɛɛ": {"แ", "", ""},
+	"o": {"", "", ""}, "oo": {"โ", "", ""}, "ɔ": {"", "็", "อ"}, "ɔɔ": {"", "", "อ"},
+	"ə": {"เ", "ิ", ""}, "əə": {"เ", "ิ", ""}, "ia": {"เ", "ี", "ย"}, "iia": {"เ", "ี", "ย"},
+	"ʉa": {"เ", "ื", "อ"}, "ʉʉa": {"เ", "ื", "อ"}, "ua": {"", "", "ว"}, "uua": {"", "", "ว"},
+}
+
+// respellSyllable spells a Paiboon syllable in Thai
+func respellSyllable(initial, vowel, final, tone string) string {
+	// A trailing i, o or u after another vowel letter is a glide, written
+	// ย or ว: aai, eo, uuai
+	glide := ""
+	if n := len(vowel); n > 1 && strings.ContainsRune("iou", rune(vowel[n-1])) && vowel[n-2] != vowel[n-1] {
+		glide = map[byte]string{'i': "ย", 'o': "ว", 'u': "ว"}[vowel[n-1]]
+		vowel = vowel[:n-1]
+	}
+	closing := glide
+	if f, ok := respellFinals[final]; ok {
+		closing = f
+	}
+	v, ok := respellOpen[vowel]
+	if closing != "" {
+		v, ok = respellClosed[vowel]
+	}
+	if !ok {
+		return ""
+	}
+	long := strings.Count(vowel, string([]rune(vowel)[0])) > 1
+	dead := final == "k" || final == "t" || final == "p" || closing == "" && !long
+	// The short a spelled with its own letters: ไ-, เ-า, -ำ
+	switch {
+	case vowel == "a" && glide == "ย":
+		v, closing = respellVowel{"ไ", "", ""}, ""
+	case vowel == "a" && glide == "ว":
+		v, closing = respellVowel{"เ", "", "า"}, ""
+	case vowel == "a" && final == "m":
+		v, closing = respellVowel{"", "", "ำ"}, ""
+	case vowel == "əə" && glide == "ย":
+		v = respellVowel{"เ", "", ""}
+	}
+	consonants, mark, ok := respellInitial(initial, tone, dead, long)
+	if !ok {
+		return ""
+	}
+	over := v.over
+	if over == "็" && mark != "" {
+		over = ""
+	}
+	return v.before + consonants + over + mark + v.after + closing
+}
+
+// respellInitial spells initial with the consonant class and tone mark
+// that give tone to a syllable, live or dead. Returns false for initials
+// Thai doesn't spell.
+func respellInitial(initial, tone string, dead, long bool) (consonants, mark string, ok bool) {
+	head, second := initial, ""
+	if n := len(initial); n > 1 && initial != "ng" && respellClusterSecond[initial[n-1]] != "" {
+		head, second = initial[:n-1], respellClusterSecond[initial[n-1]]
+	}
+	if letter, ok := respellMid[head]; ok {
+		return letter + second, toneMark(thai.ClassMid, tone, dead, long), true
+	}
+	// Low and rising tones are written on the high class, the others on
+	// the low class
+	high := tone == "rising" || tone == "low"
+	if pair, ok := respellPaired[head]; ok {
+		if high {
+			return pair[0] + second, toneMark(thai.ClassHigh, tone, dead, long), true
+		}
+		return pair[1] + second, toneMark(thai.ClassLow, tone, dead, long), true
+	}
+	letter, ok := respellSonorants[head]
+	if !ok || second != "" {
+		return "", "", false
+	}
+	if high {
+		return "ห" + letter, toneMark(thai.ClassHigh, tone, dead, long), true
+	}
+	return letter, toneMark(thai.ClassLow, tone, dead, long), true
+}
+
+// toneMark returns the tone mark a consonant of class needs to give tone
+// to a live or dead syllable; "" when the tone is the one read without a
+// mark or can't be written on that class
+func toneMark(class thai.Class, tone string, dead, long bool) string {
+	switch {
+	case class == thai.ClassMid && dead && tone == "low":
+		return ""
+	case class == thai.ClassMid:
+		return map[string]string{"low": "่", "falling": "้", "high": "๊", "rising": "๋"}[tone]
+	case class == thai.ClassHigh && tone == "falling":
+		return "้"
+	case class == thai.ClassHigh && tone == "low" && !dead:
+		return "่"
+	case class == thai.ClassHigh:
+		return ""
+	case tone == "falling" && !(dead && long):
+		return "่"
+	case tone == "high" && !(dead && !long):
+		return "้"
+	}
+	return ""
+}
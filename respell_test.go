@@ -0,0 +1,20 @@
+package paiboonizer
+
+import "testing"
+
+func TestThaiRespelling(t *testing.T) {
+	tests := []struct {
+		name, roman, want string
+	}{
+		{"hyphenated syllables", "sǎa-mâat", "สา-มาด"},
+		{"syllables without a separator", "sàwàtdii", "sàwàtdii"},
+		{"words", "sà~wàt-dii kráp", "สะ-หวัด-ดี ครับ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ThaiRespelling(tt.roman); got != tt.want {
+				t.Errorf("ThaiRespelling(%q) = %q, want %q", tt.roman, got, tt.want)
+			}
+		})
+	}
+}
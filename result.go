@@ -0,0 +1,157 @@
+package paiboonizer
+
+import (
+	"golang.org/x/text/unicode/norm"
+)
+
+// RomanSource tells where the romanization of a syllable came from
+type RomanSource int
+
+const (
+	SourceDictionary         RomanSource = iota // Official or Opus word dictionary
+	SourceSyllableDictionary                    // Syllable dictionary (LookupSyllable)
+	SourceSpecialCase                           // Special case table (LookupSpecialCase)
+	SourceRules                                 // Syllable rules
+)
+
+// String returns the source name
+func (s RomanSource) String() string {
+	switch s {
+	case SourceDictionary:
+		return "dictionary"
+	case SourceSyllableDictionary:
+		return "syllable dictionary"
+	case SourceSpecialCase:
+		return "special case"
+	}
+	return "rules"
+}
+
+// SyllableResult is a syllable of a Result
+type SyllableResult struct {
+	// Thai is the spelling of the syllable, empty when the syllables of a
+	// multi-syllable dictionary or special case entry can't be matched one
+	// to one with the Thai (see ExtractSyllables)
+	Thai  string
+	Roman string // Empty if the rules couldn't romanize the syllable
+	Tone  string // mid, low, falling, high or rising; empty without Roman
+	// ToneNumber is Tone in the order of the Paiboon chart: 0 mid, 1 low,
+	// 2 falling, 3 high, 4 rising; -1 without Roman
+	ToneNumber int
+	Source     RomanSource
+}
+
+// Result is a word with the breakdown of its romanization, for learner
+// tools that show each syllable with its tone and origin
+type Result struct {
+	Word  string // The word, in NFC
+	Roman string
+	// EntryID identifies the dictionary entry of the word (see EntryID),
+	// empty for words left to the rules
+	EntryID   string
+	Syllables []SyllableResult
+}
+
+// Transliterate romanizes a single word and returns it syllable by
+// syllable. Dictionary words are split along the separators of their
+// entry; other words are romanized as EngineComprehensive does, so Roman
+// matches ComprehensiveTransliterate, and each syllable tells whether it
+// came from the syllable dictionary, a special case or the rules.
+func Transliterate(word string) Result {
+	ensureDictionaryLoaded()
+	word = norm.NFC.String(word)
+	if LooksRomanized(word) {
+		return Result{Word: word, Roman: word, Syllables: []SyllableResult{}}
+	}
+	if trans, ok := LookupDictionary(word); ok {
+		trans = norm.NFC.String(trans)
+		return Result{Word: word, Roman: trans, EntryID: entryIDOf(word), Syllables: splitPiece(wordPiece{word, trans, SourceDictionary})}
+	}
+	pieces, _ := comprehensivePieces(word, FailureDrop, JoinDictionary)
+	res := Result{Word: word, Roman: ComprehensiveTransliterate(word), Syllables: []SyllableResult{}}
+	for _, p := range pieces {
+		res.Syllables = append(res.Syllables, splitPiece(p)...)
+	}
+	return res
+}
+
+// splitPiece breaks a romanized piece of a word into its syllables
+func splitPiece(p wordPiece) []SyllableResult {
+	if p.roman == "" {
+		return []SyllableResult{{Thai: p.thai, ToneNumber: -1, Source: p.source}}
+	}
+	romans := []string{}
+	for _, syl := range romanSyllableSep.Split(p.roman, -1) {
+		if syl != "" {
+			romans = append(romans, syl)
+		}
+	}
+	thais := []string{p.thai}
+	if len(romans) > 1 {
+		thais = thaiSyllables(p.thai, romans)
+	}
+	syllables := make([]SyllableResult, len(romans))
+	for i, roman := range romans {
+		tone := romanTone(roman)
+		syllables[i] = SyllableResult{Thai: thais[i], Roman: roman, Tone: tone, ToneNumber: toneOrder(tone), Source: p.source}
+	}
+	return syllables
+}
+
+// thaiSyllables splits thai into the syllables romanized as romans,
+// matching the romanization of each candidate syllable, then without
+// tones. Returns empty strings when no split matches.
+func thaiSyllables(thai string, romans []string) []string {
+	runes := []rune(thai)
+	for _, match := range []func(a, b string) bool{
+		func(a, b string) bool { return a == b },
+		func(a, b string) bool { return stripRomanTones(a) == stripRomanTones(b) },
+	} {
+		if split, ok := alignThaiSyllables(runes, romans, match); ok {
+			return split
+		}
+	}
+	return make([]string, len(romans))
+}
+
+// alignThaiSyllables splits runes into one syllable per roman, longest
+// syllables first, backtracking when the rest can't be split
+func alignThaiSyllables(runes []rune, romans []string, match func(a, b string) bool) ([]string, bool) {
+	if len(romans) == 0 {
+		return nil, len(runes) == 0
+	}
+	for length := min(len(runes), 10); length > 0; length-- {
+		syl := string(runes[:length])
+		if !startsWithThaiLetter(syl) || !syllableReads(syl, romans[0], match) {
+			continue
+		}
+		if rest, ok := alignThaiSyllables(runes[length:], romans[1:], match); ok {
+			return append([]string{syl}, rest...), true
+		}
+	}
+	return nil, false
+}
+
+// syllableReads reports whether a reading of the Thai syllable (syllable
+// dictionary, special cases or rules) matches roman
+func syllableReads(syl, roman string, match func(a, b string) bool) bool {
+	if trans, ok := syllableDict[syl]; ok && match(norm.NFC.String(trans), roman) {
+		return true
+	}
+	if trans, ok := specialCasesGlobal[syl]; ok && match(norm.NFC.String(trans), roman) {
+		return true
+	}
+	return match(norm.NFC.String(ruleTransliterateSyllable(syl)), roman) ||
+		match(norm.NFC.String(transliterateSyllable(syl)), roman)
+}
+
+// romanTone returns the tone a Paiboon syllable is marked with, mid when
+// it has no tone mark
+func romanTone(syl string) string {
+	for _, r := range norm.NFD.String(syl) {
+		if tone, ok := toneByMark[r]; ok {
+			return tone
+		}
+	}
+	return "mid"
+}
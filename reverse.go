@@ -0,0 +1,223 @@
+package paiboonizer
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// reverseWords maps normalized romanizations to dictionary words
+	reverseWords map[string][]string
+	// reverseSyllables maps romanized syllables to the Thai syllables the
+	// syllable dictionary reads that way
+	reverseSyllables map[string][]string
+	reverseOnce      sync.Once
+)
+
+// romanKey normalizes a romanization for reverse lookup: NFC, lower case,
+// any run of syllable separators (-, ~, space) as a single hyphen
+func romanKey(roman string) string {
+	roman = strings.ToLower(norm.NFC.String(strings.TrimSpace(roman)))
+	return romanSyllableSep.ReplaceAllString(roman, "-")
+}
+
+// loadReverseIndex builds the reverse indexes once
+func loadReverseIndex() {
+	ensureDictionaryLoaded()
+	reverseWords = make(map[string][]string)
+	reverseSyllables = make(map[string][]string)
+	for _, e := range dictionaryEntries() {
+		key := romanKey(e.Roman)
+		reverseWords[key] = append(reverseWords[key], e.Thai)
+	}
+	for thai, roman := range syllableDict {
+		key := romanKey(roman)
+		reverseSyllables[key] = append(reverseSyllables[key], thai)
+	}
+	for _, list := range reverseSyllables {
+		sort.Strings(list)
+	}
+}
+
+// ReverseLookup returns the Thai spellings that romanize to roman: the
+// dictionary words with that romanization first, then, for a single
+// syllable, the Thai syllables the syllable dictionary reads that way.
+// Tones must match; the separators -, ~ and space are interchangeable.
+// Returns nil if nothing matches.
+func ReverseLookup(roman string) []string {
+	reverseOnce.Do(loadReverseIndex)
+	key := romanKey(roman)
+	var candidates []string
+	seen := make(map[string]bool)
+	for _, list := range [][]string{reverseWords[key], reverseSyllables[key]} {
+		for _, thai := range list {
+			if !seen[thai] {
+				seen[thai] = true
+				candidates = append(candidates, thai)
+			}
+		}
+	}
+	return candidates
+}
+
+// RoundTripIssue is a dictionary entry whose romanization doesn't map
+// back to its Thai spelling
+type RoundTripIssue struct {
+	Thai       string
+	Roman      string
+	Syllable   string   // First romanized syllable that doesn't map back
+	Remaining  string   // Thai text left to cover at that syllable
+	Candidates []string // Thai syllables the syllable maps back to
+}
+
+// RoundTripResults contains the results of RunRoundTripTest
+type RoundTripResults struct {
+	Checked int // Entries tested
+	Skipped int // Phrases and entries with non-Thai characters
+	Issues  []RoundTripIssue
+}
+
+// RunRoundTripTest checks that the official dictionary agrees with itself:
+// the Thai spelling of each entry must be among the Thai candidates of its
+// romanization, i.e. it must split into consecutive syllables each of which
+// is a reverse candidate (ReverseLookup) of the matching romanized
+// syllable or is read that way by the rules. Entries failing this use a
+// reading no other word or rule agrees with and are likely data errors.
+func RunRoundTripTest() RoundTripResults {
+	reverseOnce.Do(loadReverseIndex)
+	results := RoundTripResults{}
+	ruleReading := make(map[string]string)
+
+	sortedKeys := make([]string, 0, len(dictionary))
+	for k := range dictionary {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, thai := range sortedKeys {
+		if !isThaiWord(thai) {
+			results.Skipped++
+			continue
+		}
+		results.Checked++
+		roman := norm.NFC.String(dictionary[thai].Roman)
+		syllables := romanSyllableSep.Split(strings.TrimSpace(roman), -1)
+		runes := []rune(thai)
+
+		// reachable holds the Thai positions the syllables so far can end
+		// at; the last syllable must end at the end of the word
+		reachable := map[roundTripState]bool{{}: true}
+		for i, syl := range syllables {
+			key := romanKey(syl)
+			next := make(map[roundTripState]bool)
+			for state := range reachable {
+				start := state.pos
+				// ๆ repeats the previous syllable
+				if start < len(runes) && runes[start] == 'ๆ' && i > 0 && romanKey(syllables[i-1]) == key {
+					next[roundTripState{pos: start + 1}] = true
+				}
+				// A consonant read with an implicit a, possibly the final
+				// of the previous syllable read a second time (ทัศนะ
+				// tát-sà~ná). A high class one leads the next syllable as
+				// a silent ห would (สมอง sà~mɔ̌ɔng).
+				for _, at := range []int{start, start - 1} {
+					if at >= 0 && at < len(runes) && (at == start || i > 0) && implicitVowelReading(runes[at]) == key {
+						next[roundTripState{pos: at + 1, lead: highClass[string(runes[at])]}] = true
+					}
+				}
+				for end := start + 1; end <= len(runes) && end-start <= maxSyllableRunes; end++ {
+					span := string(runes[start:end])
+					if state.lead && sonorants[string(runes[start])] {
+						span = "ห" + span
+					}
+					reading, ok := ruleReading[span]
+					if !ok {
+						reading = romanKey(ComprehensiveTransliterate(span))
+						ruleReading[span] = reading
+					}
+					if reading == key || containsString(reverseSyllables[key], span) {
+						next[roundTripState{pos: end}] = true
+					}
+				}
+			}
+			if i == len(syllables)-1 {
+				complete := next[roundTripState{pos: len(runes)}]
+				next = make(map[roundTripState]bool)
+				if complete {
+					next[roundTripState{pos: len(runes)}] = true
+				}
+			}
+			if len(next) == 0 {
+				furthest := 0
+				for state := range reachable {
+					if state.pos > furthest {
+						furthest = state.pos
+					}
+				}
+				results.Issues = append(results.Issues, RoundTripIssue{
+					Thai:       thai,
+					Roman:      roman,
+					Syllable:   syl,
+					Remaining:  string(runes[furthest:]),
+					Candidates: reverseSyllables[key],
+				})
+				break
+			}
+			reachable = next
+		}
+	}
+	return results
+}
+
+// roundTripState is a position in the Thai spelling reached by
+// RunRoundTripTest; lead is set after a high class consonant read with an
+// implicit a, which raises a following sonorant to high class
+type roundTripState struct {
+	pos  int
+	lead bool
+}
+
+// maxSyllableRunes bounds the Thai span RunRoundTripTest tries for one
+// romanized syllable
+const maxSyllableRunes = 8
+
+// isThaiWord reports whether s is a single word written in Thai letters
+func isThaiWord(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !isThaiRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// implicitVowelReading returns the reading of a consonant written without
+// a vowel and pronounced with a short a (สบาย sà~baai, ชนะ chá~ná): the
+// low tone for mid and high class consonants, the high tone for low class
+// ones. Returns "" for anything but a consonant.
+func implicitVowelReading(r rune) string {
+	initial, ok := initialConsonants[string(r)]
+	if !ok || !isConsonantRune(r) {
+		return ""
+	}
+	if lowClass[string(r)] {
+		return initial + "á"
+	}
+	return initial + "à"
+}
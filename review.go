@@ -0,0 +1,92 @@
+package paiboonizer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ReviewItem is a subtitle line whose existing romanization disagrees
+// with the engine
+type ReviewItem struct {
+	CorpusPair        // The aligned lines; Roman is the existing romanization
+	Engine     string // The romanization of the Thai line by the engine
+	// Disagreement is the share of the syllable alignment steps that
+	// aren't matches, from 0 to 1
+	Disagreement float64
+	Alignment    []AlignedSyllable // Existing (expected) against engine (got)
+}
+
+// reviewHeader starts every review list TSV file
+const reviewHeader = "# Paiboonizer review list\n# Format: Source<TAB>Line<TAB>Disagreement<TAB>Thai<TAB>Existing<TAB>Engine<TAB>Differences\n"
+
+// ReviewSubtitles checks an existing romanized subtitle against its Thai
+// original: lines are paired with AlignSubtitles, each Thai line is
+// romanized with opts and the two romanizations are aligned syllable by
+// syllable, case and punctuation ignored. Lines whose disagreement exceeds
+// threshold are returned worst first, for a human to decide which side is
+// wrong; it is meant as much for auditing the reference transliterations
+// as for finding engine errors. Lines AlignSubtitles couldn't pair are
+// returned as rejections.
+func ReviewSubtitles(source string, thaiLines, romanLines []string, threshold float64, opts Options) ([]ReviewItem, []CorpusRejection) {
+	pairs, rejections := AlignSubtitles(source, thaiLines, romanLines)
+	items := []ReviewItem{}
+	for _, p := range pairs {
+		engine := TransliterateText(p.Thai, opts)
+		alignment := AlignSyllables(reviewSyllables(p.Roman), reviewSyllables(engine))
+		if len(alignment) == 0 {
+			continue
+		}
+		mismatches := 0
+		for _, a := range alignment {
+			if a.Op != SyllableMatch {
+				mismatches++
+			}
+		}
+		item := ReviewItem{p, engine, float64(mismatches) / float64(len(alignment)), alignment}
+		if item.Disagreement > threshold {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Disagreement > items[j].Disagreement
+	})
+	return items, rejections
+}
+
+// reviewSyllables lowercases a romanized line and drops its punctuation,
+// which subtitles and the engine write differently, keeping the syllable
+// and word separators AlignSyllables splits on
+func reviewSyllables(roman string) string {
+	roman = strings.ToLower(roman)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '~' || !unicode.IsPunct(r) && !unicode.IsSymbol(r) {
+			return r
+		}
+		return ' '
+	}, roman)
+}
+
+// WriteReviewTSV writes items as a review list TSV file: a commented
+// header, then one row per line with its differences written as
+// "existing→engine" and separated by spaces. Tabs inside fields are
+// replaced with spaces.
+func WriteReviewTSV(w io.Writer, items []ReviewItem) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(reviewHeader)
+	field := func(s string) string { return strings.ReplaceAll(s, "\t", " ") }
+	for _, it := range items {
+		var diffs []string
+		for _, a := range it.Alignment {
+			if a.Op != SyllableMatch {
+				diffs = append(diffs, a.Expected+"→"+a.Got)
+			}
+		}
+		fmt.Fprintf(bw, "%s\t%d\t%.2f\t%s\t%s\t%s\t%s\n", field(it.Source), it.Line, it.Disagreement,
+			field(it.Thai), field(it.Roman), field(it.Engine), field(strings.Join(diffs, " ")))
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,156 @@
+package paiboonizer
+
+import (
+	"strings"
+	"sync"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// phonologyIndex groups the dictionary entries by their parsed sounds
+type phonologyIndex struct {
+	byReading map[string][]Entry // Every syllable, tones included
+	byRhyme   map[string][]Entry // Vowel and final of the last syllable
+	// rhymeEntries counts the entries that contain each syllable rhyme
+	rhymeEntries map[string]int
+	unparsed     []Entry // Entries with a syllable that doesn't parse
+}
+
+var (
+	phonology     phonologyIndex
+	phonologyOnce sync.Once
+)
+
+// paiboonSyllable is a romanized syllable split into its parts
+type paiboonSyllable struct {
+	initial, vowel, final, tone string
+}
+
+// rhyme returns the vowel and final of the syllable
+func (s paiboonSyllable) rhyme() string {
+	return s.vowel + s.final
+}
+
+// parseReading splits a romanized word into parsed syllables; ok is false
+// if any syllable is not a valid Paiboon syllable
+func parseReading(roman string) ([]paiboonSyllable, bool) {
+	roman = strings.TrimSpace(roman)
+	if roman == "" {
+		return nil, false
+	}
+	syllables := []paiboonSyllable{}
+	for _, s := range romanSyllableSep.Split(roman, -1) {
+		initial, vowel, final, tone, ok := SplitPaiboonSyllable(s)
+		if !ok {
+			return nil, false
+		}
+		syllables = append(syllables, paiboonSyllable{initial, vowel, final, tone})
+	}
+	return syllables, true
+}
+
+// readingKey identifies a pronunciation independently of how the syllables
+// were separated in the romanization
+func readingKey(syllables []paiboonSyllable) string {
+	parts := make([]string, len(syllables))
+	for i, s := range syllables {
+		parts[i] = s.initial + "|" + s.vowel + "|" + s.final + "|" + s.tone
+	}
+	return strings.Join(parts, ".")
+}
+
+// loadPhonologyIndex parses every dictionary entry once
+func loadPhonologyIndex() {
+	ensureDictionaryLoaded()
+	phonology = phonologyIndex{
+		byReading:    make(map[string][]Entry),
+		byRhyme:      make(map[string][]Entry),
+		rhymeEntries: make(map[string]int),
+	}
+	for _, e := range dictionaryEntries() {
+		// Templates (ทำ<sth>หาย) and affixes (ความ-) aren't words
+		if specialMarkerRegex.MatchString(e.Thai) || strings.HasSuffix(e.Thai, "-") {
+			continue
+		}
+		syllables, ok := parseReading(e.Roman)
+		if !ok {
+			phonology.unparsed = append(phonology.unparsed, e)
+			continue
+		}
+		key := readingKey(syllables)
+		phonology.byReading[key] = append(phonology.byReading[key], e)
+		last := syllables[len(syllables)-1].rhyme()
+		phonology.byRhyme[last] = append(phonology.byRhyme[last], e)
+		seen := make(map[string]bool)
+		for _, s := range syllables {
+			if !seen[s.rhyme()] {
+				seen[s.rhyme()] = true
+				phonology.rhymeEntries[s.rhyme()]++
+			}
+		}
+	}
+}
+
+// readingOf returns the parsed pronunciation of a Thai word: its dictionary
+// romanization if known, the rules' otherwise
+func readingOf(thai string) ([]paiboonSyllable, bool) {
+	return parseReading(TransliterateWordWithOptions(norm.NFC.String(thai), Options{}))
+}
+
+// FindHomophones returns the dictionary entries pronounced exactly like
+// thai (same syllables and tones) but spelled differently, e.g. ค่า, ข้า
+// and ฆ่า. The result is sorted by Thai spelling.
+func FindHomophones(thai string) []Entry {
+	phonologyOnce.Do(loadPhonologyIndex)
+	syllables, ok := readingOf(thai)
+	if !ok {
+		return nil
+	}
+	return otherEntries(phonology.byReading[readingKey(syllables)], thai)
+}
+
+// FindRhymes returns the dictionary entries whose last syllable has the
+// same vowel and final sound as the last syllable of thai, whatever the
+// initial and tone: the rhyme (สัมผัส) of Thai verse. The word itself is
+// left out; the result is sorted by Thai spelling.
+func FindRhymes(thai string) []Entry {
+	phonologyOnce.Do(loadPhonologyIndex)
+	syllables, ok := readingOf(thai)
+	if !ok {
+		return nil
+	}
+	return otherEntries(phonology.byRhyme[syllables[len(syllables)-1].rhyme()], thai)
+}
+
+// FindIsolatedEntries returns the dictionary entries whose romanization is
+// probably wrong: a syllable doesn't parse as Paiboon, or its vowel and
+// final combination occurs in no other entry of the dictionary.
+func FindIsolatedEntries() []Entry {
+	phonologyOnce.Do(loadPhonologyIndex)
+	isolated := append([]Entry{}, phonology.unparsed...)
+	for _, entries := range phonology.byReading {
+		for _, e := range entries {
+			syllables, _ := parseReading(e.Roman)
+			for _, s := range syllables {
+				if phonology.rhymeEntries[s.rhyme()] == 1 {
+					isolated = append(isolated, e)
+					break
+				}
+			}
+		}
+	}
+	sortEntries(isolated)
+	return isolated
+}
+
+// otherEntries copies entries without the one spelled thai
+func otherEntries(entries []Entry, thai string) []Entry {
+	thai = norm.NFC.String(thai)
+	others := []Entry{}
+	for _, e := range entries {
+		if e.Thai != thai {
+			others = append(others, e)
+		}
+	}
+	return others
+}
@@ -0,0 +1,42 @@
+package paiboonizer
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Pipelines sometimes run a file through romanization twice. The text
+// functions copy text without Thai verbatim, so a second pass changes
+// nothing. The word functions, which would read Latin letters as Thai, and
+// ThaiToRoman return input that LooksRomanized as is; the E functions
+// return ErrAlreadyRomanized for it.
+
+// LooksRomanized reports whether text reads as Paiboon romanization rather
+// than Thai: it has no Thai, and either a word with a Paiboon vowel (ɛ, ɔ,
+// ə, ʉ) or a tone mark over a vowel in at least half of its words (pǒm
+// bpai, kráp). The check is heuristic: text in a language writing tones
+// or accents the same way may pass for romanized.
+func LooksRomanized(text string) bool {
+	if containsThai(text) {
+		return false
+	}
+	words, toned := 0, 0
+	for _, word := range strings.FieldsFunc(norm.NFD.String(text), isWordSeparator) {
+		words++
+		if strings.ContainsAny(word, "ɛɔəʉƐƆƏ") {
+			return true
+		}
+		if strings.ContainsAny(word, "\u0300\u0301\u0302\u030C") {
+			toned++
+		}
+	}
+	return words > 0 && 2*toned >= words
+}
+
+// isWordSeparator reports whether r separates romanized words: anything
+// but letters and the marks over them, "-" and "~" joining syllables
+func isWordSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.Is(unicode.Mn, r) && r != '-' && r != '~'
+}
@@ -0,0 +1,45 @@
+package paiboonizer
+
+import "golang.org/x/text/unicode/norm"
+
+// royalVocabulary holds royal (ราชาศัพท์) and ecclesiastical vocabulary.
+// These words have irregular readings the rule engine can't derive and show
+// up mostly in news subtitles, so the tier is only consulted when
+// Options.RoyalVocabulary is set.
+var royalVocabulary = map[string]string{
+	// Royal verbs
+	"เสวย": "sà~wə̌əi", "ประชวร": "bprà~chuuan", "บรรทม": "ban-tom",
+	"เสด็จ": "sà~dèt", "ตรัส": "dtràt", "ประทับ": "bprà~táp",
+	"สวรรคต": "sà~wǎn-ná~kót", "ทิวงคต": "tí-wong-kót",
+	"สิ้นพระชนม์": "sîn-prá-chon", "รับสั่ง": "ráp-sàng",
+	"ทรง": "song", "ถวาย": "tà~wǎai", "ถวายพระพร": "tà~wǎai-prá-pɔɔn",
+	"ทรงพระเจริญ": "song-prá-jà~rəən", "พระราชทาน": "prá-râat-chá~taan",
+
+	// Royal nouns and titles
+	"พระองค์": "prá-ong", "ในหลวง": "nai-lǔuang", "สมเด็จ": "sǒm-dèt",
+	"พระราชา": "prá-raa-chaa", "พระราชินี": "prá-raa-chí-nii",
+	"พระราชดำรัส": "prá-râat-chá~dam-ràt", "พระราชวัง": "prá-râat-chá~wang",
+	"พระบาทสมเด็จพระเจ้าอยู่หัว": "prá-bàat-sǒm-dèt-prá-jâo-yùu-hǔa",
+	"พระชนมายุ": "prá-chon-ná~maa-yú", "พระโอรส": "prá-oo-rót",
+	"พระธิดา": "prá-tí-daa", "พระกระยาหาร": "prá-grà~yaa-hǎan",
+	"หม่อม": "mɔ̀m", "หม่อมเจ้า": "mɔ̀m-jâo",
+	"หม่อมราชวงศ์": "mɔ̀m-râat-chá~wong",
+
+	// Ecclesiastical vocabulary
+	"อาพาธ": "aa-pâat", "มรณภาพ": "mɔɔ-rá~ná~pâap", "ภัตตาหาร": "pát-dtaa-hǎan",
+	"จังหัน": "jang-hǎn", "บิณฑบาต": "bin-tá~bàat", "อาตมา": "àat-dtà~maa",
+	"โยม": "yoom", "ญาติโยม": "yâat-yoom", "บรรพชา": "ban-pá~chaa",
+	"อุปสมบท": "ùp-bpà~sǒm-bòt", "พระสงฆ์": "prá-sǒng", "จำวัด": "jam-wát",
+	"ปลงอาบัติ": "bplong-aa-bàt", "เจริญพร": "jà~rəən-pɔɔn",
+}
+
+// LookupRoyal checks if a word exists in the royal and ecclesiastical
+// vocabulary tier. Returns (transliteration, true) if found, ("", false)
+// otherwise.
+func LookupRoyal(word string) (string, bool) {
+	trans, ok := royalVocabulary[word]
+	if !ok {
+		return "", false
+	}
+	return norm.NFC.String(trans), true
+}
@@ -0,0 +1,35 @@
+package paiboonizer
+
+import (
+	"bufio"
+	"html"
+	"io"
+	"strings"
+)
+
+// WriteRubyHTML writes tokens as an HTML fragment with the romanization of
+// each Thai word and particle above it as ruby text, for reading
+// material. Words annotated with a frequency band (see Options.Frequency)
+// get the class band-top-1k, band-top-5k or band-rare on their <ruby>
+// element, so that a style sheet can show learners which words to learn
+// first. The other tokens are written as escaped text and line breaks as
+// <br>, but subtitle markup is left out.
+func WriteRubyHTML(w io.Writer, tokens []Token) error {
+	bw := bufio.NewWriter(w)
+	br := strings.NewReplacer("\r\n", "<br>\n", "\n", "<br>\n")
+	for _, tok := range tokens {
+		if tok.Kind == KindMarkup {
+			continue
+		}
+		if tok.Kind != KindWord && tok.Kind != KindParticle {
+			bw.WriteString(br.Replace(html.EscapeString(tok.Text)))
+			continue
+		}
+		bw.WriteString("<ruby")
+		if tok.Band != BandNone {
+			bw.WriteString(` class="band-` + tok.Band.String() + `"`)
+		}
+		bw.WriteString(">" + html.EscapeString(tok.Text) + "<rt>" + html.EscapeString(tok.Roman) + "</rt></ruby>")
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,360 @@
+package paiboonizer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Scheme is another rendering of the romanization: ASCII for learner
+// input methods, phonetic keyboards and other tools that can't show the
+// Paiboon glyphs, or a transcription such as the IPA. A scheme only
+// rewrites the output: the words are segmented and romanized as usual,
+// then each letter and tone mark is mapped through its tables.
+type Scheme struct {
+	Name string
+	// Letters rewrites letters outside ASCII (ʉ, ɛ, ɔ, ə). An upper case
+	// letter takes the rewriting of its lower case, capitalized. Letters
+	// not listed are kept.
+	Letters map[rune]string
+	// LongLetters rewrites a doubled letter, a long vowel (ʉʉ, ɛɛ), as a
+	// unit, so that its rewriting isn't written twice (ʉʉ → uee rather
+	// than ueue). A doubled letter not listed is rewritten letter by
+	// letter.
+	LongLetters map[rune]string
+	// Tones maps tone names (mid, low, falling, high, rising) to what is
+	// written after the syllable; a tone not listed writes nothing
+	Tones map[string]string
+	// Syllable, when non-nil, renders each well-formed syllable from its
+	// initial, vowel, final and tone (see SplitPaiboonSyllable), for
+	// schemes that spell sounds differently by position. Letters and
+	// Tones then only render the syllables it can't split or returns ""
+	// for.
+	Syllable func(initial, vowel, final, tone string) string
+	// Boundary, when non-empty, is written in place of the syllable
+	// separators "-" and "~", for transcriptions in which they mean
+	// nothing: the IPA writes ".". Word boundaries are kept.
+	Boundary string
+	// Toneless renders the syllables without their tone: Syllable is
+	// given "" as tone and nothing from Tones is written (see
+	// Options.Toneless)
+	Toneless bool
+}
+
+// asciiScheme is the "ascii" scheme of ToASCII: tones numbered as
+// SyllableResult.ToneNumber, mid tone without a digit
+var asciiScheme = Scheme{
+	Name:        "ascii",
+	Letters:     map[rune]string{'ʉ': "ue", 'ɛ': "ae", 'ɔ': "aw", 'ə': "oe"},
+	LongLetters: map[rune]string{'ʉ': "uee", 'ɛ': "aee", 'ɔ': "aaw", 'ə': "oee"},
+	Tones:       map[string]string{"low": "1", "falling": "2", "high": "3", "rising": "4"},
+}
+
+// imeScheme is the "ime" scheme: vowels spelled as in English-based
+// learner input methods and a digit after every syllable, mid tone 0
+var imeScheme = Scheme{
+	Name:        "ime",
+	Letters:     map[rune]string{'ʉ': "eu", 'ɛ': "ae", 'ɔ': "or", 'ə': "er"},
+	LongLetters: map[rune]string{'ʉ': "euu", 'ɛ': "aee", 'ɔ': "oor", 'ə': "eer"},
+	Tones:       map[string]string{"mid": "0", "low": "1", "falling": "2", "high": "3", "rising": "4"},
+}
+
+// paiboonScheme is the "paiboon" scheme: the syllables written back in
+// Paiboon, which only normalizes them
+var paiboonScheme = Scheme{Name: "paiboon", Syllable: writePaiboon}
+
+// rtgsScheme is the "rtgs" scheme: the Royal Thai General System, which
+// transcribes syllables as ISO 11940-2 does
+var rtgsScheme = Scheme{Name: "rtgs", Letters: iso11940Part2Vowels, Syllable: iso11940Part2Syllable}
+
+var (
+	schemesMu sync.RWMutex
+	schemes   = map[string]Scheme{
+		asciiScheme.Name:         asciiScheme,
+		imeScheme.Name:           imeScheme,
+		iso11940Part2Scheme.Name: iso11940Part2Scheme,
+		ipaLettersScheme.Name:    ipaLettersScheme,
+		ipaDiacriticsScheme.Name: ipaDiacriticsScheme,
+		ipaNumbersScheme.Name:    ipaNumbersScheme,
+		respellingScheme.Name:    respellingScheme,
+		paiboonScheme.Name:       paiboonScheme,
+		rtgsScheme.Name:          rtgsScheme,
+		haasScheme.Name:          haasScheme,
+	}
+)
+
+// RegisterScheme makes s available to Options.Scheme under s.Name. The
+// built-in schemes are "paiboon", "ascii" (see ToASCII), "ime", "rtgs",
+// "haas", "iso11940-2", the IPA schemes "ipa", "ipa-diacritics" and
+// "ipa-numbers" (see ToIPA) and "thai" (see ThaiRespelling). A name can't
+// be registered twice, so that the output for a name never changes.
+func RegisterScheme(s Scheme) error {
+	if s.Name == "" {
+		return fmt.Errorf("scheme without a name")
+	}
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+	if _, ok := schemes[s.Name]; ok {
+		return fmt.Errorf("scheme %q already registered", s.Name)
+	}
+	schemes[s.Name] = s
+	return nil
+}
+
+// SyllableRenderer writes a syllable, split by SplitPaiboonSyllable, in a
+// romanization system: the extension point for third-party systems (see
+// RegisterRenderer). Scheme implements it, as do the built-in renderers
+// PaiboonRenderer, RTGSRenderer, HaasRenderer, ThaiRespellingRenderer
+// and IPARenderer.
+type SyllableRenderer interface {
+	RenderSyllable(initial, vowel, final, tone string) string
+}
+
+// SyllableFunc adapts a function to SyllableRenderer
+type SyllableFunc func(initial, vowel, final, tone string) string
+
+// RenderSyllable calls f
+func (f SyllableFunc) RenderSyllable(initial, vowel, final, tone string) string {
+	return f(initial, vowel, final, tone)
+}
+
+// The built-in renderers
+var (
+	PaiboonRenderer        SyllableRenderer = SyllableFunc(writePaiboon)
+	RTGSRenderer           SyllableRenderer = SyllableFunc(iso11940Part2Syllable)
+	ThaiRespellingRenderer SyllableRenderer = SyllableFunc(respellSyllable)
+)
+
+// IPARenderer returns the renderer of ToIPA writing tones in notation
+func IPARenderer(notation ToneNotation) SyllableRenderer {
+	return SyllableFunc(func(initial, vowel, final, tone string) string {
+		return ipaSyllable(initial, vowel, final, tone, notation)
+	})
+}
+
+// RegisterRenderer registers r as a Scheme under name (see
+// RegisterScheme). Syllables that don't split, and syllables r renders as
+// "", are kept as written.
+func RegisterRenderer(name string, r SyllableRenderer) error {
+	return RegisterScheme(Scheme{Name: name, Syllable: r.RenderSyllable})
+}
+
+// RenderSyllable renders a syllable with s.Syllable, or when s has none
+// or it returns "", with Letters and Tones
+func (s Scheme) RenderSyllable(initial, vowel, final, tone string) string {
+	if s.Toneless {
+		tone = ""
+	}
+	if s.Syllable != nil {
+		if rendered := s.Syllable(initial, vowel, final, tone); rendered != "" {
+			return rendered
+		}
+	}
+	return s.renderLetters(writePaiboon(initial, vowel, final, tone))
+}
+
+// paiboonMarks are the combining tone marks of Paiboon by tone
+var paiboonMarks = map[string]string{"low": "\u0300", "falling": "\u0302", "high": "\u0301", "rising": "\u030C"}
+
+// writePaiboon writes a syllable in Paiboon, the tone mark on its
+// first vowel letter
+func writePaiboon(initial, vowel, final, tone string) string {
+	_, size := utf8.DecodeRuneInString(vowel)
+	return norm.NFC.String(initial + vowel[:size] + paiboonMarks[tone] + vowel[size:] + final)
+}
+
+// LookupScheme returns the scheme registered under name
+func LookupScheme(name string) (Scheme, bool) {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	s, ok := schemes[name]
+	return s, ok
+}
+
+// Schemes returns the names of the registered schemes, sorted
+func Schemes() []string {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render rewrites a romanization with the tables of s. A syllable ends at
+// the first character that isn't an ASCII letter, where its tone is
+// written. Combining marks other than the tone marks are dropped; text in
+// other scripts is left as is.
+func (s Scheme) Render(roman string) string {
+	return s.boundaries(s.render(roman))
+}
+
+// schemeSyllableSep matches the syllable separators Boundary replaces
+var schemeSyllableSep = regexp.MustCompile(`[-~]+`)
+
+// boundaries writes Boundary in place of the syllable separators of roman
+func (s Scheme) boundaries(roman string) string {
+	if s.Boundary == "" {
+		return roman
+	}
+	return schemeSyllableSep.ReplaceAllString(roman, s.Boundary)
+}
+
+// render is Render without Boundary, so that Options.Separator can mark
+// the boundaries in between
+func (s Scheme) render(roman string) string {
+	if s.Syllable != nil {
+		return s.renderSyllables(roman)
+	}
+	return s.renderLetters(roman)
+}
+
+// renderSyllables renders each run of letters of roman with s.Syllable,
+// with unsplit when it isn't a single syllable. A capitalized syllable
+// stays capitalized.
+func (s Scheme) renderSyllables(roman string) string {
+	var b strings.Builder
+	var run strings.Builder
+	flush := func() {
+		if run.Len() == 0 {
+			return
+		}
+		syllable := run.String()
+		run.Reset()
+		first, _ := utf8.DecodeRuneInString(syllable)
+		initial, vowel, final, tone, ok := SplitPaiboonSyllable(strings.ToLower(syllable))
+		if !ok {
+			b.WriteString(s.unsplit(syllable))
+			return
+		}
+		if s.Toneless {
+			tone = ""
+		}
+		rendered := s.Syllable(initial, vowel, final, tone)
+		if rendered == "" {
+			b.WriteString(s.unsplit(syllable))
+			return
+		}
+		if r, size := utf8.DecodeRuneInString(rendered); unicode.IsUpper(first) && size > 0 {
+			rendered = string(unicode.ToUpper(r)) + rendered[size:]
+		}
+		b.WriteString(rendered)
+	}
+	for _, r := range norm.NFD.String(roman) {
+		if unicode.IsLetter(r) || unicode.Is(unicode.Mn, r) {
+			run.WriteRune(r)
+			continue
+		}
+		flush()
+		b.WriteRune(r)
+	}
+	flush()
+	return norm.NFC.String(b.String())
+}
+
+// unsplit renders a run of letters s.Syllable can't: with the Letters
+// and Tones tables, or as written when s has neither, such as a run of
+// syllables without a separator (sàwàtdii), which would lose its tone
+// marks otherwise
+func (s Scheme) unsplit(run string) string {
+	if len(s.Letters) == 0 && len(s.Tones) == 0 && !s.Toneless {
+		return run
+	}
+	return s.renderLetters(run)
+}
+
+// renderLetters is Render with the Letters and Tones tables
+func (s Scheme) renderLetters(roman string) string {
+	var b strings.Builder
+	tone, inSyllable := "mid", false
+	flush := func() {
+		if inSyllable && !s.Toneless {
+			b.WriteString(s.Tones[tone])
+		}
+		tone, inSyllable = "mid", false
+	}
+	runes := []rune(norm.NFD.String(roman))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if t, ok := toneByMark[r]; ok {
+			tone = t
+			continue
+		}
+		if r >= 0x0300 && r <= 0x036F {
+			continue
+		}
+		if letters, n := s.longLetter(runes, i); n > 0 {
+			// The tone mark sits between the two letters
+			for _, m := range runes[i+1 : i+n] {
+				if t, ok := toneByMark[m]; ok {
+					tone = t
+				}
+			}
+			b.WriteString(letters)
+			inSyllable = true
+			i += n - 1
+			continue
+		}
+		if letters, ok := s.letter(r); ok {
+			b.WriteString(letters)
+			inSyllable = true
+			continue
+		}
+		if !isASCIILetter(r) {
+			flush()
+		} else {
+			inSyllable = true
+		}
+		b.WriteRune(r)
+	}
+	flush()
+	return norm.NFC.String(b.String())
+}
+
+// longLetter returns the rewriting of the doubled letter starting at
+// runes[i], capitalized for upper case letters, and the number of runes
+// it spans, marks between its letters included; 0 when runes[i] doesn't
+// start a doubled letter of LongLetters
+func (s Scheme) longLetter(runes []rune, i int) (string, int) {
+	lower := unicode.ToLower(runes[i])
+	letters, ok := s.LongLetters[lower]
+	if !ok {
+		return "", 0
+	}
+	j := i + 1
+	for j < len(runes) && runes[j] >= 0x0300 && runes[j] <= 0x036F {
+		j++
+	}
+	if j == len(runes) || unicode.ToLower(runes[j]) != lower {
+		return "", 0
+	}
+	if unicode.IsUpper(runes[i]) && letters != "" {
+		first, size := utf8.DecodeRuneInString(letters)
+		letters = string(unicode.ToUpper(first)) + letters[size:]
+	}
+	return letters, j - i + 1
+}
+
+// letter returns the rewriting of r, capitalized for upper case letters
+func (s Scheme) letter(r rune) (string, bool) {
+	if letters, ok := s.Letters[r]; ok {
+		return letters, true
+	}
+	if !unicode.IsUpper(r) {
+		return "", false
+	}
+	letters, ok := s.Letters[unicode.ToLower(r)]
+	if !ok || letters == "" {
+		return letters, ok
+	}
+	first, size := utf8.DecodeRuneInString(letters)
+	return string(unicode.ToUpper(first)) + letters[size:], true
+}
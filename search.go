@@ -0,0 +1,124 @@
+package paiboonizer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// DictQuery selects dictionary entries. Every non-empty field must match;
+// the zero value matches everything.
+type DictQuery struct {
+	Prefix string // Thai spelling starts with Prefix
+	Suffix string // Thai spelling ends with Suffix
+	// RomanContains matches a substring of the romanization. Without tone
+	// marks in the query the match ignores tones ("maa" finds "mâa").
+	RomanContains string
+	// RhymesWith is a Thai word; entries whose last syllable has the same
+	// vowel and final sound (any tone, any initial) match
+	RhymesWith string
+	Limit      int // Maximum number of entries returned, 0 for no limit
+}
+
+// Entry is a dictionary entry
+type Entry struct {
+	ID     string // Stable across romanization corrections (see EntryID)
+	Thai   string
+	Roman  string
+	Source string // "official" or "opus"
+}
+
+// romanSyllableSep splits a romanized word into syllables
+var romanSyllableSep = regexp.MustCompile(`[-~ ]+`)
+
+// SearchDictionary returns the dictionary entries matching query, sorted
+// by Thai spelling. Words present in both dictionaries are returned once,
+// with the official romanization.
+func SearchDictionary(query DictQuery) []Entry {
+	ensureDictionaryLoaded()
+	romanQuery := norm.NFC.String(query.RomanContains)
+	toneless := romanQuery == stripRomanTones(romanQuery)
+
+	rhyme := ""
+	if query.RhymesWith != "" {
+		roman := TransliterateWordWithOptions(norm.NFC.String(query.RhymesWith), Options{})
+		var ok bool
+		if rhyme, ok = rhymeOf(roman); !ok {
+			return nil
+		}
+	}
+
+	matches := func(thai, roman string) bool {
+		if query.Prefix != "" && !strings.HasPrefix(thai, query.Prefix) {
+			return false
+		}
+		if query.Suffix != "" && !strings.HasSuffix(thai, query.Suffix) {
+			return false
+		}
+		if romanQuery != "" {
+			haystack := roman
+			if toneless {
+				haystack = stripRomanTones(roman)
+			}
+			if !strings.Contains(haystack, romanQuery) {
+				return false
+			}
+		}
+		if rhyme != "" {
+			if r, ok := rhymeOf(roman); !ok || r != rhyme {
+				return false
+			}
+		}
+		return true
+	}
+
+	entries := []Entry{}
+	for _, e := range dictionaryEntries() {
+		if matches(e.Thai, e.Roman) {
+			entries = append(entries, e)
+		}
+	}
+	if query.Limit > 0 && len(entries) > query.Limit {
+		entries = entries[:query.Limit]
+	}
+	return entries
+}
+
+// dictionaryEntries returns every dictionary entry in NFC, sorted by Thai
+// spelling. Words present in both dictionaries keep the official
+// romanization.
+func dictionaryEntries() []Entry {
+	entries := make([]Entry, 0, len(dictionary)+len(opusDictionary))
+	for thai, value := range dictionary {
+		entries = append(entries, Entry{ID: entryIDOf(thai), Thai: thai, Roman: norm.NFC.String(value.Roman), Source: "official"})
+	}
+	for thai, value := range opusDictionary {
+		if _, ok := dictionary[thai]; ok {
+			continue
+		}
+		entries = append(entries, Entry{ID: entryIDOf(thai), Thai: thai, Roman: norm.NFC.String(value.Roman), Source: "opus"})
+	}
+	sortEntries(entries)
+	return entries
+}
+
+// sortEntries sorts entries by Thai spelling
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Thai < entries[j].Thai })
+}
+
+// rhymeOf returns the vowel and final of the last syllable of a romanized
+// word, e.g. "aan" for "mʉ̂ʉa-waan"
+func rhymeOf(roman string) (string, bool) {
+	syllables := romanSyllableSep.Split(strings.TrimSpace(roman), -1)
+	if len(syllables) == 0 {
+		return "", false
+	}
+	_, vowel, final, _, ok := SplitPaiboonSyllable(syllables[len(syllables)-1])
+	if !ok {
+		return "", false
+	}
+	return vowel + final, true
+}
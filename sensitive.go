@@ -0,0 +1,147 @@
+package paiboonizer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// SensitiveList maps sensitive Thai words (profanity, slurs, sexual
+// vocabulary) to their category, for products that have to tag or hide
+// them, such as subtitles for children
+type SensitiveList map[string]string
+
+// defaultSensitiveCategory is the category of words listed without one
+const defaultSensitiveCategory = "sensitive"
+
+// ReadSensitiveList reads a sensitive word list: one Thai word per line,
+// optionally followed by a tab and its category ("profanity", "sexual").
+// Words without a category get "sensitive". Blank lines and lines starting
+// with # are ignored.
+func ReadSensitiveList(r io.Reader) (SensitiveList, error) {
+	list := SensitiveList{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(norm.NFC.String(scanner.Text()))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		word, category, _ := strings.Cut(line, "\t")
+		word, category = strings.TrimSpace(word), strings.TrimSpace(category)
+		if !containsThai(word) {
+			return nil, fmt.Errorf("line %d: no Thai in %q", n, word)
+		}
+		if category == "" {
+			category = defaultSensitiveCategory
+		}
+		list[word] = category
+	}
+	return list, scanner.Err()
+}
+
+// Category returns the category of word: its own, or that of a listed
+// word among the words it splits into. Only splits into listed and
+// dictionary words covering all of word count, so that compounds
+// (ไอ้เหี้ย) are caught but words merely spelled with a listed one
+// aren't: หีบ (box) is not หี followed by a word บ. Returns false for
+// words containing no listed word.
+func (l SensitiveList) Category(word string) (string, bool) {
+	if category, ok := l[word]; ok {
+		return category, true
+	}
+	// covered[i] reports whether runes[i:] splits into words, category[i]
+	// the category of the first listed one in the longest-first such split
+	// that has one
+	runes := []rune(word)
+	covered := make([]bool, len(runes)+1)
+	category := make([]string, len(runes)+1)
+	covered[len(runes)] = true
+	for i := len(runes) - 1; i >= 0; i-- {
+		if !canStartWord(runes, i) {
+			continue
+		}
+		for n := min(len(runes)-i, maxWordLen); n > 0 && category[i] == ""; n-- {
+			if !covered[i+n] {
+				continue
+			}
+			piece := string(runes[i : i+n])
+			if listed, ok := l[piece]; ok {
+				covered[i], category[i] = true, listed
+			} else if _, ok := LookupDictionary(piece); ok && n > 1 {
+				covered[i], category[i] = true, category[i+n]
+			}
+		}
+	}
+	return category[0], category[0] != ""
+}
+
+// SensitivePolicy selects what the romanization of a listed word becomes
+type SensitivePolicy int
+
+const (
+	// SensitiveTag only sets Token.Sensitive and keeps the romanization.
+	// The default.
+	SensitiveTag  SensitivePolicy = iota
+	SensitiveMark                 // The romanization in brackets: [hîia]
+	SensitiveMask                 // Every letter of the romanization replaced by *: ****
+)
+
+// String returns the name of the policy
+func (p SensitivePolicy) String() string {
+	switch p {
+	case SensitiveTag:
+		return "tag"
+	case SensitiveMark:
+		return "mark"
+	case SensitiveMask:
+		return "mask"
+	}
+	return "unknown"
+}
+
+// Apply rewrites the romanization of a listed word as p says
+func (p SensitivePolicy) Apply(roman string) string {
+	switch p {
+	case SensitiveMark:
+		return "[" + roman + "]"
+	case SensitiveMask:
+		return strings.Map(func(r rune) rune {
+			switch {
+			case unicode.Is(unicode.Mn, r):
+				return -1
+			case unicode.IsLetter(r):
+				return '*'
+			}
+			return r
+		}, norm.NFD.String(roman))
+	}
+	return roman
+}
+
+// ApplySensitive tags the Thai words and particles of tokens found in
+// list (see SensitiveList.Category) with their category and rewrites
+// their romanization as policy says, for token streams romanized without
+// Options.Sensitive. Repeated words (ๆ) follow the word they repeat.
+func ApplySensitive(tokens []Token, list SensitiveList, policy SensitivePolicy) []Token {
+	last := ""
+	for i, t := range tokens {
+		if t.Kind != KindWord && t.Kind != KindParticle {
+			continue
+		}
+		category, ok := list.Category(t.Text)
+		if t.Text == "ๆ" {
+			category, ok = last, last != ""
+		}
+		last = ""
+		if ok {
+			tokens[i].Sensitive = category
+			tokens[i].Roman = policy.Apply(t.Roman)
+			last = category
+		}
+	}
+	return tokens
+}
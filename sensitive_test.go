@@ -0,0 +1,43 @@
+package paiboonizer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSensitiveListCategory(t *testing.T) {
+	list, err := ReadSensitiveList(strings.NewReader("หี\tvulgar\nเหี้ย\tinsult\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		word, want string
+		// compound words are found by their word split, which slim
+		// builds can't make
+		compound bool
+	}{
+		{"หี", "vulgar", false},
+		{"เหี้ย", "insult", false},
+		{"ไอ้เหี้ย", "insult", true},
+		{"หีบ", "", false},
+		{"หีบเพลง", "", false},
+	}
+	for _, tt := range tests {
+		if tt.compound && SlimBuild() {
+			continue
+		}
+		got, ok := list.Category(tt.word)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("Category(%q) = %q %v, want %q", tt.word, got, ok, tt.want)
+		}
+	}
+
+	opts := DefaultOptions()
+	opts.Sensitive = list
+	opts.SensitivePolicy = SensitiveMask
+	for _, text := range []string{"หีบ", "หีบเพลง"} {
+		if got := TransliterateText(text, opts); strings.Contains(got, "*") {
+			t.Errorf("TransliterateText(%q) = %q, masked", text, got)
+		}
+	}
+}
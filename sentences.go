@@ -0,0 +1,134 @@
+package paiboonizer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// weakClosers are sentence-final particles common enough inside sentences
+// (ไม่เลย, ไปหรือไม่) that a space after them doesn't end one
+var weakClosers = map[string]bool{"เลย": true, "หรือ": true}
+
+// sentence is a sentence of a text with its offset in runes
+type sentence struct {
+	text   string
+	offset int
+}
+
+// SplitSentences splits a paragraph into sentences, trimmed of the
+// whitespace between them. Thai has no sentence punctuation, so a
+// sentence ends at a line break, at a space after . ! ? or … (abbreviations
+// like กทม. aside) and at a space after a sentence-final particle (ครับ,
+// นะ, ไหม). Other spaces, which Thai also puts between clauses, don't split.
+// The pythainlp service has no sentence tokenizer, so the split is always
+// heuristic.
+func SplitSentences(text string) []string {
+	sentences := []string{}
+	for _, s := range splitSentences(text) {
+		sentences = append(sentences, s.text)
+	}
+	return sentences
+}
+
+// splitSentences is SplitSentences with the offset of each sentence
+func splitSentences(text string) []sentence {
+	var sentences []sentence
+	start, offset := -1, 0
+	var current strings.Builder
+	flush := func() {
+		if s := strings.TrimRightFunc(current.String(), unicode.IsSpace); s != "" {
+			sentences = append(sentences, sentence{s, start})
+		}
+		current.Reset()
+		start = -1
+	}
+	for i, r := range text {
+		if unicode.IsSpace(r) && start >= 0 {
+			// A split at the first space of a gap; the following ones
+			// are trimmed off
+			prev, _ := utf8.DecodeLastRuneInString(text[:i])
+			if r == '\n' || r == '\r' || !unicode.IsSpace(prev) && endsSentence(current.String()) {
+				flush()
+			}
+		}
+		if start < 0 && !unicode.IsSpace(r) {
+			start = offset
+		}
+		if start >= 0 {
+			current.WriteRune(r)
+		}
+		offset++
+	}
+	flush()
+	return sentences
+}
+
+// endsSentence reports whether a sentence may end after s, before a space
+func endsSentence(s string) bool {
+	s = strings.TrimRight(s, `)"'”’»`)
+	last, _ := utf8.DecodeLastRuneInString(s)
+	switch {
+	case last == '!' || last == '?' || last == '…':
+		return true
+	case last == '.':
+		// Not after an abbreviation, which keeps its dot
+		words := segmentThai(lastThaiRun(s), Options{DetectAcronyms: true})
+		return len(words) == 0 || words[len(words)-1].kind != tokenThai
+	case isThaiRune(last):
+		words := segmentThai(lastThaiRun(s), Options{})
+		word := words[len(words)-1].text
+		return IsSentenceFinalParticle(word) && !weakClosers[word]
+	}
+	return false
+}
+
+// lastThaiRun returns the Thai run s ends with, its dots included
+func lastThaiRun(s string) string {
+	runs := splitScripts(s)
+	if len(runs) == 0 || runs[len(runs)-1].kind != tokenThai {
+		return ""
+	}
+	return runs[len(runs)-1].text
+}
+
+// SentenceResult is a sentence of TransliterateSentences
+type SentenceResult struct {
+	Text   string
+	Offset int // Position in the paragraph, in runes
+	TextResult
+	// Err is set when the sentence couldn't be romanized; TextResult is
+	// then empty and the other sentences are unaffected
+	Err error
+}
+
+// TransliterateSentences splits a paragraph with SplitSentences and
+// romanizes each sentence on its own with TransliterateTextDetailed, so
+// that one failing sentence doesn't cost the rest: an unexpected failure
+// of the rules on a sentence is reported in its Err. Offsets of dropped
+// spans and warnings are relative to the sentence.
+func TransliterateSentences(text string, opts Options) []SentenceResult {
+	ensureDictionaryLoaded()
+	if opts.NormalizeInput {
+		text = normalizeInput(text)
+	}
+	results := []SentenceResult{}
+	for _, s := range splitSentences(text) {
+		res := SentenceResult{Text: s.text, Offset: s.offset}
+		res.TextResult, res.Err = transliterateSentence(s.text, opts)
+		results = append(results, res)
+	}
+	return results
+}
+
+// transliterateSentence romanizes a sentence, turning a panic of the rules
+// into an error
+func transliterateSentence(text string, opts Options) (result TextResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = TextResult{}, fmt.Errorf("romanizing %q: %v", text, r)
+		}
+	}()
+	return TransliterateTextDetailed(text, opts), nil
+}
@@ -0,0 +1,130 @@
+package paiboonizer
+
+import (
+	"strings"
+)
+
+// SeparatorMark is what a Separator writes at the boundaries it marks
+type SeparatorMark int
+
+const (
+	// MarkAsWritten keeps the separators of the dictionary entries,
+	// special cases and JoinPolicy ("-" and "~") and a space between
+	// words. The default.
+	MarkAsWritten SeparatorMark = iota
+	MarkHyphen                  // kwaam-sùk
+	MarkTilde                   // kwaam~sùk
+	MarkSpace                   // kwaam sùk
+	MarkNone                    // kwaamsùk
+)
+
+// String returns the name of the mark
+func (m SeparatorMark) String() string {
+	switch m {
+	case MarkAsWritten:
+		return "as-written"
+	case MarkHyphen:
+		return "hyphen"
+	case MarkTilde:
+		return "tilde"
+	case MarkSpace:
+		return "space"
+	case MarkNone:
+		return "none"
+	}
+	return "unknown"
+}
+
+// text returns what the mark writes, asWritten for MarkAsWritten
+func (m SeparatorMark) text(asWritten string) string {
+	switch m {
+	case MarkHyphen:
+		return "-"
+	case MarkTilde:
+		return "~"
+	case MarkSpace:
+		return " "
+	case MarkNone:
+		return ""
+	}
+	return asWritten
+}
+
+// SeparatorScope selects the boundaries a Separator marks
+type SeparatorScope int
+
+const (
+	// ScopeSyllable marks the boundaries between the syllables of a word;
+	// words stay separated by a space. The default.
+	ScopeSyllable SeparatorScope = iota
+	// ScopeWord runs the syllables of each word together and marks the
+	// boundaries between words written together in the input instead
+	ScopeWord
+)
+
+// String returns the name of the scope
+func (s SeparatorScope) String() string {
+	switch s {
+	case ScopeSyllable:
+		return "syllable"
+	case ScopeWord:
+		return "word"
+	}
+	return "unknown"
+}
+
+// Separator is the output policy for syllable and word boundaries. Unlike
+// JoinPolicy, which only covers words left to the rules, it applies
+// uniformly to every romanized word whatever wrote it: dictionary entries
+// (kwaam-sùk, sà~màk), special cases, spelled out acronyms and the rules.
+// Overrides are written as given. The zero value keeps the output as
+// written.
+type Separator struct {
+	Mark  SeparatorMark
+	Scope SeparatorScope
+}
+
+// String returns the mark and scope of the separator, as "hyphen/syllable"
+func (s Separator) String() string {
+	return s.Mark.String() + "/" + s.Scope.String()
+}
+
+// Apply rewrites the boundaries of a romanization as s says: "-" and "~"
+// are syllable boundaries, spaces word boundaries. It converts the output
+// of functions that take no Options, such as TransliterateWordRulesOnly
+// and ComprehensiveTransliterate; the others apply Options.Separator
+// themselves.
+func (s Separator) Apply(roman string) string {
+	if s == (Separator{}) {
+		return roman
+	}
+	syllable, word := s.Mark.text(""), " "
+	if s.Scope == ScopeWord {
+		syllable, word = "", s.Mark.text(" ")
+	}
+	return romanSyllableSep.ReplaceAllStringFunc(roman, func(sep string) string {
+		if strings.Contains(sep, " ") {
+			return word
+		}
+		return syllable
+	})
+}
+
+// between returns what is written between two words written together in
+// the input, asWritten unless s marks word boundaries
+func (s Separator) between(asWritten string) string {
+	if s.Scope == ScopeWord {
+		return s.Mark.text(asWritten)
+	}
+	return asWritten
+}
+
+// joinPolicy returns the JoinPolicy the rules use under s: any policy
+// marking syllables, since Apply rewrites the boundaries, unless s keeps
+// the output as written
+func (s Separator) joinPolicy(join JoinPolicy) JoinPolicy {
+	if s == (Separator{}) {
+		return join
+	}
+	return JoinHyphen
+}
@@ -0,0 +1,192 @@
+package paiboonizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+// SoakConfig sets what SoakManagers runs and the growth it tolerates
+type SoakConfig struct {
+	Duration time.Duration // Total run time
+	// Cycle is how long each Manager streams lines before it is closed
+	// and the next one created; zero means one minute
+	Cycle          time.Duration
+	Lines          []string // Corpus lines romanized in a loop
+	ManagerOptions []ManagerOption
+	// MaxHeapGrowth is the growth of the live heap tolerated, in bytes;
+	// zero means 64 MiB
+	MaxHeapGrowth uint64
+	// MaxFDGrowth and MaxGoroutineGrowth are the growth of open file
+	// descriptors and goroutines tolerated; zero tolerates none
+	MaxFDGrowth        int
+	MaxGoroutineGrowth int
+	// Progress, when non-nil, is called with each sample
+	Progress func(SoakSample)
+}
+
+// SoakSample is the state of the process after a Manager was closed
+type SoakSample struct {
+	Cycle      int // 0 before the first Manager
+	Elapsed    time.Duration
+	Lines      int    // Lines romanized during the cycle
+	Errors     int    // Lines that failed during the cycle
+	HeapAlloc  uint64 // Live heap after a garbage collection, in bytes
+	FDs        int    // Open file descriptors, -1 where they can't be counted
+	Goroutines int
+	Containers int // Running pythainlp containers, -1 without Docker
+}
+
+// String formats the sample on one line
+func (s SoakSample) String() string {
+	return fmt.Sprintf("cycle %d at %v: %d lines, %d errors, heap %.1f MiB, %d fds, %d goroutines, %d containers",
+		s.Cycle, s.Elapsed.Round(time.Second), s.Lines, s.Errors, float64(s.HeapAlloc)/(1<<20), s.FDs, s.Goroutines, s.Containers)
+}
+
+// SoakReport is the result of SoakManagers
+type SoakReport struct {
+	Samples  []SoakSample
+	Problems []string // Leaks found, empty when the run is clean
+}
+
+// OK reports whether no leak was found
+func (r SoakReport) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// String formats the report for display
+func (r SoakReport) String() string {
+	var b strings.Builder
+	for _, s := range r.Samples {
+		b.WriteString(s.String() + "\n")
+	}
+	if r.OK() {
+		b.WriteString("No leak found\n")
+	}
+	for _, p := range r.Problems {
+		fmt.Fprintf(&b, "Problem: %s\n", p)
+	}
+	return b.String()
+}
+
+// SoakManagers creates a Manager, streams cfg.Lines through it for a cycle,
+// closes it and starts over until cfg.Duration has elapsed, sampling the
+// live heap, open file descriptors, goroutines and running pythainlp
+// containers after each Close. The samples are compared with the one
+// after the first cycle, which has warmed the caches, so that leaks in the
+// pythainlp integration show up as growth before they reach production
+// services. Fails when a Manager can't be created; ctx ending stops the
+// run early with the samples taken so far.
+func SoakManagers(ctx context.Context, cfg SoakConfig) (SoakReport, error) {
+	if len(cfg.Lines) == 0 {
+		return SoakReport{}, errors.New("soak: no lines")
+	}
+	cycle := cfg.Cycle
+	if cycle <= 0 {
+		cycle = time.Minute
+	}
+	maxHeap := cfg.MaxHeapGrowth
+	if maxHeap == 0 {
+		maxHeap = 64 << 20
+	}
+	ensureDictionaryLoaded()
+	start := time.Now()
+	var report SoakReport
+	record := func(s SoakSample) {
+		s.Elapsed = time.Since(start)
+		report.Samples = append(report.Samples, s)
+		if cfg.Progress != nil {
+			cfg.Progress(s)
+		}
+	}
+	record(sampleProcess(ctx, SoakSample{}))
+
+	next := 0
+	for n := 1; time.Since(start) < cfg.Duration && ctx.Err() == nil; n++ {
+		m, err := NewManager(ctx, cfg.ManagerOptions...)
+		if err != nil {
+			return report, fmt.Errorf("soak: cycle %d: %w", n, err)
+		}
+		s := SoakSample{Cycle: n}
+		cycleCtx, cancel := context.WithTimeout(ctx, cycle)
+		for cycleCtx.Err() == nil {
+			if _, err := m.ThaiToRoman(cycleCtx, cfg.Lines[next]); err != nil && cycleCtx.Err() == nil {
+				s.Errors++
+			}
+			s.Lines++
+			next = (next + 1) % len(cfg.Lines)
+		}
+		cancel()
+		if err := m.Close(); err != nil {
+			report.Problems = append(report.Problems, fmt.Sprintf("cycle %d: closing the manager: %v", n, err))
+		}
+		record(sampleProcess(ctx, s))
+	}
+
+	if len(report.Samples) < 3 {
+		report.Problems = append(report.Problems, "fewer than 2 cycles: raise Duration or lower Cycle to compare them")
+		return report, nil
+	}
+	ref, last := report.Samples[1], report.Samples[len(report.Samples)-1]
+	if last.HeapAlloc > ref.HeapAlloc && last.HeapAlloc-ref.HeapAlloc > maxHeap {
+		report.Problems = append(report.Problems, fmt.Sprintf("live heap grew by %.1f MiB (%.1f MiB tolerated)",
+			float64(last.HeapAlloc-ref.HeapAlloc)/(1<<20), float64(maxHeap)/(1<<20)))
+	}
+	if ref.FDs >= 0 && last.FDs-ref.FDs > cfg.MaxFDGrowth {
+		report.Problems = append(report.Problems, fmt.Sprintf("open file descriptors grew from %d to %d", ref.FDs, last.FDs))
+	}
+	if last.Goroutines-ref.Goroutines > cfg.MaxGoroutineGrowth {
+		report.Problems = append(report.Problems, fmt.Sprintf("goroutines grew from %d to %d", ref.Goroutines, last.Goroutines))
+	}
+	if ref.Containers >= 0 && last.Containers > ref.Containers {
+		report.Problems = append(report.Problems, fmt.Sprintf("running pythainlp containers grew from %d to %d", ref.Containers, last.Containers))
+	}
+	return report, nil
+}
+
+// sampleProcess fills in the process state of s after a garbage collection
+func sampleProcess(ctx context.Context, s SoakSample) SoakSample {
+	runtime.GC()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	s.HeapAlloc = mem.HeapAlloc
+	s.FDs = countFDs()
+	s.Goroutines = runtime.NumGoroutine()
+	s.Containers = countPythainlpContainers(ctx)
+	return s
+}
+
+// countFDs returns the number of open file descriptors of the process,
+// -1 where /proc isn't available
+func countFDs() int {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return -1
+	}
+	return len(entries)
+}
+
+// countPythainlpContainers returns the number of running pythainlp
+// containers, as CheckEnvironment finds them, -1 without Docker
+func countPythainlpContainers(ctx context.Context) int {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return -1
+	}
+	defer cli.Close()
+	containers, err := cli.ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.service=pythainlp")),
+	})
+	if err != nil {
+		return -1
+	}
+	return len(containers)
+}
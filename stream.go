@@ -0,0 +1,129 @@
+package paiboonizer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// TransliterateStream copies r to w with the Thai of each line romanized
+// by TransliterateText, one line in memory at a time, so files of any
+// size can be romanized. Lines without Thai are copied byte for byte, line
+// endings (\n or \r\n) are kept, as is the non-Thai text of Thai lines. A
+// leading BOM is dropped.
+func TransliterateStream(r io.Reader, w io.Writer, opts Options) error {
+	return TransliterateStreamContext(context.Background(), r, w, opts)
+}
+
+// TransliterateStreamContext is TransliterateStream with a context bounding
+// the pythainlp calls of EngineAuto, checked between lines and words.
+// Returns the context's error if it ends before the end of r; the lines
+// romanized until then are written.
+func TransliterateStreamContext(ctx context.Context, r io.Reader, w io.Writer, opts Options) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	for first := true; ; first = false {
+		line, readErr := br.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			bw.Flush()
+			return readErr
+		}
+		if first {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+		body, eol := splitLineEnding(line)
+		if containsThai(body) {
+			roman, err := TransliterateTextContext(ctx, body, opts)
+			if err != nil {
+				bw.Flush()
+				return err
+			}
+			body = roman
+		}
+		if _, err := bw.WriteString(body + eol); err != nil {
+			return err
+		}
+		if readErr == io.EOF {
+			return bw.Flush()
+		}
+	}
+}
+
+// splitLineEnding splits the \n or \r\n ending off line
+func splitLineEnding(line string) (string, string) {
+	if strings.HasSuffix(line, "\r\n") {
+		return line[:len(line)-2], "\r\n"
+	}
+	if strings.HasSuffix(line, "\n") {
+		return line[:len(line)-1], "\n"
+	}
+	return line, ""
+}
+
+// lineJSON is a line as TransliterateJSONL writes it
+type lineJSON struct {
+	Line     int           `json:"line"`
+	Text     string        `json:"text"`
+	Roman    string        `json:"roman"`
+	Warnings []warningJSON `json:"warnings"`
+}
+
+// warningJSON is a Warning as TransliterateJSONL writes it
+type warningJSON struct {
+	Kind    string `json:"kind"`
+	Text    string `json:"text,omitempty"`
+	Offset  int    `json:"offset"`
+	Message string `json:"message"`
+}
+
+// TransliterateJSONL reads r line by line and writes one JSON object per
+// line to w as soon as the line is romanized, for pipelines (jq) and live
+// subtitle processing:
+//
+//	{"line": 1, "text": "ไปไหน", "roman": "bpai nǎi", "warnings": []}
+//
+// Every line gets an object, lines without Thai included; line numbers
+// start at 1 and a leading BOM is dropped. Each object is written with a
+// single Write, so w needs no buffering; a writer that buffers has to
+// flush on Write for the objects to come out immediately.
+func TransliterateJSONL(r io.Reader, w io.Writer, opts Options) error {
+	return TransliterateJSONLContext(context.Background(), r, w, opts)
+}
+
+// TransliterateJSONLContext is TransliterateJSONL with a context bounding
+// the pythainlp calls of EngineAuto, checked between lines and words.
+// Returns the context's error if it ends before the end of r.
+func TransliterateJSONLContext(ctx context.Context, r io.Reader, w io.Writer, opts Options) error {
+	br := bufio.NewReader(r)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for n := 1; ; n++ {
+		line, readErr := br.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+		if readErr == io.EOF && line == "" {
+			return nil
+		}
+		if n == 1 {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+		body, _ := splitLineEnding(line)
+		res, err := TransliterateTextDetailedContext(ctx, body, opts)
+		if err != nil {
+			return err
+		}
+		out := lineJSON{Line: n, Text: body, Roman: res.Roman, Warnings: []warningJSON{}}
+		for _, wn := range res.Warnings {
+			out.Warnings = append(out.Warnings, warningJSON{wn.Kind.String(), wn.Text, wn.Offset, wn.Message})
+		}
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+	}
+}
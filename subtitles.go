@@ -0,0 +1,146 @@
+package paiboonizer
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// SubtitleEvent is a parsed subtitle: its display interval and its text,
+// markup included (<i>, {\an8}, \N)
+type SubtitleEvent struct {
+	Start, End time.Duration
+	Text       string
+}
+
+// RomanizedEvent is a subtitle event with its romanization and the
+// estimated timing of each word
+type RomanizedEvent struct {
+	SubtitleEvent
+	Roman string // As TransliterateText, markup kept
+	Words []TimedWord
+}
+
+// TimedWord is a spoken token of a subtitle event with its estimated
+// display interval
+type TimedWord struct {
+	Text   string
+	Roman  string
+	Kind   TokenKind // KindWord, KindParticle, KindNumber or KindForeign
+	Offset int       // Position of Text in the event text, in runes
+	Start  time.Duration
+	End    time.Duration
+}
+
+// RomanizeSubtitleEvents romanizes subtitle events for captions synced to
+// the audio. Each event's interval is shared among its words in
+// proportion to their syllable count, a rough estimate of speech rate that
+// is good enough for karaoke-style highlighting: Thai subtitles have no
+// word timing and speech runs at a fairly even syllable rate. Whitespace,
+// punctuation and markup take no time. The intervals of an event's words
+// follow each other from Start to End.
+func RomanizeSubtitleEvents(events []SubtitleEvent, opts Options) []RomanizedEvent {
+	result, _ := RomanizeSubtitleEventsContext(context.Background(), events, opts)
+	return result
+}
+
+// RomanizeSubtitleEventsContext is RomanizeSubtitleEvents with a context
+// bounding the pythainlp calls of EngineAuto, checked between words.
+// Returns the context's error if it ends before every event is romanized.
+func RomanizeSubtitleEventsContext(ctx context.Context, events []SubtitleEvent, opts Options) ([]RomanizedEvent, error) {
+	result := make([]RomanizedEvent, len(events))
+	for i, ev := range events {
+		roman, err := TransliterateTextContext(ctx, ev.Text, opts)
+		if err != nil {
+			return nil, err
+		}
+		tokens, err := TransliterateTokensContext(ctx, ev.Text, opts)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = RomanizedEvent{SubtitleEvent: ev, Roman: roman, Words: timeWords(ev, tokens)}
+	}
+	return result, nil
+}
+
+// timeWords shares the interval of ev among its spoken tokens
+func timeWords(ev SubtitleEvent, tokens []Token) []TimedWord {
+	words := []TimedWord{}
+	weights := []int{}
+	total := 0
+	offset := 0
+	for _, tok := range tokens {
+		switch tok.Kind {
+		case KindWord, KindParticle, KindNumber, KindForeign:
+			w := spokenSyllables(tok.Roman)
+			words = append(words, TimedWord{Text: tok.Text, Roman: tok.Roman, Kind: tok.Kind, Offset: offset})
+			weights = append(weights, w)
+			total += w
+		}
+		offset += len([]rune(tok.Text))
+	}
+	span := ev.End - ev.Start
+	if span < 0 {
+		span = 0
+	}
+	elapsed := 0
+	for i := range words {
+		words[i].Start = ev.Start + span*time.Duration(elapsed)/time.Duration(total)
+		elapsed += weights[i]
+		words[i].End = ev.Start + span*time.Duration(elapsed)/time.Duration(total)
+	}
+	return words
+}
+
+// spokenSyllables estimates the syllables spoken for a romanized token, at
+// least one
+func spokenSyllables(roman string) int {
+	n := 0
+	for _, syl := range romanSyllableSep.Split(roman, -1) {
+		if syl != "" {
+			n++
+		}
+	}
+	return max(n, 1)
+}
+
+// subtitleEventJSON is a RomanizedEvent as WriteSubtitleEventsJSON writes it
+type subtitleEventJSON struct {
+	Start int64           `json:"start_ms"`
+	End   int64           `json:"end_ms"`
+	Text  string          `json:"text"`
+	Roman string          `json:"roman"`
+	Words []timedWordJSON `json:"words"`
+}
+
+// timedWordJSON is a TimedWord as WriteSubtitleEventsJSON writes it
+type timedWordJSON struct {
+	Text   string `json:"text"`
+	Roman  string `json:"roman"`
+	Kind   string `json:"kind"`
+	Offset int    `json:"offset"`
+	Start  int64  `json:"start_ms"`
+	End    int64  `json:"end_ms"`
+}
+
+// WriteSubtitleEventsJSON writes romanized events as a JSON array for
+// caption renderers, times in milliseconds and kinds by name:
+//
+//	[{"start_ms": 1000, "end_ms": 2500, "text": "ไปไหน", "roman": "bpai nǎi",
+//	  "words": [{"text": "ไป", "roman": "bpai", "kind": "word", "offset": 0,
+//	             "start_ms": 1000, "end_ms": 1750}, ...]}, ...]
+func WriteSubtitleEventsJSON(w io.Writer, events []RomanizedEvent) error {
+	out := make([]subtitleEventJSON, len(events))
+	for i, ev := range events {
+		words := make([]timedWordJSON, len(ev.Words))
+		for j, tw := range ev.Words {
+			words[j] = timedWordJSON{tw.Text, tw.Roman, tw.Kind.String(), tw.Offset, tw.Start.Milliseconds(), tw.End.Milliseconds()}
+		}
+		out[i] = subtitleEventJSON{ev.Start.Milliseconds(), ev.End.Milliseconds(), ev.Text, ev.Roman, words}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
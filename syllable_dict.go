@@ -0,0 +1,62 @@
+package paiboonizer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// syllableDictionaryFile is the pre-extracted syllable dictionary embedded
+// by slim builds
+const syllableDictionaryFile = "syllables.tsv"
+
+// SlimBuild reports whether paiboonizer was built with the paiboonizer_slim
+// tag, embedding the syllable dictionary only: LookupDictionary then finds
+// nothing and every word goes through the rules.
+func SlimBuild() bool {
+	return slimBuild
+}
+
+// WriteSyllableDictionary writes the syllable dictionary as extracted from
+// the vocab files, one "thai\troman" line per syllable sorted by Thai. This
+// is the syllables.tsv file embedded by slim builds.
+func WriteSyllableDictionary(w io.Writer) error {
+	ensureDictionaryLoaded()
+	keys := make([]string, 0, len(syllableDict))
+	for k := range syllableDict {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# Syllable dictionary extracted from the vocab files, for slim builds")
+	fmt.Fprintln(bw, "# Generated by gen_syllables.go (go generate), do not edit")
+	for _, k := range keys {
+		fmt.Fprintf(bw, "%s\t%s\n", k, syllableDict[k])
+	}
+	return bw.Flush()
+}
+
+// loadSyllableDictionary loads the pre-extracted syllable dictionary of slim
+// builds. Full builds don't embed it and extract the syllables themselves.
+// Entries are canonicalized like the dictionaries they were extracted from,
+// so that a stale syllables.tsv gives the output of full builds.
+func loadSyllableDictionary() {
+	data, err := syllableFS.ReadFile(syllableDictionaryFile)
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "\t", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if _, exists := syllableDict[parts[0]]; !exists {
+			syllableDict[parts[0]], _ = canonicalRoman(parts[0], parts[1])
+		}
+	}
+}
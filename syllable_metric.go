@@ -0,0 +1,185 @@
+package paiboonizer
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// SyllableOp is how a syllable of the expected romanization was rendered
+type SyllableOp int
+
+const (
+	SyllableMatch        SyllableOp = iota // Same syllable
+	SyllableSubstitution                   // Different syllable in its place
+	SyllableDeletion                       // Missing from the output
+	SyllableInsertion                      // Extra syllable in the output
+	SyllableMerge                          // Two syllables run together (boundary missed)
+	SyllableSplit                          // One syllable cut in two (extra boundary)
+)
+
+// String returns the op name
+func (op SyllableOp) String() string {
+	switch op {
+	case SyllableMatch:
+		return "match"
+	case SyllableSubstitution:
+		return "substitution"
+	case SyllableDeletion:
+		return "deletion"
+	case SyllableInsertion:
+		return "insertion"
+	case SyllableMerge:
+		return "merge"
+	}
+	return "split"
+}
+
+// AlignedSyllable is a step of a syllable alignment. Merged or split
+// syllables are joined with "-"; the missing side of a deletion or
+// insertion is empty.
+type AlignedSyllable struct {
+	Op       SyllableOp
+	Position int // Index of the (first) expected syllable involved
+	Expected string
+	Got      string
+}
+
+// AlignSyllables aligns the syllables of two romanizations of the same
+// word, split on - ~ and spaces, with the fewest edits. Syllables match
+// only if identical, tones included; a merge or split is recognized when
+// the syllables involved spell the same thing without tones, and counts
+// as a boundary error rather than two wrong syllables.
+func AlignSyllables(expected, got string) []AlignedSyllable {
+	exp := splitRomanSyllables(expected)
+	out := splitRomanSyllables(got)
+	n, m := len(exp), len(out)
+	base := func(s string) string { return stripRomanTones(s) }
+
+	// cost[i][j] is the edit cost of aligning exp[i:] with out[j:]
+	cost := make([][]int, n+1)
+	for i := range cost {
+		cost[i] = make([]int, m+1)
+	}
+	for i := n; i >= 0; i-- {
+		for j := m; j >= 0; j-- {
+			switch {
+			case i == n:
+				cost[i][j] = m - j
+				continue
+			case j == m:
+				cost[i][j] = n - i
+				continue
+			}
+			c := min(cost[i+1][j], cost[i][j+1]) + 1
+			if exp[i] == out[j] {
+				c = min(c, cost[i+1][j+1])
+			} else {
+				c = min(c, cost[i+1][j+1]+1)
+			}
+			if i+1 < n && base(exp[i]+exp[i+1]) == base(out[j]) {
+				c = min(c, cost[i+2][j+1]+1)
+			}
+			if j+1 < m && base(exp[i]) == base(out[j]+out[j+1]) {
+				c = min(c, cost[i+1][j+2]+1)
+			}
+			cost[i][j] = c
+		}
+	}
+
+	var steps []AlignedSyllable
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && exp[i] == out[j] && cost[i][j] == cost[i+1][j+1]:
+			steps = append(steps, AlignedSyllable{SyllableMatch, i, exp[i], out[j]})
+			i, j = i+1, j+1
+		case i+1 < n && j < m && base(exp[i]+exp[i+1]) == base(out[j]) && cost[i][j] == cost[i+2][j+1]+1:
+			steps = append(steps, AlignedSyllable{SyllableMerge, i, exp[i] + "-" + exp[i+1], out[j]})
+			i, j = i+2, j+1
+		case i < n && j+1 < m && base(exp[i]) == base(out[j]+out[j+1]) && cost[i][j] == cost[i+1][j+2]+1:
+			steps = append(steps, AlignedSyllable{SyllableSplit, i, exp[i], out[j] + "-" + out[j+1]})
+			i, j = i+1, j+2
+		case i < n && j < m && cost[i][j] == cost[i+1][j+1]+1:
+			steps = append(steps, AlignedSyllable{SyllableSubstitution, i, exp[i], out[j]})
+			i, j = i+1, j+1
+		case i < n && cost[i][j] == cost[i+1][j]+1:
+			steps = append(steps, AlignedSyllable{SyllableDeletion, i, exp[i], ""})
+			i++
+		default:
+			steps = append(steps, AlignedSyllable{SyllableInsertion, i, "", out[j]})
+			j++
+		}
+	}
+	return steps
+}
+
+// splitRomanSyllables splits a romanization on syllable separators
+func splitRomanSyllables(roman string) []string {
+	var syllables []string
+	for _, s := range romanSyllableSep.Split(norm.NFC.String(strings.TrimSpace(roman)), -1) {
+		if s != "" {
+			syllables = append(syllables, s)
+		}
+	}
+	return syllables
+}
+
+// PositionAccuracy counts the expected syllables at one position of
+// their word and how many were rendered right
+type PositionAccuracy struct {
+	Total   int
+	Correct int
+}
+
+// SyllableStats aggregates syllable alignments over many words
+type SyllableStats struct {
+	Words          int // Words aligned
+	Syllables      int // Expected syllables
+	Correct        int // Expected syllables matched
+	BoundaryErrors int // Merges and splits
+	// ByPosition holds the accuracy by syllable position in the word:
+	// first syllable at index 0
+	ByPosition []PositionAccuracy
+}
+
+// Add accumulates the alignment of one word
+func (s *SyllableStats) Add(alignment []AlignedSyllable) {
+	s.Words++
+	count := func(pos, total int, correct bool) {
+		for len(s.ByPosition) <= pos+total-1 {
+			s.ByPosition = append(s.ByPosition, PositionAccuracy{})
+		}
+		for p := pos; p < pos+total; p++ {
+			s.ByPosition[p].Total++
+			if correct {
+				s.ByPosition[p].Correct++
+			}
+		}
+		s.Syllables += total
+		if correct {
+			s.Correct += total
+		}
+	}
+	for _, a := range alignment {
+		switch a.Op {
+		case SyllableMatch:
+			count(a.Position, 1, true)
+		case SyllableSubstitution, SyllableDeletion, SyllableSplit:
+			count(a.Position, 1, false)
+		case SyllableMerge:
+			count(a.Position, 2, false)
+		}
+		if a.Op == SyllableMerge || a.Op == SyllableSplit {
+			s.BoundaryErrors++
+		}
+	}
+}
+
+// Accuracy returns the percentage of expected syllables matched
+func (s SyllableStats) Accuracy() float64 {
+	if s.Syllables == 0 {
+		return 0
+	}
+	return float64(s.Correct) * 100 / float64(s.Syllables)
+}
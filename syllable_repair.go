@@ -0,0 +1,99 @@
+package paiboonizer
+
+import "strings"
+
+// ExtractSyllables finds syllable boundaries with a handful of local rules
+// that sometimes cut a syllable in the middle, leaving a piece that starts
+// with a vowel or tone mark (ปีก|่|อน) or ends with a leading vowel. Such
+// pieces can't be romanized on their own; the checks below catch them so
+// that the word is segmented again instead.
+
+// dependentMarks are the vowels and signs written after the consonant they
+// belong to, which no syllable starts with
+const dependentMarks = "ะัาำิีึืุู็่้๊๋์ํ"
+
+// brokenSyllable reports whether syl can't be a syllable of its own: it
+// starts with a dependent vowel or a tone mark, or ends with a leading
+// vowel
+func brokenSyllable(syl string) bool {
+	runes := []rune(syl)
+	if len(runes) == 0 {
+		return false
+	}
+	return strings.ContainsRune(dependentMarks, runes[0]) || isLeadingVowel(string(runes[len(runes)-1]))
+}
+
+// countBroken returns the number of broken syllables
+func countBroken(syllables []string) int {
+	n := 0
+	for _, syl := range syllables {
+		if brokenSyllable(syl) {
+			n++
+		}
+	}
+	return n
+}
+
+// repairSyllables returns the syllables of word found by ExtractSyllables,
+// segmented again when one of them is broken: with the boundaries of the
+// comprehensive engine, then, if pieces are still broken, by merging each
+// into its neighbour (a dependent mark with the syllable before it, a
+// leading vowel with the one after it)
+func repairSyllables(word string, syllables []string) []string {
+	if countBroken(syllables) == 0 {
+		return syllables
+	}
+	relaxed := comprehensiveSyllables(word)
+	if countBroken(relaxed) <= countBroken(syllables) {
+		syllables = relaxed
+	}
+	return mergeBrokenSyllables(syllables)
+}
+
+// comprehensiveSyllables splits word at the syllable boundaries of the
+// comprehensive engine, without its dictionaries
+func comprehensiveSyllables(word string) []string {
+	runes := []rune(word)
+	var syllables []string
+	for i := 0; i < len(runes); {
+		end := findSyllableEndComprehensive(runes, i)
+		if end <= i {
+			end = i + 1
+		}
+		syllables = append(syllables, string(runes[i:end]))
+		i = end
+	}
+	return syllables
+}
+
+// mergeBrokenSyllables joins every broken syllable to its neighbour
+func mergeBrokenSyllables(syllables []string) []string {
+	merged := []string{}
+	carry := ""
+	for _, syl := range syllables {
+		syl = carry + syl
+		carry = ""
+		runes := []rune(syl)
+		switch {
+		case strings.ContainsRune(dependentMarks, runes[0]) && len(merged) > 0:
+			merged[len(merged)-1] += syl
+			if isLeadingVowel(string(runes[len(runes)-1])) {
+				// The leading vowel still belongs to the next syllable
+				last := []rune(merged[len(merged)-1])
+				merged[len(merged)-1] = string(last[:len(last)-1])
+				carry = string(last[len(last)-1])
+			}
+		case isLeadingVowel(string(runes[len(runes)-1])):
+			if len(runes) > 1 {
+				merged = append(merged, string(runes[:len(runes)-1]))
+			}
+			carry = string(runes[len(runes)-1])
+		default:
+			merged = append(merged, syl)
+		}
+	}
+	if carry != "" {
+		merged = append(merged, carry)
+	}
+	return merged
+}
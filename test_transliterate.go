@@ -2,8 +2,8 @@ package paiboonizer
 
 import (
 	"bufio"
-	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -23,68 +23,32 @@ func testTransliterate() {
 	}
 	defer output.Close()
 
-	scanner := bufio.NewScanner(input)
+	// Header lines (#) are kept as written, the others romanized by
+	// TransliterateStream
+	reader := bufio.NewReader(input)
 	writer := bufio.NewWriter(output)
 	defer writer.Flush()
-
-	lineNum := 0
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
-		
-		// Skip BOM if present
-		if lineNum == 1 && strings.HasPrefix(line, "\ufeff") {
-			line = strings.TrimPrefix(line, "\ufeff")
-		}
-		
-		// Skip empty lines or preserve them
-		if line == "" {
-			writer.WriteString("\n")
-			continue
-		}
-		
-		// Check if line starts with # (header) or is not Thai - preserve as is
-		if strings.HasPrefix(line, "#") || !containsThai(line) {
-			writer.WriteString(line + "\n")
-			continue
+	for {
+		line, readErr := reader.ReadString('\n')
+		line = strings.TrimPrefix(line, "\ufeff")
+		var err error
+		if strings.HasPrefix(line, "#") {
+			_, err = writer.WriteString(line)
+		} else {
+			err = TransliterateStream(strings.NewReader(line), writer, DefaultOptions())
 		}
-		
-		// Transliterate Thai text
-		result := ""
-		if globalManager != nil && globalManager.nlpManager != nil {
-			ctx := context.Background()
-			tokens, err := globalManager.nlpManager.Tokenize(ctx, line)
-			if err == nil && tokens != nil && len(tokens.Raw) > 0 {
-				// Tokenize and transliterate each word
-				results := []string{}
-				for _, token := range tokens.Raw {
-					if token == " " || token == "" {
-						continue
-					}
-					// Use the main transliteration function with dictionary
-					trans := TransliterateWordRulesOnly(token)
-					if trans != "" {
-						results = append(results, trans)
-					}
-				}
-				result = strings.Join(results, " ")
-			}
+		if err != nil {
+			fmt.Printf("Error writing test.romanized.txt: %v\n", err)
+			return
 		}
-		
-		// Fallback if no result
-		if result == "" {
-			result = TransliterateWordRulesOnly(line)
+		if readErr == io.EOF {
+			break
 		}
-		
-		writer.WriteString(result + "\n")
-		
-		// Progress indicator
-		if lineNum % 50 == 0 {
-			fmt.Printf("Processed %d lines...\n", lineNum)
+		if readErr != nil {
+			fmt.Printf("Error reading test.txt: %v\n", readErr)
+			return
 		}
 	}
-	
-	fmt.Printf("Transliteration complete! Processed %d lines.\n", lineNum)
 	fmt.Println("Output saved to test.romanized.txt")
 }
 
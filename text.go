@@ -0,0 +1,545 @@
+package paiboonizer
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tassa-yoniso-manasi-karoto/paiboonizer/thai"
+)
+
+// tokenKind classifies the runs produced by the text pipeline tokenizer
+type tokenKind int
+
+const (
+	tokenThai   tokenKind = iota // Thai word (romanized)
+	tokenSpace                   // Whitespace between tokens
+	tokenOther                   // Anything else, passed through verbatim
+	tokenMarkup                  // ASS override block or HTML tag, verbatim
+)
+
+// textToken is a single unit of the text pipeline
+type textToken struct {
+	text  string
+	kind  tokenKind
+	roman string // Fixed by Options.Overrides, empty otherwise
+	// joined marks a chunk of a long unknown span (see chunkUnknown),
+	// written right after the previous chunk as one word
+	joined bool
+}
+
+// maxWordLen caps the dictionary lookahead of segmentThai, in runes
+const maxWordLen = 20
+
+const (
+	// maxChunkLen caps the unknown spans segmentThai leaves to the rules,
+	// in runes: Thai without spaces or dictionary words can run for
+	// thousands of runes, past the pythainlp request limits and the
+	// lengths the syllable matcher expects
+	maxChunkLen = 100
+	// chunkOverlap is how far past the limit syllableCut segments to find
+	// where to cut, in runes
+	chunkOverlap = 20
+	// maxPythainlpRequest caps the text ThaiToRoman sends to pythainlp in
+	// one request, in runes (see pythainlpChunks)
+	maxPythainlpRequest = 2000
+)
+
+// TransliterateText romanizes a line of running text without pythainlp.
+// Thai runs are split into words by longest dictionary match and each word
+// goes through TransliterateWordWithOptions; Latin text, digits and
+// punctuation pass through unchanged. Words are separated by single spaces
+// and whitespace in the input is collapsed to single spaces, unless
+// opts.PreserveWhitespace is set.
+//
+// Subtitle and HTML formatting is kept in place around the romanized text:
+// ASS override blocks ({\i1}), the ASS escapes \N, \n and \h, and HTML
+// tags (<i>, </font>) pass through verbatim and are never romanized. When
+// a tag sits between two Thai words the separating space goes after
+// closing tags and before opening ones, so "ไม่<i>ดี</i>" gives
+// "mâi <i>dii</i>".
+//
+// With opts.ConnectedSpeech set, function words are given their reduced
+// connected speech reading before a content word (see
+// DefaultConnectedSpeech). opts.Particles marks sentence-final particles.
+func TransliterateText(text string, opts Options) string {
+	return TransliterateTextDetailed(text, opts).Roman
+}
+
+// TransliterateTextDetailed is TransliterateText, also returning the pieces
+// of Thai the rules romanized to nothing, with their offset in text, and
+// warnings: unknown words, suspicious spellings (see WarningKind) and
+// dropped spans. opts.OnFailure sets what replaces dropped spans in the
+// output.
+func TransliterateTextDetailed(text string, opts Options) TextResult {
+	res, _ := TransliterateTextDetailedContext(context.Background(), text, opts)
+	return res
+}
+
+// TransliterateTextContext is TransliterateText with a context bounding the
+// pythainlp calls of EngineAuto, checked between words. Returns the
+// context's error if it ends before the text is romanized.
+func TransliterateTextContext(ctx context.Context, text string, opts Options) (string, error) {
+	res, err := TransliterateTextDetailedContext(ctx, text, opts)
+	return res.Roman, err
+}
+
+// TransliterateTextDetailedContext is TransliterateTextDetailed with a
+// context, as TransliterateTextContext
+func TransliterateTextDetailedContext(ctx context.Context, text string, opts Options) (TextResult, error) {
+	ensureDictionaryLoaded()
+	var b strings.Builder
+	var result TextResult
+	if opts.NormalizeInput {
+		text = normalizeInput(text)
+	}
+	if opts.FixLayoutTypos {
+		text, result.Warnings = fixLayoutTypos(text)
+	}
+	if roman, ok := opts.Overrides.line(text); ok {
+		roman = opts.OutputForm.forced(roman)
+		result.Roman = roman
+		result.Alignment = []AlignedSpan{{text, 0, utf8.RuneCountInString(text), 0, utf8.RuneCountInString(roman)}}
+		return result, nil
+	}
+	result.Warnings = append(result.Warnings, suspiciousInput(text)...)
+	offset := 0
+	pendingSpace := false
+	prevThai := false
+	lastRoman := ""
+	// lastWritten is lastRoman as written, rewritten by SensitivePolicy
+	lastWritten := ""
+	// lineThai is the Thai of the current line since its last other
+	// token, for Punctuate
+	lineThai := ""
+	endLine := func() {
+		if opts.Punctuate && lineThai != "" {
+			b.WriteString(terminalMark(lineThai))
+		}
+		lineThai = ""
+	}
+	// Markup right after a Thai word is held until the next token shows
+	// whether a separating space has to be placed inside it
+	var markup []string
+	flushMarkup := func() {
+		for _, m := range markup {
+			b.WriteString(m)
+		}
+		markup = nil
+	}
+	tokens := tokenizeText(text, opts)
+	for i, tok := range tokens {
+		start := offset
+		offset += utf8.RuneCountInString(tok.text)
+		switch tok.kind {
+		case tokenSpace:
+			if strings.ContainsAny(tok.text, "\n\r") {
+				endLine()
+			}
+		case tokenThai:
+			lineThai += tok.text
+		case tokenOther:
+			lineThai = ""
+		}
+		switch {
+		case tok.kind == tokenSpace && opts.PreserveWhitespace:
+			flushMarkup()
+			b.WriteString(tok.text)
+			prevThai = false
+			continue
+		case tok.kind == tokenSpace:
+			flushMarkup()
+			pendingSpace = b.Len() > 0
+			continue
+		case tok.kind == tokenMarkup && pendingSpace:
+			b.WriteByte(' ')
+			b.WriteString(tok.text)
+			pendingSpace, prevThai = false, false
+			continue
+		case tok.kind == tokenMarkup && prevThai:
+			markup = append(markup, tok.text)
+			continue
+		case tok.kind == tokenMarkup:
+			b.WriteString(tok.text)
+			continue
+		}
+		if tok.kind == tokenThai {
+			if err := ctx.Err(); err != nil {
+				return TextResult{}, err
+			}
+		}
+		other := tok.text
+		if tok.kind == tokenOther {
+			if other = rewriteForeign(tok.text, opts.Foreign); other == "" {
+				// Dropped: the spaces around it collapse into one
+				continue
+			}
+			other = opts.OutputForm.forced(other)
+		}
+		// Mai yamok with no word before it to repeat fails like Thai the
+		// rules can't romanize
+		dangling := tok.text == "ๆ" && lastRoman == ""
+		if dangling {
+			result.Dropped = append(result.Dropped, DroppedSpan{tok.text, start})
+			if opts.OnFailure == FailureDrop {
+				continue
+			}
+		}
+		if prevThai && tok.kind == tokenThai && len(markup) > 0 && !pendingSpace {
+			writeSeparatedMarkup(&b, markup, opts.Separator.between(" "))
+			markup = nil
+		} else {
+			flushMarkup()
+			if pendingSpace {
+				b.WriteByte(' ')
+			} else if prevThai && tok.joined {
+				b.WriteString(chunkJoint(lastRoman, opts))
+			} else if prevThai && tok.kind == tokenThai {
+				b.WriteString(opts.Separator.between(" "))
+			}
+		}
+		romanStart := b.Len()
+		switch {
+		case tok.kind != tokenThai:
+			b.WriteString(other)
+		case dangling:
+			b.WriteString(failureText(tok.text, opts.OnFailure))
+		case tok.text == "ๆ":
+			// Mai yamok repeats the previous word
+			b.WriteString(lastWritten)
+		default:
+			if tok.roman != "" {
+				lastRoman = tok.roman
+			} else if reduced, ok := connectedSpeechForm(tok.text, nextThaiWord(tokens, i), opts); ok {
+				lastRoman = reduced
+			} else {
+				var wordDropped []DroppedSpan
+				var known bool
+				lastRoman, wordDropped, known = transliterateWordDetailed(ctx, tok.text, opts)
+				result.Dropped = append(result.Dropped, shiftDroppedSpans(wordDropped, start)...)
+				if !known {
+					result.Warnings = append(result.Warnings, Warning{WarnUnknownWord, tok.text, start, "romanized by the rules"})
+				}
+			}
+			lastWritten = lastRoman
+			if _, ok := opts.Sensitive.Category(tok.text); ok {
+				lastWritten = opts.SensitivePolicy.Apply(lastRoman)
+			}
+			if opts.Particles != ParticlePlain && isSentenceFinal(tokens, i) {
+				b.WriteString(markParticle(lastWritten, opts))
+			} else {
+				b.WriteString(lastWritten)
+			}
+		}
+		result.Alignment = append(result.Alignment, AlignedSpan{tok.text, start, offset, romanStart, b.Len()})
+		pendingSpace = false
+		prevThai = tok.kind == tokenThai
+	}
+	endLine()
+	flushMarkup()
+	result.Roman = b.String()
+	romanOffsetsToRunes(result.Roman, result.Alignment)
+	result.Warnings = append(result.Warnings, droppedWarnings(result.Dropped)...)
+	if err := ctx.Err(); err != nil {
+		return TextResult{}, err
+	}
+	return result, nil
+}
+
+// nextThaiWord returns the Thai word following tokens[i] across whitespace
+// and markup, "" if something else comes first
+func nextThaiWord(tokens []textToken, i int) string {
+	for _, tok := range tokens[i+1:] {
+		switch tok.kind {
+		case tokenSpace, tokenMarkup:
+			continue
+		case tokenThai:
+			return tok.text
+		}
+		return ""
+	}
+	return ""
+}
+
+// writeSeparatedMarkup writes the markup found between two Thai words with
+// the word separator sep after the leading closing tags. Line breaks
+// already separate the words and get no separator.
+func writeSeparatedMarkup(b *strings.Builder, markup []string, sep string) {
+	split := 0
+	for split < len(markup) && isClosingMarkup(markup[split]) {
+		split++
+	}
+	spaced := false
+	for _, m := range markup {
+		if m == `\N` || m == `\n` {
+			spaced = true
+		}
+	}
+	for i, m := range markup {
+		if i == split && !spaced {
+			b.WriteString(sep)
+		}
+		b.WriteString(m)
+	}
+	if split == len(markup) && !spaced {
+		b.WriteString(sep)
+	}
+}
+
+// markupRegex matches the formatting passed through by TransliterateText:
+// ASS override blocks, the ASS \N, \n and \h escapes and HTML tags
+var markupRegex = regexp.MustCompile(`^(?:\{\\[^{}]*\}|\\[Nnh]|</?[A-Za-z][^<>]*>)`)
+
+// closingMarkupRegex matches HTML end tags and ASS blocks that only switch
+// styles off ({\i0}, {\b0}, {\r})
+var closingMarkupRegex = regexp.MustCompile(`^(?:</[^<>]*>|\{(?:\\(?:[ibus]0|r))+\})$`)
+
+// isClosingMarkup reports whether markup ends a formatted span
+func isClosingMarkup(markup string) bool {
+	return closingMarkupRegex.MatchString(markup)
+}
+
+// tokenizeText splits text into whitespace, non-Thai runs and Thai words
+func tokenizeText(text string, opts Options) []textToken {
+	tokens := []textToken{}
+	for _, run := range splitScripts(text) {
+		if run.kind != tokenThai {
+			tokens = append(tokens, run)
+			continue
+		}
+		tokens = append(tokens, segmentThai(run.text, opts)...)
+	}
+	return tokens
+}
+
+// splitScripts groups text into runs of Thai, whitespace, markup and
+// everything else. A dot directly following Thai text stays in the Thai run
+// so that abbreviations (พ.ศ., กทม.) reach the segmenter in one piece.
+func splitScripts(text string) []textToken {
+	runs := []textToken{}
+	var current []rune
+	currentKind := tokenOther
+	flush := func() {
+		if len(current) > 0 {
+			runs = append(runs, textToken{text: string(current), kind: currentKind})
+			current = nil
+		}
+	}
+	skipTo := 0
+	for i, r := range text {
+		if i < skipTo {
+			continue
+		}
+		if m := markupRegex.FindString(text[i:]); m != "" {
+			flush()
+			runs = append(runs, textToken{text: m, kind: tokenMarkup})
+			currentKind = tokenMarkup
+			skipTo = i + len(m)
+			continue
+		}
+		kind := tokenOther
+		switch {
+		case isThaiRune(r):
+			kind = tokenThai
+		case unicode.IsSpace(r):
+			kind = tokenSpace
+		case r == '.' && currentKind == tokenThai && len(current) > 0:
+			kind = tokenThai
+		}
+		if kind != currentKind {
+			flush()
+			currentKind = kind
+		}
+		current = append(current, r)
+	}
+	flush()
+	return runs
+}
+
+// segmentThai splits a run of Thai text into words by longest dictionary
+// match. Spans no dictionary word covers are grouped into a single unknown
+// word and left to the rule engine, cut into chunks when too long (see
+// chunkUnknown). Abbreviations are kept whole when acronym handling is
+// enabled, ๆ gets a token of its own, and Thai numbers and stray dots
+// come out as tokenOther, passed through like the other digits.
+func segmentThai(run string, opts Options) []textToken {
+	runes := []rune(run)
+	tokens := []textToken{}
+	unknownStart := -1
+	flushUnknown := func(end int) {
+		if unknownStart >= 0 {
+			for i, chunk := range chunkUnknown(runes[unknownStart:end]) {
+				tokens = append(tokens, textToken{text: string(chunk), kind: tokenThai, joined: i > 0})
+			}
+			unknownStart = -1
+		}
+	}
+
+	for i := 0; i < len(runes); {
+		if fixed, n := opts.Overrides.tokensAt(runes, i); n > 0 {
+			flushUnknown(i)
+			tokens = append(tokens, fixed...)
+			i += n
+			continue
+		}
+		if n := thaiNumberAt(runes, i); n > 0 {
+			flushUnknown(i)
+			tokens = append(tokens, textToken{text: string(runes[i : i+n]), kind: tokenOther})
+			i += n
+			continue
+		}
+		if canStartWord(runes, i) {
+			if opts.DetectAcronyms || opts.SpellOut {
+				if abbr := matchAcronym(runes[i:]); abbr != "" {
+					flushUnknown(i)
+					tokens = append(tokens, textToken{text: abbr, kind: tokenThai})
+					i += len([]rune(abbr))
+					continue
+				}
+			}
+			if n := longestWordAt(runes, i, opts); n > 0 {
+				flushUnknown(i)
+				tokens = append(tokens, textToken{text: string(runes[i : i+n]), kind: tokenThai})
+				i += n
+				continue
+			}
+		}
+		if runes[i] == '.' || runes[i] == 'ๆ' {
+			flushUnknown(i)
+			kind := tokenOther
+			if runes[i] == 'ๆ' {
+				kind = tokenThai
+			}
+			tokens = append(tokens, textToken{text: string(runes[i]), kind: kind})
+			i++
+			continue
+		}
+		if unknownStart < 0 {
+			unknownStart = i
+		}
+		i++
+	}
+	flushUnknown(len(runes))
+	return tokens
+}
+
+// longestWordAt returns the rune length of the longest dictionary word
+// starting at runes[start], or 0. Single-rune entries are ignored since
+// they mostly are letter names that would chop real words apart.
+func longestWordAt(runes []rune, start int, opts Options) int {
+	maxLen := len(runes) - start
+	if maxLen > maxWordLen {
+		maxLen = maxWordLen
+	}
+	for length := maxLen; length > 1; length-- {
+		if isKnownWord(string(runes[start:start+length]), opts) {
+			return length
+		}
+	}
+	return 0
+}
+
+// isKnownWord reports whether word is in one of the dictionary tiers
+// enabled by opts: the tables of opts.Layers when set, so that words are
+// split where they are looked up
+func isKnownWord(word string, opts Options) bool {
+	if opts.Layers != nil {
+		for _, l := range opts.Layers {
+			if _, ok := l.lookup(word, opts); ok {
+				return true
+			}
+		}
+		return false
+	}
+	if opts.RoyalVocabulary {
+		if _, ok := royalVocabulary[word]; ok {
+			return true
+		}
+	}
+	_, ok := LookupDictionary(word)
+	return ok
+}
+
+// canStartWord reports whether a word may begin at runes[i]: on a consonant
+// or leading vowel, and not right after a leading vowel
+func canStartWord(runes []rune, i int) bool {
+	r := string(runes[i])
+	if !isConsonant(r) && !isLeadingVowel(r) {
+		return false
+	}
+	return i == 0 || !isLeadingVowel(string(runes[i-1]))
+}
+
+// isThaiRune reports whether r is in the Thai Unicode block
+func isThaiRune(r rune) bool {
+	return thai.IsThai(r)
+}
+
+// chunkUnknown cuts an unknown span longer than maxChunkLen into chunks
+// of at most maxChunkLen runes, each cut on a syllable boundary (see
+// syllableCut)
+func chunkUnknown(runes []rune) [][]rune {
+	var chunks [][]rune
+	for len(runes) > maxChunkLen {
+		cut := syllableCut(runes, maxChunkLen)
+		chunks = append(chunks, runes[:cut])
+		runes = runes[cut:]
+	}
+	return append(chunks, runes)
+}
+
+// pythainlpChunks cuts text into the pieces ThaiToRoman sends to
+// pythainlp, of at most maxPythainlpRequest runes. Each cut falls after
+// the last whitespace within the limit, or on a syllable boundary in text
+// without spaces, so that no word is split between two requests.
+func pythainlpChunks(text string) []string {
+	runes := []rune(text)
+	var chunks []string
+	for len(runes) > maxPythainlpRequest {
+		cut := 0
+		for i := maxPythainlpRequest; i > 0 && cut == 0; i-- {
+			if unicode.IsSpace(runes[i-1]) {
+				cut = i
+			}
+		}
+		if cut == 0 {
+			cut = syllableCut(runes, maxPythainlpRequest)
+		}
+		chunks = append(chunks, string(runes[:cut]))
+		runes = runes[cut:]
+	}
+	return append(chunks, string(runes))
+}
+
+// syllableCut returns where to cut runes, longer than limit, so that the
+// first piece is at most limit runes: on the last syllable boundary
+// within the limit, found by segmenting chunkOverlap runes past it so
+// that the syllable across the limit is seen whole, or at the limit when
+// there is no boundary there
+func syllableCut(runes []rune, limit int) int {
+	window := runes[:min(len(runes), limit+chunkOverlap)]
+	cut, pos := 0, 0
+	for _, syl := range ExtractSyllables(string(window)) {
+		if pos += utf8.RuneCountInString(syl); pos > limit {
+			break
+		}
+		cut = pos
+	}
+	if cut == 0 {
+		cut = limit
+	}
+	return cut
+}
+
+// chunkJoint returns what is written between the romanizations of two
+// chunks of an unknown span, prev being the first: the syllable
+// separator of opts, so that the chunks read as the one word they are
+func chunkJoint(prev string, opts Options) string {
+	join := opts.Separator.joinPolicy(opts.Join)
+	if join == JoinNone {
+		return ""
+	}
+	return opts.Separator.Apply(join.separator(prev))
+}
@@ -0,0 +1,210 @@
+package paiboonizer
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+// The Thai words of the markup tests are written apart or split by markup,
+// so that slim builds, which can't split Thai runs, romanize them the same
+func TestTransliterateTextMarkup(t *testing.T) {
+	tests := []struct {
+		name, text, want string
+	}{
+		{"ass override blocks", `{\i1}ไม่ ดี{\i0}`, `{\i1}mâi dii{\i0}`},
+		{"html tag", "<i>ไม่</i>", "<i>mâi</i>"},
+		{"nested tags", "<b><i>ไม่ ดี</i></b>", "<b><i>mâi dii</i></b>"},
+		{"tag with attributes", `<font color="red"><i>ไม่</i></font>`, `<font color="red"><i>mâi</i></font>`},
+		{"opening tag between words", "ไม่<i>ดี</i>", "mâi <i>dii</i>"},
+		{"closing tag between words", "ไม่</i>ดี", "mâi</i> dii"},
+		{"override block between words", `ไม่{\b1}ดี`, `mâi {\b1}dii`},
+		{"nested override blocks", `{\i1}ไม่{\b1}ดี{\b0}{\i0}`, `{\i1}mâi {\b1}dii{\b0}{\i0}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TransliterateText(tt.text, DefaultOptions()); got != tt.want {
+				t.Errorf("TransliterateText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransliterateTokensMarkup(t *testing.T) {
+	tokens := TransliterateTokens("ไม่<i>ดี</i>", DefaultOptions())
+	want := []struct {
+		text string
+		kind TokenKind
+	}{{"ไม่", KindWord}, {"<i>", KindMarkup}, {"ดี", KindWord}, {"</i>", KindMarkup}}
+	if len(tokens) != len(want) {
+		t.Fatalf("got %d tokens %v, want %d", len(tokens), tokens, len(want))
+	}
+	for i, w := range want {
+		if tokens[i].Text != w.text || tokens[i].Kind != w.kind {
+			t.Errorf("token %d = %q %v, want %q %v", i, tokens[i].Text, tokens[i].Kind, w.text, w.kind)
+		}
+		if w.kind == KindMarkup && tokens[i].Roman != w.text {
+			t.Errorf("markup token %d romanized as %q", i, tokens[i].Roman)
+		}
+	}
+}
+
+// longUnknownInputs are synthetic runs of thousands of runes without a
+// dictionary word, left to the rules as one unknown span
+var longUnknownInputs = []string{
+	strings.Repeat("ไศ๊ย", 600),
+	strings.Repeat("เฟี๋ยป", 1000),
+	strings.Repeat("หร๊า", 800),
+}
+
+func TestChunkUnknownCoversInput(t *testing.T) {
+	for _, in := range longUnknownInputs {
+		chunks := chunkUnknown([]rune(in))
+		var joined strings.Builder
+		for i, chunk := range chunks {
+			if len(chunk) == 0 || len(chunk) > maxChunkLen {
+				t.Errorf("chunk %d of %d runes, want 1 to %d", i, len(chunk), maxChunkLen)
+			}
+			joined.WriteString(string(chunk))
+		}
+		if joined.String() != in {
+			t.Errorf("chunks of a %d rune input don't cover it exactly", len([]rune(in)))
+		}
+	}
+}
+
+func TestTransliterateTextLongUnknownSpan(t *testing.T) {
+	for _, in := range longUnknownInputs {
+		tokens := segmentThai(in, DefaultOptions())
+		if len(tokens) < 2 {
+			t.Fatalf("a %d rune input gave %d tokens, want it chunked", len([]rune(in)), len(tokens))
+		}
+		for i, tok := range tokens {
+			if tok.kind != tokenThai || tok.joined != (i > 0) {
+				t.Fatalf("token %d %q: kind %v joined %v, want chunks of one unknown word", i, tok.text, tok.kind, tok.joined)
+			}
+		}
+
+		res := TransliterateTextDetailed(in, DefaultOptions())
+		if strings.Contains(res.Roman, " ") {
+			t.Errorf("space at a chunk joint in the romanization of %q...", string([]rune(in)[:12]))
+		}
+		var spans strings.Builder
+		for _, span := range res.Alignment {
+			spans.WriteString(span.Text)
+		}
+		if spans.String() != in {
+			t.Errorf("aligned spans don't cover the input exactly")
+		}
+	}
+}
+
+func TestPythainlpChunks(t *testing.T) {
+	inputs := append([]string{strings.Repeat("สวัสดีครับ ", 500)}, longUnknownInputs...)
+	for _, in := range inputs {
+		chunks := pythainlpChunks(in)
+		if len(chunks) < 2 {
+			t.Errorf("a %d rune input sent in %d request", len([]rune(in)), len(chunks))
+		}
+		for i, chunk := range chunks {
+			if n := len([]rune(chunk)); n == 0 || n > maxPythainlpRequest {
+				t.Errorf("request %d of %d runes, want 1 to %d", i, n, maxPythainlpRequest)
+			}
+			if strings.Contains(in, " ") && i < len(chunks)-1 && !strings.HasSuffix(chunk, " ") {
+				t.Errorf("request %d not cut after a space", i)
+			}
+		}
+		if strings.Join(chunks, "") != in {
+			t.Errorf("requests don't cover the input exactly")
+		}
+	}
+}
+
+func TestTransliterateTextDanglingRepeat(t *testing.T) {
+	tests := []struct {
+		text   string
+		policy FailurePolicy
+		want   string
+	}{
+		{"ๆ", FailurePassThrough, "ๆ"},
+		{"ๆ ๆ", FailureBracket, "[ๆ] [ๆ]"},
+		{"abc ๆ", FailureDrop, "abc"},
+		{"ดีๆ", FailureBracket, "dii dii"},
+	}
+	for _, tt := range tests {
+		opts := DefaultOptions()
+		opts.OnFailure = tt.policy
+		res := TransliterateTextDetailed(tt.text, opts)
+		if res.Roman != tt.want {
+			t.Errorf("TransliterateText(%q) with %v = %q, want %q", tt.text, tt.policy, res.Roman, tt.want)
+		}
+		// Slim builds also warn of unknown words; only the dropped
+		// spans depend on the mai yamok
+		dropped := 0
+		for _, w := range res.Warnings {
+			if w.Kind == WarnDroppedSpan {
+				dropped++
+			}
+		}
+		dangling := tt.text != "ดีๆ"
+		if dangling != (dropped > 0) {
+			t.Errorf("TransliterateText(%q) warnings %v", tt.text, res.Warnings)
+		}
+		tokens := TransliterateTokens(tt.text, opts)
+		if last := tokens[len(tokens)-1]; dangling != (len(last.Dropped) > 0) {
+			t.Errorf("TransliterateTokens(%q) last token %+v", tt.text, last)
+		}
+	}
+}
+
+func TestPreserveWhitespace(t *testing.T) {
+	opts := DefaultOptions()
+	opts.PreserveWhitespace = true
+	tests := []struct {
+		name, text, want string
+	}{
+		{"tabs", "ไม่\tดี", "mâi\tdii"},
+		{"run of spaces", "ไม่   ดี", "mâi   dii"},
+		{"leading and trailing space", "  ไม่ ดี \t", "  mâi dii \t"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TransliterateText(tt.text, opts); got != tt.want {
+				t.Errorf("TransliterateText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			var stream strings.Builder
+			if err := TransliterateStream(strings.NewReader(tt.text+"\n"), &stream, opts); err != nil {
+				t.Fatal(err)
+			}
+			if got := stream.String(); got != tt.want+"\n" {
+				t.Errorf("TransliterateStream(%q) = %q, want %q", tt.text, got, tt.want+"\n")
+			}
+			var columns strings.Builder
+			err := TransliterateColumns(strings.NewReader("x,\""+tt.text+"\"\n"), &columns, ColumnOptions{Columns: []int{2}, Comma: ',', Text: opts})
+			if err != nil {
+				t.Fatal(err)
+			}
+			record, err := csv.NewReader(strings.NewReader(columns.String())).Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := record[1]; got != tt.want {
+				t.Errorf("TransliterateColumns(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayersIgnoreRoyalVocabulary(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Layers = []LookupLayer{LayerOfficial, LayerOpus}
+	without := TransliterateText("พระองค์เสวย", opts)
+	opts.RoyalVocabulary = true
+	if with := TransliterateText("พระองค์เสวย", opts); with != without {
+		t.Errorf("RoyalVocabulary changes the output under Layers: %q, %q without it", with, without)
+	}
+	opts.Layers = []LookupLayer{LayerRoyal, LayerOfficial, LayerOpus}
+	if got := TransliterateText("พระองค์เสวย", opts); got == without {
+		t.Errorf("LayerRoyal doesn't split เสวย: %q", got)
+	}
+}
@@ -0,0 +1,245 @@
+// Package thai exposes the tables of the Thai script paiboonizer works
+// with: the consonants with their tone class and Paiboon sounds, the vowel
+// signs, the tone marks and the other signs, with typed checks on runes,
+// so that other projects don't have to copy them.
+package thai
+
+// Class is the tone class of a consonant, which with the tone mark, the
+// vowel length and the final decides the tone of a syllable
+type Class int
+
+const (
+	ClassNone Class = iota // Not a consonant
+	ClassMid               // อักษรกลาง: ก จ ด ต บ ป อ ...
+	ClassHigh              // อักษรสูง: ข ฉ ถ ผ ฝ ส ห ...
+	ClassLow               // อักษรต่ำ: ค ง ช น ม ย ร ล ว ...
+)
+
+// String returns the class name, as ToneClassOf of paiboonizer does
+func (c Class) String() string {
+	switch c {
+	case ClassMid:
+		return "mid"
+	case ClassHigh:
+		return "high"
+	case ClassLow:
+		return "low"
+	}
+	return ""
+}
+
+// The tone marks and other signs written over or after a consonant
+const (
+	MaiEk       = '่' // Tone mark, low or falling tone
+	MaiTho      = '้' // Tone mark, falling or high tone
+	MaiTri      = '๊' // Tone mark, high tone
+	MaiChattawa = '๋' // Tone mark, rising tone
+	Thanthakhat = '์' // Silences the letter it is written on
+	MaiTaikhu   = '็' // Shortens the vowel
+	Nikhahit    = 'ํ' // Nasal sign, part of ำ
+	Phinthu     = 'ฺ' // Virama of Pali and Sanskrit spellings
+	MaiYamok    = 'ๆ' // Repeats the word before it
+	PaiyanNoi   = 'ฯ' // Abbreviation sign
+)
+
+// BlockStart and BlockEnd bound the Thai Unicode block
+const (
+	BlockStart = '฀'
+	BlockEnd   = '๿'
+)
+
+// consonants holds the 44 consonants in alphabetical order with their
+// tone class
+var consonants = []struct {
+	letter rune
+	class  Class
+}{
+	{'ก', ClassMid}, {'ข', ClassHigh}, {'ฃ', ClassHigh}, {'ค', ClassLow},
+	{'ฅ', ClassLow}, {'ฆ', ClassLow}, {'ง', ClassLow}, {'จ', ClassMid},
+	{'ฉ', ClassHigh}, {'ช', ClassLow}, {'ซ', ClassLow}, {'ฌ', ClassLow},
+	{'ญ', ClassLow}, {'ฎ', ClassMid}, {'ฏ', ClassMid}, {'ฐ', ClassHigh},
+	{'ฑ', ClassLow}, {'ฒ', ClassLow}, {'ณ', ClassLow}, {'ด', ClassMid},
+	{'ต', ClassMid}, {'ถ', ClassHigh}, {'ท', ClassLow}, {'ธ', ClassLow},
+	{'น', ClassLow}, {'บ', ClassMid}, {'ป', ClassMid}, {'ผ', ClassHigh},
+	{'ฝ', ClassHigh}, {'พ', ClassLow}, {'ฟ', ClassLow}, {'ภ', ClassLow},
+	{'ม', ClassLow}, {'ย', ClassLow}, {'ร', ClassLow}, {'ล', ClassLow},
+	{'ว', ClassLow}, {'ศ', ClassHigh}, {'ษ', ClassHigh}, {'ส', ClassHigh},
+	{'ห', ClassHigh}, {'ฬ', ClassLow}, {'อ', ClassMid}, {'ฮ', ClassLow},
+}
+
+// initialSounds maps consonants, and the vowel letters ฤ and ฦ, to their
+// Paiboon romanization at the start of a syllable
+var initialSounds = map[rune]string{
+	'ก': "g", 'ข': "k", 'ฃ': "k", 'ค': "k", 'ฅ': "k", 'ฆ': "k", 'ง': "ng",
+	'จ': "j", 'ฉ': "ch", 'ช': "ch", 'ซ': "s", 'ฌ': "ch", 'ญ': "y", 'ฎ': "d",
+	'ฏ': "dt", 'ฐ': "t", 'ฑ': "t", 'ฒ': "t", 'ณ': "n", 'ด': "d", 'ต': "dt",
+	'ถ': "t", 'ท': "t", 'ธ': "t", 'น': "n", 'บ': "b", 'ป': "bp", 'ผ': "p",
+	'ฝ': "f", 'พ': "p", 'ฟ': "f", 'ภ': "p", 'ม': "m", 'ย': "y", 'ร': "r",
+	'ฤ': "rʉ", 'ล': "l", 'ฦ': "lʉ", 'ว': "w", 'ศ': "s", 'ษ': "s", 'ส': "s",
+	'ห': "h", 'ฬ': "l", 'อ': "", 'ฮ': "h",
+}
+
+// finalSounds maps consonants to their Paiboon romanization at the end of
+// a syllable; ย and ว close the vowel (i, o) and ห, อ and ฮ are silent
+var finalSounds = map[rune]string{
+	'ก': "k", 'ข': "k", 'ฃ': "k", 'ค': "k", 'ฅ': "k", 'ฆ': "k", 'ง': "ng",
+	'จ': "t", 'ฉ': "t", 'ช': "t", 'ซ': "t", 'ฌ': "t", 'ญ': "n", 'ฎ': "t",
+	'ฏ': "t", 'ฐ': "t", 'ฑ': "t", 'ฒ': "t", 'ณ': "n", 'ด': "t", 'ต': "t",
+	'ถ': "t", 'ท': "t", 'ธ': "t", 'น': "n", 'บ': "p", 'ป': "p", 'ผ': "p",
+	'ฝ': "p", 'พ': "p", 'ฟ': "p", 'ภ': "p", 'ม': "m", 'ย': "i", 'ร': "n",
+	'ล': "n", 'ว': "o", 'ศ': "t", 'ษ': "t", 'ส': "t", 'ห': "", 'ฬ': "n",
+	'อ': "", 'ฮ': "",
+}
+
+// vowelSigns are the vowel signs, leading vowels included, in Unicode
+// order
+const vowelSigns = "ะัาำิีึืุูเแโใไๅ"
+
+// leadingVowels are written before the consonant they follow in speech
+const leadingVowels = "เแโใไ"
+
+// toneMarks are the tone marks in Unicode order
+var toneMarks = []rune{MaiEk, MaiTho, MaiTri, MaiChattawa}
+
+// classes indexes consonants by letter
+var classes = func() map[rune]Class {
+	m := make(map[rune]Class, len(consonants))
+	for _, c := range consonants {
+		m[c.letter] = c.class
+	}
+	return m
+}()
+
+// IsThai reports whether r is in the Thai Unicode block
+func IsThai(r rune) bool {
+	return r >= BlockStart && r <= BlockEnd
+}
+
+// IsConsonant reports whether r is one of the 44 consonants. The vowel
+// letters ฤ and ฦ, in the consonant range of the block, aren't (see
+// IsVowelLetter).
+func IsConsonant(r rune) bool {
+	_, ok := classes[r]
+	return ok
+}
+
+// IsVowelLetter reports whether r is ฤ or ฦ, letters written like
+// consonants that read as a syllable (rʉ, lʉ)
+func IsVowelLetter(r rune) bool {
+	return r == 'ฤ' || r == 'ฦ'
+}
+
+// IsVowel reports whether r is a vowel sign (ะ า ิ เ ...), leading vowels
+// included
+func IsVowel(r rune) bool {
+	for _, v := range vowelSigns {
+		if r == v {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLeadingVowel reports whether r is written before its consonant (เ แ
+// โ ใ ไ)
+func IsLeadingVowel(r rune) bool {
+	for _, v := range leadingVowels {
+		if r == v {
+			return true
+		}
+	}
+	return false
+}
+
+// IsToneMark reports whether r is one of the four tone marks
+func IsToneMark(r rune) bool {
+	for _, m := range toneMarks {
+		if r == m {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDigit reports whether r is a Thai digit (๐-๙)
+func IsDigit(r rune) bool {
+	return r >= '๐' && r <= '๙'
+}
+
+// DigitValue returns the value of the Thai digit r, false for other runes
+func DigitValue(r rune) (int, bool) {
+	if !IsDigit(r) {
+		return 0, false
+	}
+	return int(r - '๐'), true
+}
+
+// ClassOf returns the tone class of consonant r, ClassNone for other runes
+func ClassOf(r rune) Class {
+	return classes[r]
+}
+
+// Consonants returns the 44 consonants in alphabetical order
+func Consonants() []rune {
+	letters := make([]rune, len(consonants))
+	for i, c := range consonants {
+		letters[i] = c.letter
+	}
+	return letters
+}
+
+// ConsonantsOfClass returns the consonants of class in alphabetical order
+func ConsonantsOfClass(class Class) []rune {
+	var letters []rune
+	for _, c := range consonants {
+		if c.class == class {
+			letters = append(letters, c.letter)
+		}
+	}
+	return letters
+}
+
+// Vowels returns the vowel signs in Unicode order
+func Vowels() []rune {
+	return []rune(vowelSigns)
+}
+
+// ToneMarks returns the tone marks: mai ek, mai tho, mai tri and mai
+// chattawa
+func ToneMarks() []rune {
+	return append([]rune(nil), toneMarks...)
+}
+
+// InitialSound returns the Paiboon romanization of r at the start of a
+// syllable (ป bp, ฤ rʉ), false for runes that don't start one
+func InitialSound(r rune) (string, bool) {
+	s, ok := initialSounds[r]
+	return s, ok
+}
+
+// FinalSound returns the Paiboon romanization of consonant r closing a
+// syllable (ด t, ร n), false for runes that don't close one
+func FinalSound(r rune) (string, bool) {
+	s, ok := finalSounds[r]
+	return s, ok
+}
+
+// InitialSounds returns a copy of the table of InitialSound, keyed by
+// letter
+func InitialSounds() map[rune]string {
+	return copyTable(initialSounds)
+}
+
+// FinalSounds returns a copy of the table of FinalSound, keyed by letter
+func FinalSounds() map[rune]string {
+	return copyTable(finalSounds)
+}
+
+// copyTable returns a copy of m
+func copyTable(m map[rune]string) map[rune]string {
+	c := make(map[rune]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,282 @@
+package paiboonizer
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/tassa-yoniso-manasi-karoto/paiboonizer/thai"
+)
+
+// TokenKind classifies a token of TransliterateTokens
+type TokenKind int
+
+const (
+	KindWord        TokenKind = iota // Thai word
+	KindParticle                     // Thai particle (ครับ, นะ, สิ)
+	KindNumber                       // Arabic or Thai digits
+	KindPunctuation                  // Punctuation and symbols
+	KindForeign                      // Letters of another script (Latin, ...)
+	KindSpace                        // Whitespace
+	KindMarkup                       // ASS override block or HTML tag
+)
+
+// String returns the kind name
+func (k TokenKind) String() string {
+	switch k {
+	case KindWord:
+		return "word"
+	case KindParticle:
+		return "particle"
+	case KindNumber:
+		return "number"
+	case KindPunctuation:
+		return "punctuation"
+	case KindForeign:
+		return "foreign"
+	case KindSpace:
+		return "space"
+	}
+	return "markup"
+}
+
+// Token is a unit of text with its romanization. Roman is the input text
+// for everything but Thai words and particles, and foreign words, which
+// get what Options.Foreign says (empty when dropped).
+type Token struct {
+	Text  string
+	Roman string
+	Kind  TokenKind
+	// Dropped holds the syllables of Text the rules romanized to nothing,
+	// with their offset in Text; Options.OnFailure sets what replaces them
+	// in Roman
+	Dropped []DroppedSpan
+	// Band is the frequency band of Thai words and particles when
+	// Options.Frequency is set, BandNone otherwise
+	Band FrequencyBand
+	// Gloss is the English gloss of Thai words and particles from
+	// Options.Glosser, empty if it has none
+	Gloss string
+	// EntryID identifies the dictionary entry Roman comes from (see
+	// EntryID), empty for words left to the rules
+	EntryID string
+	// Sensitive is the category of Thai words found in
+	// Options.Sensitive, empty otherwise
+	Sensitive string
+}
+
+// TransliterateTokens runs the TransliterateText pipeline and returns its
+// tokens with their romanization and kind instead of a joined string, so
+// that callers can color or filter the output without analysing it again.
+// Particles are recognized from the vocab files' part of speech and the
+// sentence-final particle list; Particles and PreserveWhitespace don't
+// apply since formatting is left to the caller. Concatenating Text gives
+// back the input, normalized and with its layout typos corrected if opts
+// asks for it.
+func TransliterateTokens(text string, opts Options) []Token {
+	tokens, _ := TransliterateTokensContext(context.Background(), text, opts)
+	return tokens
+}
+
+// TransliterateTokensContext is TransliterateTokens with a context bounding
+// the pythainlp calls of EngineAuto, checked between words. Returns the
+// context's error if it ends before the text is romanized.
+func TransliterateTokensContext(ctx context.Context, text string, opts Options) ([]Token, error) {
+	ensureDictionaryLoaded()
+	if opts.NormalizeInput {
+		text = normalizeInput(text)
+	}
+	if opts.FixLayoutTypos {
+		text = FixLayoutTypos(text)
+	}
+	return romanizeTokens(ctx, tokenizeText(text, opts), opts)
+}
+
+// ClassifyWords labels and romanizes words already split by a tokenizer
+// (pythainlp's newmm, a subtitle editor) the way TransliterateTokens does
+// the words it splits itself: each Thai word is romanized as a whole and
+// labeled KindWord or KindParticle, the rest is labeled KindNumber,
+// KindForeign, KindPunctuation, KindSpace or KindMarkup and kept as is. A
+// word mixing Thai with other characters gives a token per script.
+func ClassifyWords(words []string, opts Options) []Token {
+	tokens, _ := ClassifyWordsContext(context.Background(), words, opts)
+	return tokens
+}
+
+// ClassifyWordsContext is ClassifyWords with a context bounding the
+// pythainlp calls of EngineAuto, checked between words. Returns the
+// context's error if it ends before the words are romanized.
+func ClassifyWordsContext(ctx context.Context, words []string, opts Options) ([]Token, error) {
+	ensureDictionaryLoaded()
+	var tokens []textToken
+	for _, word := range words {
+		if opts.NormalizeInput {
+			word = normalizeInput(word)
+		}
+		for _, run := range splitScripts(word) {
+			if run.kind != tokenThai {
+				tokens = append(tokens, run)
+				continue
+			}
+			// Mai yamok repeats the word before it, even when the
+			// tokenizer leaves them together (ดีๆ)
+			for i, part := range strings.Split(run.text, "ๆ") {
+				if i > 0 {
+					tokens = append(tokens, textToken{text: "ๆ", kind: tokenThai})
+				}
+				tokens = append(tokens, splitThaiNumbers(part)...)
+			}
+		}
+	}
+	return romanizeTokens(ctx, tokens, opts)
+}
+
+// ContainsThai reports whether s has a character of the Thai block, the
+// test the text pipelines use to tell what they romanize
+func ContainsThai(s string) bool {
+	return containsThai(s)
+}
+
+// romanizeTokens turns the tokens of the text pipeline into Tokens,
+// romanizing the Thai ones
+func romanizeTokens(ctx context.Context, tokens []textToken, opts Options) ([]Token, error) {
+	result := []Token{}
+	lastRoman := ""
+	for i, tok := range tokens {
+		switch tok.kind {
+		case tokenSpace:
+			result = append(result, Token{Text: tok.text, Roman: tok.text, Kind: KindSpace})
+		case tokenMarkup:
+			result = append(result, Token{Text: tok.text, Roman: tok.text, Kind: KindMarkup})
+		case tokenOther:
+			for _, t := range classifyOther(tok.text) {
+				if t.Kind == KindForeign {
+					t.Roman = opts.OutputForm.forced(foreignText(t.Text, opts.Foreign))
+				}
+				result = append(result, t)
+			}
+		default:
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			var dropped []DroppedSpan
+			if tok.roman != "" {
+				lastRoman = tok.roman
+			} else if tok.text != "ๆ" {
+				if reduced, ok := connectedSpeechForm(tok.text, nextThaiWord(tokens, i), opts); ok {
+					lastRoman = reduced
+				} else {
+					lastRoman, dropped, _ = transliterateWordDetailed(ctx, tok.text, opts)
+				}
+			}
+			t := Token{Text: tok.text, Roman: lastRoman, Kind: WordKind(tok.text), Dropped: dropped}
+			if tok.text == "ๆ" && lastRoman == "" {
+				// Mai yamok with no word before it to repeat
+				t.Roman = failureText(tok.text, opts.OnFailure)
+				t.Dropped = []DroppedSpan{{tok.text, 0}}
+			}
+			if tok.roman == "" && tok.text != "ๆ" {
+				t.EntryID = entryIDFor(tok.text, opts)
+			}
+			if opts.Frequency != nil && tok.text != "ๆ" {
+				t.Band = opts.Frequency.Band(tok.text)
+			}
+			if opts.Glosser != nil && tok.text != "ๆ" {
+				t.Gloss, _ = opts.Glosser.Gloss(tok.text)
+			}
+			result = append(result, t)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if opts.Sensitive != nil {
+		result = ApplySensitive(result, opts.Sensitive, opts.SensitivePolicy)
+	}
+	return result, nil
+}
+
+// WordKind returns the kind TransliterateTokens gives the Thai word:
+// KindParticle for particles, KindWord otherwise
+func WordKind(word string) TokenKind {
+	if pos, _ := PartOfSpeech(word); pos == "part" || IsSentenceFinalParticle(word) {
+		return KindParticle
+	}
+	return KindWord
+}
+
+// thaiNumberAt returns the rune length of the number in Thai digits (๐-๙)
+// starting at runes[i], a dot or comma between two digits included; 0 if
+// runes[i] isn't a Thai digit
+func thaiNumberAt(runes []rune, i int) int {
+	n := 0
+	for i+n < len(runes) {
+		r := runes[i+n]
+		sep := (r == '.' || r == ',') && n > 0 && i+n+1 < len(runes) && thai.IsDigit(runes[i+n+1])
+		if !thai.IsDigit(r) && !sep {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// splitThaiNumbers splits a Thai run into words and the numbers in Thai
+// digits among them, as tokenOther like in segmentThai
+func splitThaiNumbers(run string) []textToken {
+	runes := []rune(run)
+	var tokens []textToken
+	start := 0
+	for i := 0; i < len(runes); {
+		n := thaiNumberAt(runes, i)
+		if n == 0 {
+			i++
+			continue
+		}
+		if start < i {
+			tokens = append(tokens, textToken{text: string(runes[start:i]), kind: tokenThai})
+		}
+		tokens = append(tokens, textToken{text: string(runes[i : i+n]), kind: tokenOther})
+		i += n
+		start = i
+	}
+	if start < len(runes) {
+		tokens = append(tokens, textToken{text: string(runes[start:]), kind: tokenThai})
+	}
+	return tokens
+}
+
+// classifyOther splits a run of non-Thai text into foreign words, numbers
+// and punctuation. A dot or comma between digits belongs to the number.
+func classifyOther(run string) []Token {
+	runes := []rune(run)
+	kindOf := func(i int) TokenKind {
+		r := runes[i]
+		switch {
+		case unicode.IsDigit(r):
+			return KindNumber
+		case (r == '.' || r == ',') && i > 0 && i+1 < len(runes) && unicode.IsDigit(runes[i-1]) && unicode.IsDigit(runes[i+1]):
+			return KindNumber
+		case unicode.IsLetter(r) || unicode.IsMark(r):
+			return KindForeign
+		}
+		return KindPunctuation
+	}
+
+	tokens := []Token{}
+	var current strings.Builder
+	currentKind := KindPunctuation
+	for i := range runes {
+		kind := kindOf(i)
+		if current.Len() > 0 && kind != currentKind {
+			tokens = append(tokens, Token{Text: current.String(), Roman: current.String(), Kind: currentKind})
+			current.Reset()
+		}
+		currentKind = kind
+		current.WriteRune(runes[i])
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, Token{Text: current.String(), Roman: current.String(), Kind: currentKind})
+	}
+	return tokens
+}
@@ -0,0 +1,109 @@
+package paiboonizer
+
+import (
+	"fmt"
+	"sort"
+)
+
+// leadConsonants maps the silent leading consonants (อักษรนำ) to the
+// consonants they lead. The pair is read as the led consonant with the
+// class of the lead: ห makes a sonorant high (หมา, หลาย) and อ makes ย mid
+// (อยู่, อย่าง). This is a class modifier, not a cluster: the lead is
+// never pronounced.
+var leadConsonants = map[string]map[string]bool{
+	"ห": sonorants,
+	"อ": {"ย": true},
+}
+
+// toneClassTables lists the tone class tables by class name
+func toneClassTables() map[string]map[string]bool {
+	return map[string]map[string]bool{"high": highClass, "mid": midClass, "low": lowClass}
+}
+
+// thaiConsonantLetters returns the 44 Thai consonants in alphabetical
+// order, without the vowel letters ฤ and ฦ
+func thaiConsonantLetters() []string {
+	var letters []string
+	for r := 'ก'; r <= 'ฮ'; r++ {
+		if r != 'ฤ' && r != 'ฦ' {
+			letters = append(letters, string(r))
+		}
+	}
+	return letters
+}
+
+// ConsonantsOfClass returns the consonants of a tone class ("high", "mid"
+// or "low") in alphabetical order, nil for an unknown class
+func ConsonantsOfClass(class string) []string {
+	table, ok := toneClassTables()[class]
+	if !ok {
+		return nil
+	}
+	var letters []string
+	for _, c := range thaiConsonantLetters() {
+		if table[c] {
+			letters = append(letters, c)
+		}
+	}
+	return letters
+}
+
+// LeadConsonants returns the silent leading consonants with the consonants
+// each one leads, sorted (ห: ง ญ น ม ย ร ล ว, อ: ย). See ToneClassOf.
+func LeadConsonants() map[string][]string {
+	leads := make(map[string][]string, len(leadConsonants))
+	for lead, led := range leadConsonants {
+		for c := range led {
+			leads[lead] = append(leads[lead], c)
+		}
+		sort.Strings(leads[lead])
+	}
+	return leads
+}
+
+// CheckToneClasses checks the tone class tables for consistency: every
+// Thai consonant in exactly one class, nothing else in them, and leading
+// consonants of the high or mid class leading low consonants only (a lead
+// only changes the class of a low consonant). Returns the problems found,
+// none for consistent tables.
+func CheckToneClasses() []string {
+	var problems []string
+	tables := toneClassTables()
+	names := []string{"high", "mid", "low"}
+	consonants := make(map[string]bool)
+	for _, c := range thaiConsonantLetters() {
+		consonants[c] = true
+		var classes []string
+		for _, name := range names {
+			if tables[name][c] {
+				classes = append(classes, name)
+			}
+		}
+		switch len(classes) {
+		case 0:
+			problems = append(problems, fmt.Sprintf("%s is in no tone class", c))
+		case 1:
+		default:
+			problems = append(problems, fmt.Sprintf("%s is in several tone classes: %v", c, classes))
+		}
+	}
+	for _, name := range names {
+		for c := range tables[name] {
+			if !consonants[c] {
+				problems = append(problems, fmt.Sprintf("%q in the %s class is not a Thai consonant", c, name))
+			}
+		}
+	}
+	for lead, led := range leadConsonants {
+		if class := toneClassName(lead); class != "high" && class != "mid" {
+			problems = append(problems, fmt.Sprintf("lead %s is not of the high or mid class", lead))
+		}
+		for c := range led {
+			if !lowClass[c] {
+				problems = append(problems, fmt.Sprintf("%s led by %s is not of the low class", c, lead))
+			}
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
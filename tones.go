@@ -0,0 +1,178 @@
+package paiboonizer
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ToneVariant is one tone of a syllable in Thai spelling and Paiboon
+type ToneVariant struct {
+	Tone    string // mid, low, falling, high or rising
+	Thai    string
+	Paiboon string
+}
+
+// thaiToneMarks are the four tone marks, in mai ek to mai chattawa order
+var thaiToneMarks = []string{"่", "้", "๊", "๋"}
+
+// classPairs maps low-class consonants to the high-class consonant with
+// the same sound and back
+var classPairs = map[string]string{
+	"ค": "ข", "ช": "ฉ", "ซ": "ส", "ท": "ถ", "พ": "ผ", "ฟ": "ฝ", "ฮ": "ห",
+	"ข": "ค", "ฉ": "ช", "ส": "ซ", "ถ": "ท", "ผ": "พ", "ฝ": "ฟ", "ห": "ฮ",
+}
+
+// sonorants are the low-class consonants that take a silent ห to behave
+// as high class
+var sonorants = map[string]bool{
+	"ง": true, "ญ": true, "น": true, "ม": true, "ย": true, "ร": true, "ล": true, "ว": true,
+}
+
+// ToneVariants generates the tone minimal pairs of a syllable: the same
+// initial, vowel and final in each of the five tones, e.g. มา gives มา,
+// หม่า, ม่า, ม้า and หมา. Spellings are built by changing the tone mark,
+// adding a silent ห to sonorants and swapping a consonant for its
+// other-class counterpart (ค/ข); the syllable parser decides the tone of
+// each candidate and the first spelling found for a tone is kept. Tones no
+// spelling reaches are left out. Results are in mid, low, falling, high,
+// rising order.
+func ToneVariants(syllable string) []ToneVariant {
+	ensureDictionaryLoaded()
+	syllable = norm.NFC.String(syllable)
+	initial, vowel, final, _, ok := SplitPaiboonSyllable(transliterateSyllable(syllable))
+	if !ok {
+		return nil
+	}
+	// The engine reading of the syllable itself is the most reliable
+	// spelling of its sounds; fall back to the parser's
+	base := ComprehensiveTransliterate(syllable)
+	if _, _, _, _, ok := SplitPaiboonSyllable(base); !ok {
+		base = transliterateSyllable(syllable)
+	}
+
+	found := make(map[string]ToneVariant)
+	for _, candidate := range toneCandidates(syllable) {
+		in, v, f, tone, ok := SplitPaiboonSyllable(transliterateSyllable(candidate))
+		if !ok || in != initial || v != vowel || f != final {
+			continue
+		}
+		if _, seen := found[tone]; seen {
+			continue
+		}
+		found[tone] = ToneVariant{Tone: tone, Thai: candidate, Paiboon: toneVariantReading(candidate, base, tone)}
+	}
+
+	variants := []ToneVariant{}
+	for _, tone := range []string{"mid", "low", "falling", "high", "rising"} {
+		if v, ok := found[tone]; ok {
+			variants = append(variants, v)
+		}
+	}
+	return variants
+}
+
+// toneVariantReading romanizes a generated spelling: known syllables keep
+// their dictionary reading when it agrees on the tone, others get the base
+// reading with the tone mark moved
+func toneVariantReading(candidate, base, tone string) string {
+	trans, ok := syllableDict[candidate]
+	if !ok {
+		trans, ok = LookupDictionary(candidate)
+	}
+	if ok {
+		trans = norm.NFC.String(trans)
+		if _, _, _, t, single := SplitPaiboonSyllable(trans); single && t == tone {
+			return trans
+		}
+	}
+	return retone(base, tone)
+}
+
+// retone replaces the tone mark of a single romanized syllable
+func retone(roman, tone string) string {
+	var mark rune
+	for r, t := range toneByMark {
+		if t == tone {
+			mark = r
+		}
+	}
+	var b strings.Builder
+	placed := false
+	for _, r := range norm.NFD.String(stripRomanTones(roman)) {
+		b.WriteRune(r)
+		if mark != 0 && !placed && isRomanVowel(r) {
+			b.WriteRune(mark)
+			placed = true
+		}
+	}
+	return norm.NFC.String(b.String())
+}
+
+// toneCandidates returns the spellings of syllable with every tone mark
+// and onset variant, the plainest spellings first
+func toneCandidates(syllable string) []string {
+	runes := []rune(stripToneMarks(syllable))
+	lead := 0
+	if lead < len(runes) && isLeadingVowel(string(runes[lead])) {
+		lead++
+	}
+	if lead >= len(runes) || !isConsonantRune(runes[lead]) {
+		return nil
+	}
+	prefix := string(runes[:lead])
+	onset := string(runes[lead])
+	rest := runes[lead+1:]
+
+	// A leading ห or อ, or the second letter of a true cluster, carries
+	// the tone mark together with the first consonant
+	if len(rest) > 0 && isConsonantRune(rest[0]) {
+		pair := onset + string(rest[0])
+		if _, ok := clusters[pair]; ok || onset == "ห" || pair == "อย" {
+			onset = pair
+			rest = rest[1:]
+		}
+	}
+	// The tone mark sits after above and below vowels
+	markAt := 0
+	for markAt < len(rest) && isAboveOrBelowVowel(rest[markAt]) {
+		markAt++
+	}
+	body, tail := string(rest[:markAt]), string(rest[markAt:])
+
+	onsets := []string{onset}
+	first := string([]rune(onset)[0])
+	if len([]rune(onset)) == 1 {
+		if sonorants[onset] {
+			onsets = append(onsets, "ห"+onset)
+		}
+		if other, ok := classPairs[onset]; ok {
+			onsets = append(onsets, other)
+		}
+	} else if first == "ห" && sonorants[string([]rune(onset)[1])] {
+		onsets = append(onsets, string([]rune(onset)[1:]))
+	}
+
+	candidates := []string{}
+	for _, o := range onsets {
+		candidates = append(candidates, prefix+o+body+tail)
+		for _, mark := range thaiToneMarks {
+			// ๊ and ๋ are only written on mid-class consonants
+			if (mark == "๊" || mark == "๋") && !midClass[string([]rune(o)[0])] {
+				continue
+			}
+			candidates = append(candidates, prefix+o+body+mark+tail)
+		}
+	}
+	return candidates
+}
+
+// isAboveOrBelowVowel reports whether r is a vowel written above or below
+// the consonant, before which no tone mark may be placed
+func isAboveOrBelowVowel(r rune) bool {
+	switch r {
+	case 'ั', 'ิ', 'ี', 'ึ', 'ื', 'ุ', 'ู', '็':
+		return true
+	}
+	return false
+}
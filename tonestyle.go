@@ -0,0 +1,62 @@
+package paiboonizer
+
+// ToneStyle selects how the Paiboon output writes tones (see
+// Options.ToneStyle)
+type ToneStyle int
+
+const (
+	TonesAsDiacritics  ToneStyle = iota // Combining marks on the vowel: nǎam. The default.
+	TonesAsSuperscript                  // Superscript tone number after the syllable: naam⁴
+	TonesAsDigits                       // Tone number after the syllable: naam4
+	TonesAsChao                         // Chao tone letters after the syllable: naam˩˦
+)
+
+// String returns the name of the style
+func (s ToneStyle) String() string {
+	switch s {
+	case TonesAsDiacritics:
+		return "diacritics"
+	case TonesAsSuperscript:
+		return "superscript"
+	case TonesAsDigits:
+		return "digits"
+	case TonesAsChao:
+		return "chao"
+	}
+	return "unknown"
+}
+
+// toneStyleSuffixes are the marks written after a syllable by tone, in
+// each style. Numbers are SyllableResult.ToneNumber, so every syllable
+// gets one, 0 for mid.
+var toneStyleSuffixes = map[ToneStyle]map[string]string{
+	TonesAsSuperscript: {"mid": "⁰", "low": "¹", "falling": "²", "high": "³", "rising": "⁴"},
+	TonesAsDigits:      {"mid": "0", "low": "1", "falling": "2", "high": "3", "rising": "4"},
+	TonesAsChao:        {"mid": "˧", "low": "˨˩", "falling": "˥˩", "high": "˦˥", "rising": "˩˦"},
+}
+
+// Apply rewrites the tones of a Paiboon romanization in style s, from the
+// tone of each syllable: the diacritic is dropped and the mark of s
+// written after the syllable. Text in other scripts is left as is.
+func (s ToneStyle) Apply(roman string) string {
+	return s.apply(roman, false)
+}
+
+// apply is Apply, leaving the tones out altogether when toneless: the
+// syllables are written back without a diacritic or a mark after them
+func (s ToneStyle) apply(roman string, toneless bool) string {
+	suffixes, ok := toneStyleSuffixes[s]
+	if !ok && !toneless {
+		return roman
+	}
+	scheme := Scheme{
+		// The Paiboon letters are kept, as letters of the syllable
+		Letters: map[rune]string{'ʉ': "ʉ", 'ɛ': "ɛ", 'ɔ': "ɔ", 'ə': "ə"},
+		Tones:   suffixes,
+		Syllable: func(initial, vowel, final, tone string) string {
+			return initial + vowel + final + suffixes[tone]
+		},
+		Toneless: toneless,
+	}
+	return scheme.Render(roman)
+}
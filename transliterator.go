@@ -0,0 +1,286 @@
+package paiboonizer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// DictionaryUse selects the word dictionaries consulted before the rules
+type DictionaryUse int
+
+const (
+	// DictionaryAll looks words up in the official then the Opus
+	// dictionary, as LookupDictionary does. The default.
+	DictionaryAll DictionaryUse = iota
+	// DictionaryOfficial only trusts the official vocab files
+	DictionaryOfficial
+	// DictionaryNone romanizes every word with the rules, to measure or
+	// debug them. The text pipelines still split words with the
+	// dictionaries.
+	DictionaryNone
+)
+
+// String returns the name of the dictionary use
+func (d DictionaryUse) String() string {
+	switch d {
+	case DictionaryAll:
+		return "all"
+	case DictionaryOfficial:
+		return "official"
+	case DictionaryNone:
+		return "none"
+	}
+	return "unknown"
+}
+
+// lookup returns the romanization of word in the dictionaries d allows
+func (d DictionaryUse) lookup(word string) (string, bool) {
+	switch d {
+	case DictionaryAll:
+		return LookupDictionary(word)
+	case DictionaryOfficial:
+		ensureDictionaryLoaded()
+		trans, ok := dictionary.roman(word)
+		return trans, ok
+	}
+	return "", false
+}
+
+// SyllableEngine selects how the rules split and romanize the words no
+// dictionary knows
+type SyllableEngine int
+
+const (
+	// EngineAuto uses the syllables of pythainlp when the default Manager
+	// has it running and EngineComprehensive otherwise. The default.
+	EngineAuto SyllableEngine = iota
+	// EngineComprehensive matches known syllables maximally and romanizes
+	// the rest with the syllable rules (ComprehensiveTransliterate)
+	EngineComprehensive
+	// EngineSegmenter splits the word with ExtractSyllables and romanizes
+	// each syllable on its own, from the syllable dictionary or the rules
+	EngineSegmenter
+)
+
+// String returns the name of the engine
+func (e SyllableEngine) String() string {
+	switch e {
+	case EngineAuto:
+		return "auto"
+	case EngineComprehensive:
+		return "comprehensive"
+	case EngineSegmenter:
+		return "segmenter"
+	}
+	return "unknown"
+}
+
+// transliterate romanizes a word with the engine; ctx bounds the pythainlp
+// call of EngineAuto
+func (e SyllableEngine) transliterate(ctx context.Context, word string, onFailure FailurePolicy, join JoinPolicy) (string, []DroppedSpan) {
+	switch e {
+	case EngineComprehensive:
+		return comprehensiveTransliterate(word, onFailure, join)
+	case EngineSegmenter:
+		trans, dropped := romanizeSyllables(ExtractSyllables(word), join, onFailure, lookupOrRuleSyllable)
+		return norm.NFC.String(trans), dropped
+	}
+	return autoEngineTransliterate(ctx, word, onFailure, join)
+}
+
+// normalizeInput returns text in NFC without invisible format characters
+// (zero-width spaces and joiners, BOM, soft hyphens), which split Thai
+// words and syllables in text copied from the web or chat apps
+func normalizeInput(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Cf, r) {
+			return -1
+		}
+		return r
+	}, norm.NFC.String(text))
+}
+
+// Transliterator romanizes Thai with a fixed configuration, an alternative
+// to passing Options to the package-level functions. It holds no state
+// besides its Options and the overrides it may reload or edit (see
+// LoadOverridesFile and SetOverride), so it is safe for concurrent use and
+// cheap to create.
+type Transliterator struct {
+	opts      Options
+	overrides atomic.Pointer[overridesFile]
+	// edits serializes the changes to overrides
+	edits sync.Mutex
+	// fingerprint is the OptionsFingerprint of opts, until overrides are
+	// loaded
+	fingerprint     string
+	fingerprintOnce sync.Once
+}
+
+// Option configures a Transliterator
+type Option func(*Transliterator)
+
+// NewTransliterator returns a Transliterator starting from DefaultOptions
+// and applying opts in order
+func NewTransliterator(opts ...Option) *Transliterator {
+	t := &Transliterator{opts: DefaultOptions()}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// NewOfflineTransliterator returns a Transliterator guaranteed never to
+// start or call Docker and pythainlp, whether or not a Manager is running:
+// it only uses the embedded dictionaries and the rules. It starts from
+// DefaultOptions with EngineComprehensive and applies opts in order, and
+// fails with ErrNeedsPythainlp if they select EngineAuto, the only engine
+// that calls pythainlp (a WithOptions with a zero Engine does). The
+// embedded dictionaries are loaded up front so that their errors are
+// returned here rather than panicking later.
+func NewOfflineTransliterator(opts ...Option) (*Transliterator, error) {
+	if err := Load(); err != nil {
+		return nil, err
+	}
+	t := &Transliterator{opts: DefaultOptions()}
+	t.opts.Engine = EngineComprehensive
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.opts.Engine == EngineAuto {
+		return nil, fmt.Errorf("offline transliterator: engine %v: %w", t.opts.Engine, ErrNeedsPythainlp)
+	}
+	return t, nil
+}
+
+// WithOptions replaces the whole configuration; later options adjust it
+func WithOptions(opts Options) Option {
+	return func(t *Transliterator) {
+		t.opts = opts
+	}
+}
+
+// WithDictionary selects the word dictionaries consulted before the rules
+func WithDictionary(use DictionaryUse) Option {
+	return func(t *Transliterator) {
+		t.opts.Dictionary = use
+	}
+}
+
+// WithLayers sets the whole-word tables consulted before the rules, in
+// order (see Options.Layers)
+func WithLayers(layers ...LookupLayer) Option {
+	return func(t *Transliterator) {
+		t.opts.Layers = layers
+	}
+}
+
+// WithEngine selects the syllable engine of the rules
+func WithEngine(engine SyllableEngine) Option {
+	return func(t *Transliterator) {
+		t.opts.Engine = engine
+	}
+}
+
+// WithSyllableJoin selects how the syllables of words left to the rules
+// are separated
+func WithSyllableJoin(policy JoinPolicy) Option {
+	return func(t *Transliterator) {
+		t.opts.Join = policy
+	}
+}
+
+// WithSeparator sets how the syllables and words of the output are
+// separated, dictionary entries included (see Options.Separator)
+func WithSeparator(sep Separator) Option {
+	return func(t *Transliterator) {
+		t.opts.Separator = sep
+	}
+}
+
+// WithNormalization turns input normalization on or off (see
+// Options.NormalizeInput)
+func WithNormalization(on bool) Option {
+	return func(t *Transliterator) {
+		t.opts.NormalizeInput = on
+	}
+}
+
+// Options returns the configuration of t, with the overrides of the file
+// last loaded by LoadOverridesFile if any
+func (t *Transliterator) Options() Options {
+	opts, _ := t.Snapshot()
+	return opts
+}
+
+// Fingerprint returns the OptionsFingerprint of Options, for
+// CacheKeyFingerprint. It is computed when the overrides are loaded,
+// reloaded or edited rather than on every call, so that keying a cache
+// doesn't cost more with a larger overrides file.
+func (t *Transliterator) Fingerprint() string {
+	_, fingerprint := t.Snapshot()
+	return fingerprint
+}
+
+// Snapshot returns Options and Fingerprint as of the same overrides, for
+// hosts caching output while the overrides are edited: called one after
+// the other, they may see an edit in between and key the output of the
+// new overrides under the fingerprint of the old.
+func (t *Transliterator) Snapshot() (Options, string) {
+	opts := t.opts
+	if f := t.overrides.Load(); f != nil {
+		opts.Overrides = f.overrides
+		return opts, f.fingerprint
+	}
+	t.fingerprintOnce.Do(func() {
+		t.fingerprint = OptionsFingerprint(t.opts)
+	})
+	return opts, t.fingerprint
+}
+
+// Word romanizes a single word, as TransliterateWordWithOptions
+func (t *Transliterator) Word(word string) string {
+	return TransliterateWordWithOptions(word, t.Options())
+}
+
+// Text romanizes running text, as TransliterateText
+func (t *Transliterator) Text(text string) string {
+	return TransliterateText(text, t.Options())
+}
+
+// TextDetailed romanizes running text with its dropped spans and warnings,
+// as TransliterateTextDetailed
+func (t *Transliterator) TextDetailed(text string) TextResult {
+	return TransliterateTextDetailed(text, t.Options())
+}
+
+// Tokens romanizes running text into tokens, as TransliterateTokens
+func (t *Transliterator) Tokens(text string) []Token {
+	return TransliterateTokens(text, t.Options())
+}
+
+// WordContext is Word with a context, as TransliterateWordWithOptionsContext
+func (t *Transliterator) WordContext(ctx context.Context, word string) (string, error) {
+	return TransliterateWordWithOptionsContext(ctx, word, t.Options())
+}
+
+// TextContext is Text with a context, as TransliterateTextContext
+func (t *Transliterator) TextContext(ctx context.Context, text string) (string, error) {
+	return TransliterateTextContext(ctx, text, t.Options())
+}
+
+// TextDetailedContext is TextDetailed with a context, as
+// TransliterateTextDetailedContext
+func (t *Transliterator) TextDetailedContext(ctx context.Context, text string) (TextResult, error) {
+	return TransliterateTextDetailedContext(ctx, text, t.Options())
+}
+
+// TokensContext is Tokens with a context, as TransliterateTokensContext
+func (t *Transliterator) TokensContext(ctx context.Context, text string) ([]Token, error) {
+	return TransliterateTokensContext(ctx, text, t.Options())
+}
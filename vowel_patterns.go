@@ -0,0 +1,218 @@
+package paiboonizer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// The vowel pattern table drives improvedTransliterate, the first rule pass
+// of ComprehensiveTransliterate. Patterns loaded at runtime extend it so
+// that rule fixes can be tried without recompiling.
+var (
+	// sortedVowelPatterns is the effective table: built-in and loaded
+	// patterns, longest first then by priority
+	sortedVowelPatterns atomic.Pointer[[]VowelPattern]
+	// extraVowelPatterns are the patterns loaded at runtime, guarded by
+	// vowelPatternsMu
+	extraVowelPatterns []VowelPattern
+	vowelPatternsMu    sync.Mutex
+)
+
+// NewVowelPattern validates and returns a vowel pattern. pattern spells the
+// syllable with placeholders: C a consonant, K an initial cluster (กร, ปล,
+// ...) and T an optional tone mark; every other character must appear as
+// is. The first C or K is the initial and the last later C the final,
+// whose presence must agree with hasFinal. paiboon is the vowel
+// romanization; initial, final and tone are added by the rules. Patterns
+// are tried longest first, then by decreasing priority.
+func NewVowelPattern(pattern, paiboon string, hasFinal bool, priority int) (VowelPattern, error) {
+	pattern = norm.NFC.String(pattern)
+	paiboon = norm.NFC.String(paiboon)
+	placeholders := 0
+	final := false
+	tones := 0
+	for i, r := range pattern {
+		switch {
+		case r == 'C' || r == 'K':
+			placeholders++
+			if r == 'K' && placeholders > 1 {
+				return VowelPattern{}, fmt.Errorf("vowel pattern %q: K is only allowed as the initial", pattern)
+			}
+			final = placeholders > 1
+		case r == 'T':
+			tones++
+			if tones > 1 || placeholders == 0 {
+				return VowelPattern{}, fmt.Errorf("vowel pattern %q: T must follow the initial, once", pattern)
+			}
+		case !isThaiRune(r):
+			return VowelPattern{}, fmt.Errorf("vowel pattern %q: unexpected %q at byte %d", pattern, r, i)
+		}
+	}
+	switch {
+	case placeholders == 0:
+		return VowelPattern{}, fmt.Errorf("vowel pattern %q: no initial (C or K)", pattern)
+	case final != hasFinal:
+		return VowelPattern{}, fmt.Errorf("vowel pattern %q: has final is %v but the pattern says %v", pattern, hasFinal, final)
+	case paiboon == "":
+		return VowelPattern{}, fmt.Errorf("vowel pattern %q: empty romanization", pattern)
+	}
+	for _, r := range paiboon {
+		if !unicode.IsLetter(r) && !unicode.Is(unicode.Mn, r) || isThaiRune(r) {
+			return VowelPattern{}, fmt.Errorf("vowel pattern %q: romanization %q is not a Paiboon vowel", pattern, paiboon)
+		}
+	}
+	return VowelPattern{pattern: pattern, paiboon: paiboon, hasFinal: hasFinal, priority: priority}, nil
+}
+
+// Pattern returns the spelling of the pattern, with its C, K and T placeholders
+func (vp VowelPattern) Pattern() string { return vp.pattern }
+
+// Paiboon returns the vowel romanization
+func (vp VowelPattern) Paiboon() string { return vp.paiboon }
+
+// HasFinal reports whether the pattern has a final consonant
+func (vp VowelPattern) HasFinal() bool { return vp.hasFinal }
+
+// Priority returns the priority among patterns of the same length
+func (vp VowelPattern) Priority() int { return vp.priority }
+
+// ExtendVowelPatterns adds patterns to the table used by the rules. A
+// pattern spelled like a built-in or previously added one replaces it;
+// among patterns of the same length and priority, added ones are tried
+// before built-in ones. Meant for experiments: the output changes, and so
+// does CacheKey.
+func ExtendVowelPatterns(patterns ...VowelPattern) {
+	vowelPatternsMu.Lock()
+	defer vowelPatternsMu.Unlock()
+	replaced := make(map[string]bool, len(patterns))
+	for _, p := range patterns {
+		replaced[p.pattern] = true
+	}
+	var extra []VowelPattern
+	for _, p := range extraVowelPatterns {
+		if !replaced[p.pattern] {
+			extra = append(extra, p)
+		}
+	}
+	extraVowelPatterns = append(extra, patterns...)
+	rebuildVowelPatterns()
+}
+
+// ResetVowelPatterns drops the patterns added by ExtendVowelPatterns and
+// LoadVowelPatterns
+func ResetVowelPatterns() {
+	vowelPatternsMu.Lock()
+	defer vowelPatternsMu.Unlock()
+	extraVowelPatterns = nil
+	rebuildVowelPatterns()
+}
+
+// rebuildVowelPatterns merges the added patterns into the built-in table.
+// The built-in order is kept as is, so that an empty extension gives the
+// built-in table back. Callers hold vowelPatternsMu.
+func rebuildVowelPatterns() {
+	extra := append([]VowelPattern(nil), extraVowelPatterns...)
+	sort.SliceStable(extra, func(i, j int) bool { return vowelPatternLess(extra[i], extra[j]) })
+	replaced := make(map[string]bool, len(extra))
+	for _, p := range extra {
+		replaced[p.pattern] = true
+	}
+	table := make([]VowelPattern, 0, len(builtinVowelPatterns)+len(extra))
+	for _, p := range builtinVowelPatterns {
+		if replaced[p.pattern] {
+			continue
+		}
+		for len(extra) > 0 && !vowelPatternLess(p, extra[0]) {
+			table = append(table, extra[0])
+			extra = extra[1:]
+		}
+		table = append(table, p)
+	}
+	table = append(table, extra...)
+	sortedVowelPatterns.Store(&table)
+}
+
+// VowelPatterns returns the effective vowel pattern table in the order the
+// rules try it
+func VowelPatterns() []VowelPattern {
+	return append([]VowelPattern(nil), *sortedVowelPatterns.Load()...)
+}
+
+// LoadVowelPatterns reads patterns in the format of WriteVowelPatterns and
+// adds them with ExtendVowelPatterns. Nothing is added if any line is
+// invalid; the error lists every invalid line.
+func LoadVowelPatterns(r io.Reader) error {
+	var patterns []VowelPattern
+	var errs []error
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			errs = append(errs, fmt.Errorf("line %d: %d fields, want pattern, romanization, final and priority", n, len(fields)))
+			continue
+		}
+		hasFinal, err := strconv.ParseBool(fields[2])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: final: %w", n, err))
+			continue
+		}
+		priority, err := strconv.Atoi(fields[3])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: priority: %w", n, err))
+			continue
+		}
+		p, err := NewVowelPattern(fields[0], fields[1], hasFinal, priority)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", n, err))
+			continue
+		}
+		patterns = append(patterns, p)
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	ExtendVowelPatterns(patterns...)
+	return nil
+}
+
+// WriteVowelPatterns writes the effective table as tab-separated pattern,
+// romanization, final (true/false) and priority lines, in the order the
+// rules try it. The output can be edited and read back by
+// LoadVowelPatterns.
+func WriteVowelPatterns(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# pattern\tromanization\tfinal\tpriority")
+	for _, p := range VowelPatterns() {
+		fmt.Fprintf(bw, "%s\t%s\t%v\t%d\n", p.pattern, p.paiboon, p.hasFinal, p.priority)
+	}
+	return bw.Flush()
+}
+
+// vowelPatternsFingerprint identifies the patterns added at runtime, ""
+// when there are none
+func vowelPatternsFingerprint() string {
+	vowelPatternsMu.Lock()
+	defer vowelPatternsMu.Unlock()
+	var sb strings.Builder
+	for _, p := range extraVowelPatterns {
+		fmt.Fprintf(&sb, "%s\t%s\t%v\t%d\n", p.pattern, p.paiboon, p.hasFinal, p.priority)
+	}
+	return sb.String()
+}
@@ -0,0 +1,103 @@
+package paiboonizer
+
+import "fmt"
+
+// WarningKind classifies a Warning
+type WarningKind int
+
+const (
+	WarnUnknownWord       WarningKind = iota // Word in no dictionary, romanized by the rules
+	WarnPythainlpFallback                    // pythainlp unavailable or too slow, internal segmentation used
+	WarnSuspiciousInput                      // Thai spelled in a way no word is (stray or doubled marks)
+	WarnDroppedSpan                          // Thai the rules romanized to nothing (see DroppedSpan)
+	WarnLayoutTypo                           // Thai typed with the wrong keyboard layout, read as Thai (see FixLayoutTypos)
+)
+
+// String returns the kind name
+func (k WarningKind) String() string {
+	switch k {
+	case WarnUnknownWord:
+		return "unknown word"
+	case WarnPythainlpFallback:
+		return "pythainlp fallback"
+	case WarnSuspiciousInput:
+		return "suspicious input"
+	case WarnLayoutTypo:
+		return "layout typo"
+	}
+	return "dropped span"
+}
+
+// Warning reports something about a romanization worth monitoring: its
+// output is less reliable than usual but nothing failed
+type Warning struct {
+	Kind    WarningKind
+	Text    string // Input concerned, "" when the warning is about the whole text
+	Offset  int    // Position of Text in the input, in runes
+	Message string
+}
+
+// String returns the warning on one line
+func (w Warning) String() string {
+	if w.Text == "" {
+		return fmt.Sprintf("%s: %s", w.Kind, w.Message)
+	}
+	return fmt.Sprintf("%s %q at %d: %s", w.Kind, w.Text, w.Offset, w.Message)
+}
+
+// TextResult is the romanization of a text with what is known about its
+// reliability
+type TextResult struct {
+	Roman string
+	// Dropped holds the Thai the rules romanized to nothing, with its
+	// offset in the text; Options.OnFailure sets what replaces it in Roman
+	Dropped  []DroppedSpan
+	Warnings []Warning
+	// Alignment maps each word and non-Thai run of the text to its part
+	// of Roman, in text order
+	Alignment []AlignedSpan
+}
+
+// droppedWarnings turns dropped spans into warnings
+func droppedWarnings(dropped []DroppedSpan) []Warning {
+	var warnings []Warning
+	for _, d := range dropped {
+		warnings = append(warnings, Warning{WarnDroppedSpan, d.Text, d.Offset, "romanized to nothing"})
+	}
+	return warnings
+}
+
+// suspiciousInput reports the spellings of text that no Thai word has,
+// usually typing or OCR errors the rules will read wrong: combining marks
+// with no consonant to sit on, marks typed twice, two tone marks on one
+// consonant and leading vowels with no consonant after them
+func suspiciousInput(text string) []Warning {
+	var warnings []Warning
+	runes := []rune(text)
+	warn := func(i int, msg string) {
+		warnings = append(warnings, Warning{WarnSuspiciousInput, string(runes[i]), i, msg})
+	}
+	for i, r := range runes {
+		var prev rune
+		if i > 0 {
+			prev = runes[i-1]
+		}
+		switch {
+		case isCombiningMark(r) && r == prev:
+			warn(i, "mark typed twice")
+		case isCombiningMark(r) && !isConsonantRune(prev) && !isCombiningMark(prev):
+			warn(i, "mark with no consonant")
+		case isToneMark(string(r)) && isToneMark(string(prev)):
+			warn(i, "two tone marks")
+		case isLeadingVowel(string(r)) && (i+1 == len(runes) || !isConsonantRune(runes[i+1])):
+			warn(i, "leading vowel with no consonant")
+		}
+	}
+	return warnings
+}
+
+// isCombiningMark reports whether r is a Thai vowel, tone mark or sign
+// written above or below a consonant
+func isCombiningMark(r rune) bool {
+	return isAboveOrBelowVowel(r) || isToneMark(string(r)) || r == '์' || r == 'ฺ' || r == 'ํ'
+}